@@ -1,17 +1,68 @@
 package main
 
 import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/kelompok1-swe-academya/caper-be/internal/infra/database"
 	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
 	"github.com/kelompok1-swe-academya/caper-be/internal/infra/server"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
 )
 
+const defaultShutdownTimeout = 10 * time.Second
+
 func main() {
+	if env.AppEnv.DBMigrateOnBoot {
+		if err := database.RunMigrations(); err != nil {
+			log.Fatal(log.LogInfo{
+				"error": err.Error(),
+			}, "[MAIN][main] failed to migrate database on boot")
+		}
+	}
+
 	server := server.NewHttpServer()
 	psqlDB := database.NewPgsqlConn()
-	defer psqlDB.Close()
 
 	server.MountMiddlewares()
 	server.MountRoutes(psqlDB)
-	server.Start(env.AppEnv.AppPort)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go server.Start(env.AppEnv.AppPort)
+
+	<-ctx.Done()
+	// a second signal kills the process right away instead of waiting for the drain
+	stop()
+
+	timeout := env.AppEnv.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+
+	log.Info(log.LogInfo{
+		"timeout": timeout.String(),
+	}, "[MAIN][main] shutting down")
+
+	if err := server.Shutdown(timeout); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[MAIN][main] server did not shut down cleanly")
+	}
+
+	if err := psqlDB.Close(); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[MAIN][main] failed to close database connection")
+	}
+
+	log.Info(nil, "[MAIN][main] shutdown complete")
+
+	if err := log.Close(); err != nil {
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/database"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+const migrationsPath = "database/migrations/"
+
+const usage = `usage: migrate <command> [arg]
+
+commands:
+  up [N]       apply all or N pending migrations
+  down [N]     roll back N migrations (default 1)
+  goto V       migrate up or down to version V
+  force V      set version V without running migrations, clearing the dirty flag
+  status       print the current version and dirty flag
+  create NAME  create a new pair of up and down migration files`
+
+var migrationFileRegex = regexp.MustCompile(`^(\d+)_.*\.(up|down)\.sql$`)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, usage)
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	command, arg := args[0], ""
+	if len(args) > 1 {
+		arg = args[1]
+	}
+
+	if command == "create" {
+		create(arg)
+		return
+	}
+
+	migration, err := database.NewMigration(database.NewPgsqlConn())
+	if err != nil {
+		os.Exit(1)
+	}
+
+	err = run(migration, command, arg)
+	migration.Close()
+
+	if errors.Is(err, migrate.ErrNoChange) {
+		log.Info(nil, "[MIGRATE][main] no change")
+		return
+	}
+
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"command": command,
+		}, "[MIGRATE][main] failed to run migration command")
+		os.Exit(1)
+	}
+}
+
+func run(migration *migrate.Migrate, command string, arg string) error {
+	switch command {
+	case "up":
+		if arg == "" {
+			return migration.Up()
+		}
+
+		steps, err := parsePositive(arg)
+		if err != nil {
+			return err
+		}
+
+		return migration.Steps(steps)
+	case "down":
+		steps := 1
+		if arg != "" {
+			var err error
+			if steps, err = parsePositive(arg); err != nil {
+				return err
+			}
+		}
+
+		return migration.Steps(-steps)
+	case "goto":
+		version, err := parsePositive(arg)
+		if err != nil {
+			return err
+		}
+
+		return migration.Migrate(uint(version))
+	case "force":
+		version, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("invalid version %q", arg)
+		}
+
+		return migration.Force(version)
+	case "status":
+		version, dirty, err := migration.Version()
+		if errors.Is(err, migrate.ErrNilVersion) {
+			log.Info(nil, "[MIGRATE][status] no migration has been applied")
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		log.Info(log.LogInfo{
+			"version": version,
+			"dirty":   dirty,
+		}, "[MIGRATE][status] current migration version")
+
+		return nil
+	default:
+		flag.Usage()
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
+
+func create(name string) {
+	if name == "" {
+		log.Fatal(nil, "[MIGRATE][create] migration name is required")
+	}
+
+	entries, err := os.ReadDir(migrationsPath)
+	if err != nil {
+		log.Fatal(log.LogInfo{
+			"error": err.Error(),
+		}, "[MIGRATE][create] failed to read migrations directory")
+	}
+
+	next := 1
+	for _, entry := range entries {
+		matches := migrationFileRegex.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		if version, _ := strconv.Atoi(matches[1]); version >= next {
+			next = version + 1
+		}
+	}
+
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(migrationsPath, fmt.Sprintf("%06d_%s.%s.sql", next, name, direction))
+		if err := os.WriteFile(path, nil, 0o644); err != nil { //nolint:gosec // migration files are not secret
+			log.Fatal(log.LogInfo{
+				"error": err.Error(),
+				"path":  path,
+			}, "[MIGRATE][create] failed to create migration file")
+		}
+
+		log.Info(log.LogInfo{
+			"path": path,
+		}, "[MIGRATE][create] created migration file")
+	}
+}
+
+func parsePositive(arg string) (int, error) {
+	value, err := strconv.Atoi(arg)
+	if err != nil || value < 1 {
+		return 0, fmt.Errorf("expected a positive number, got %q", arg)
+	}
+
+	return value, nil
+}
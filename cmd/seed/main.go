@@ -1,25 +1,191 @@
 package main
 
 import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
 	"github.com/kelompok1-swe-academya/caper-be/internal/infra/database"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/bcrypt"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers/flag"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
 )
 
 const SeedersFilePath = "data/seeders/"
 const SeedersDevPath = SeedersFilePath + "dev/"
 const SeedersProdPath = SeedersFilePath + "prod/"
 
+type seeder struct {
+	entity string
+	seed   func(ctx context.Context, tx *sqlx.Tx, rows []map[string]string) error
+}
+
+type dependencies struct {
+	validator validator.ValidatorInterface
+	uuid      uuid.UUIDInterface
+	bcrypt    bcrypt.BcryptInterface
+}
+
 func main() {
 	psqlDB := database.NewPgsqlConn()
 	defer psqlDB.Close()
 
-	// var path string
-	// if env.AppEnv.AppEnv == "production" {
-	// 	path = SeedersProdPath
-	// } else {
-	// 	path = SeedersDevPath
-	// }
+	var path string
+	if env.AppEnv.AppEnv == "production" {
+		path = SeedersProdPath
+	} else {
+		path = SeedersDevPath
+	}
+
+	deps := dependencies{
+		validator: validator.Validator,
+		uuid:      uuid.UUID,
+		bcrypt:    bcrypt.Bcrypt,
+	}
+
+	// seeders run in this order no matter how -entity lists them, so rows
+	// referencing other entities always find them
+	seeders := []seeder{
+		{entity: "roles", seed: deps.seedRoles},
+		{entity: "users", seed: deps.seedUsers},
+	}
+
+	selected, err := selectSeeders(seeders, flag.FlagVars.Entity)
+	if err != nil {
+		log.Fatal(log.LogInfo{
+			"error": err.Error(),
+		}, "[SEED][main] invalid entity flag")
+	}
+
+	if err := run(context.Background(), psqlDB, path, selected); err != nil {
+		log.Fatal(log.LogInfo{
+			"error": err.Error(),
+		}, "[SEED][main] failed to seed database")
+	}
+
+	log.Info(log.LogInfo{
+		"path": path,
+	}, "[SEED][main] database seeded")
+}
+
+func selectSeeders(seeders []seeder, entityFlag string) ([]seeder, error) {
+	if entityFlag == "" || entityFlag == "all" {
+		return seeders, nil
+	}
+
+	requested := make(map[string]bool)
+	for _, entity := range strings.Split(entityFlag, ",") {
+		requested[strings.TrimSpace(entity)] = true
+	}
+
+	selected := make([]seeder, 0, len(requested))
+	for _, seeder := range seeders {
+		if requested[seeder.entity] {
+			selected = append(selected, seeder)
+			delete(requested, seeder.entity)
+		}
+	}
+
+	if len(requested) > 0 {
+		unknown := make([]string, 0, len(requested))
+		for entity := range requested {
+			unknown = append(unknown, entity)
+		}
+
+		return nil, errors.New("unknown entity " + strings.Join(unknown, ","))
+	}
+
+	return selected, nil
+}
+
+// run seeds every selected entity inside a single transaction so a bad row
+// leaves the database untouched
+func run(ctx context.Context, db *sqlx.DB, path string, seeders []seeder) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	for _, seeder := range seeders {
+		filePath := path + seeder.entity + ".csv"
+
+		rows, err := readCSV(filePath)
+		if errors.Is(err, os.ErrNotExist) {
+			log.Warn(log.LogInfo{
+				"file": filePath,
+			}, "[SEED][run] seeder file not found, skipping")
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := seeder.seed(ctx, tx, rows); err != nil {
+			log.Error(log.LogInfo{
+				"error":  err.Error(),
+				"entity": seeder.entity,
+			}, "[SEED][run] failed to seed entity")
+			return err
+		}
+
+		log.Info(log.LogInfo{
+			"entity": seeder.entity,
+			"rows":   len(rows),
+		}, "[SEED][run] entity seeded")
+	}
+
+	return tx.Commit()
+}
+
+// readCSV maps every record to its header row, so column order in the file
+// doesn't matter
+func readCSV(filePath string) ([]map[string]string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	headers, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, header := range headers {
+		headers[i] = strings.TrimSpace(header)
+	}
+
+	rows := make([]map[string]string, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]string, len(headers))
+		for i, header := range headers {
+			row[header] = strings.TrimSpace(record[i])
+		}
+
+		rows = append(rows, row)
+	}
 
-	// validator := validator.Validator
-	// uuid := uuid.UUID
-	// bcrypt := bcrypt.Bcrypt
+	return rows, nil
 }
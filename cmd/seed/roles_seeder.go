@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type roleSeed struct {
+	ID             int    `validate:"required,min=1"`
+	Name           string `validate:"required,min=3,max=255"`
+	InheritsRoleID *int   `validate:"omitempty,min=1"`
+}
+
+func (d dependencies) seedRoles(ctx context.Context, tx *sqlx.Tx, rows []map[string]string) error {
+	query := `
+		INSERT INTO roles (id, name, inherits_role_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO UPDATE
+		SET name = EXCLUDED.name, inherits_role_id = EXCLUDED.inherits_role_id
+	`
+
+	for i, row := range rows {
+		id, err := strconv.Atoi(row["id"])
+		if err != nil {
+			return fmt.Errorf("row %d: invalid id %q", i+1, row["id"])
+		}
+
+		role := roleSeed{
+			ID:   id,
+			Name: row["name"],
+		}
+
+		if row["inherits_role_id"] != "" {
+			inheritsRoleID, err := strconv.Atoi(row["inherits_role_id"])
+			if err != nil {
+				return fmt.Errorf("row %d: invalid inherits_role_id %q", i+1, row["inherits_role_id"])
+			}
+
+			role.InheritsRoleID = &inheritsRoleID
+		}
+
+		if valErr := d.validator.Validate(role); valErr != nil {
+			return fmt.Errorf("row %d: %w", i+1, valErr)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, role.ID, role.Name, role.InheritsRoleID); err != nil {
+			return fmt.Errorf("row %d: %w", i+1, err)
+		}
+	}
+
+	// explicit ids don't advance the serial, keep it ahead for roles created later
+	_, err := tx.ExecContext(ctx, `SELECT setval(pg_get_serial_sequence('roles', 'id'), (SELECT MAX(id) FROM roles))`)
+
+	return err
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type userSeed struct {
+	Name     string `validate:"required,min=3,max=255"`
+	Email    string `validate:"required,email,max=255"`
+	Password string `validate:"required,min=8,max=72"`
+	RoleID   int    `validate:"required,min=1"`
+}
+
+// seedUsers only sets the password when it creates a user, reseeding must not
+// undo a password that was changed in a deployed environment
+func (d dependencies) seedUsers(ctx context.Context, tx *sqlx.Tx, rows []map[string]string) error {
+	query := `
+		INSERT INTO users (id, name, email, password, role_id, email_verified_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (email) DO UPDATE
+		SET name = EXCLUDED.name,
+			role_id = EXCLUDED.role_id,
+			email_verified_at = COALESCE(users.email_verified_at, EXCLUDED.email_verified_at),
+			updated_at = NOW()
+	`
+
+	for i, row := range rows {
+		roleID, err := strconv.Atoi(row["role_id"])
+		if err != nil {
+			return fmt.Errorf("row %d: invalid role_id %q", i+1, row["role_id"])
+		}
+
+		user := userSeed{
+			Name:     row["name"],
+			Email:    strings.ToLower(row["email"]),
+			Password: row["password"],
+			RoleID:   roleID,
+		}
+
+		if valErr := d.validator.Validate(user); valErr != nil {
+			return fmt.Errorf("row %d: %w", i+1, valErr)
+		}
+
+		id, err := d.uuid.NewV7()
+		if err != nil {
+			return err
+		}
+
+		hashedPassword, err := d.bcrypt.Hash(user.Password)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, query, id, user.Name, user.Email, hashedPassword, user.RoleID)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS roles (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR(255) NOT NULL UNIQUE,
+  inherits_role_id INT4 NULL,
+  CONSTRAINT fk_role_inherits_role FOREIGN KEY (inherits_role_id) REFERENCES roles(id) ON DELETE SET NULL
+);
+
+-- users.role_id defaults to 4, so the base roles keep fixed ids
+INSERT INTO roles (id, name, inherits_role_id) VALUES
+  (4, 'member', NULL),
+  (3, 'moderator', 4),
+  (2, 'admin', 3),
+  (1, 'superadmin', 2)
+ON CONFLICT (id) DO NOTHING;
+
+SELECT setval(pg_get_serial_sequence('roles', 'id'), (SELECT MAX(id) FROM roles));
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+  jti VARCHAR(64) PRIMARY KEY,
+  user_id UUID NOT NULL,
+  expires_at TIMESTAMP NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT fk_revoked_token_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS user_token_revocations (
+  user_id UUID PRIMARY KEY,
+  revoked_before TIMESTAMP NOT NULL,
+  expires_at TIMESTAMP NOT NULL,
+  CONSTRAINT fk_user_token_revocation_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS oauth_states (
+  id UUID PRIMARY KEY,
+  state_hash VARCHAR(64) NOT NULL UNIQUE,
+  nonce VARCHAR(255) NOT NULL,
+  code_verifier VARCHAR(255) NOT NULL,
+  expires_at TIMESTAMP NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS user_identities (
+  id UUID PRIMARY KEY,
+  user_id UUID NOT NULL,
+  provider VARCHAR(50) NOT NULL,
+  subject VARCHAR(255) NOT NULL,
+  email VARCHAR(255) NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT uq_user_identity_provider_subject UNIQUE (provider, subject),
+  CONSTRAINT fk_user_identity_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities (user_id);
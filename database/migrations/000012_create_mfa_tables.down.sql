@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS mfa_challenges;
+
+DROP TABLE IF EXISTS mfa_recovery_codes;
+
+DROP TABLE IF EXISTS user_totp;
+
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS amr;
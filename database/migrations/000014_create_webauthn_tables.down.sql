@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS webauthn_sessions;
+
+DROP TABLE IF EXISTS webauthn_credentials;
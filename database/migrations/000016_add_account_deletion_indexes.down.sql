@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_mail_outbox_recipient;
+
+DROP INDEX IF EXISTS idx_users_deleted_at;
@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_mail_outbox_recipient ON mail_outbox (recipient);
@@ -0,0 +1,2 @@
+-- cleared bodies can't be restored, only the index is dropped
+DROP INDEX IF EXISTS idx_mail_outbox_finished;
@@ -0,0 +1,5 @@
+-- delivered and failed mails no longer keep their bodies, they carried working
+-- reset, verification and sign-in links
+UPDATE mail_outbox SET text_body = '', html_body = '' WHERE status <> 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_mail_outbox_finished ON mail_outbox (created_at) WHERE status <> 'pending';
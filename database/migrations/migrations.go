@@ -0,0 +1,9 @@
+package migrations
+
+import "embed"
+
+// FS holds every *.sql file in this directory so binaries can migrate
+// without the files being shipped next to them.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,22 @@
+package contracts
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+)
+
+type EmailChangeRepository interface {
+	Create(ctx context.Context, token *entity.EmailChangeToken) error
+	InvalidateByUserID(ctx context.Context, userID uuid.UUID) error
+	Consume(ctx context.Context, tokenHash string, now time.Time) (entity.EmailChangeToken, error)
+}
+
+type EmailChangeService interface {
+	Request(ctx context.Context, user entity.User, email string) error
+	Confirm(ctx context.Context, req dto.ConfirmEmailChangeRequest) error
+}
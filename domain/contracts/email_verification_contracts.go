@@ -0,0 +1,23 @@
+package contracts
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+)
+
+type EmailVerificationRepository interface {
+	Create(ctx context.Context, token *entity.EmailVerificationToken) error
+	InvalidateByUserID(ctx context.Context, userID uuid.UUID) error
+	Consume(ctx context.Context, tokenHash string, now time.Time) (entity.EmailVerificationToken, error)
+}
+
+type EmailVerificationService interface {
+	Send(ctx context.Context, user entity.User) error
+	Resend(ctx context.Context, userID uuid.UUID) error
+	Verify(ctx context.Context, req dto.VerifyEmailRequest) error
+}
@@ -0,0 +1,24 @@
+package contracts
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LoginThrottleRepository interface {
+	RecordFailure(ctx context.Context, throttleKey string, now time.Time, since time.Time) (int, error)
+	Lock(ctx context.Context, throttleKey string, until time.Time) error
+	FindLockedUntil(ctx context.Context, now time.Time, throttleKeys ...string) (time.Time, error)
+	Reset(ctx context.Context, throttleKey string) error
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
+
+type LoginThrottleService interface {
+	Check(ctx context.Context, email string, ip string) error
+	RecordFailure(ctx context.Context, email string, ip string) error
+	RecordSuccess(ctx context.Context, email string) error
+	Unlock(ctx context.Context, userID uuid.UUID) error
+	DeleteExpired(ctx context.Context) error
+}
@@ -0,0 +1,23 @@
+package contracts
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+)
+
+type MagicLinkRepository interface {
+	Create(ctx context.Context, token *entity.MagicLinkToken) error
+	InvalidateByUserID(ctx context.Context, userID uuid.UUID) error
+	Consume(ctx context.Context, tokenHash string, now time.Time) (entity.MagicLinkToken, error)
+	DeleteExpired(ctx context.Context) error
+}
+
+type MagicLinkService interface {
+	Send(ctx context.Context, req dto.MagicLinkRequest) error
+	Login(ctx context.Context, req dto.MagicLinkLoginRequest) (dto.LoginResponse, error)
+}
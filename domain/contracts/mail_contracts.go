@@ -0,0 +1,29 @@
+package contracts
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+)
+
+// MailOutboxRepository stores outgoing mails until a worker delivers them.
+// Claim leases due mails by pushing next_attempt_at to leaseUntil, so
+// replicas polling at the same time never pick up the same mail.
+// DeleteFinished removes sent and failed mails created before the given time.
+type MailOutboxRepository interface {
+	Create(ctx context.Context, mail *entity.MailOutbox) error
+	Claim(ctx context.Context, limit int, now time.Time, leaseUntil time.Time) ([]entity.MailOutbox, error)
+	MarkSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error
+	MarkRetry(ctx context.Context, id uuid.UUID, lastError string, nextAttemptAt time.Time) error
+	MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error
+	DeleteFinished(ctx context.Context, before time.Time) error
+}
+
+type MailService interface {
+	Queue(ctx context.Context, to string, locale string, template string, data any) error
+	ProcessOutbox(ctx context.Context) error
+	PurgeFinished(ctx context.Context) error
+}
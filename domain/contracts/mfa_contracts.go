@@ -0,0 +1,40 @@
+package contracts
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+)
+
+type MFARepository interface {
+	FindTotp(ctx context.Context, userID uuid.UUID) (entity.UserTotp, error)
+	SaveTotp(ctx context.Context, totp *entity.UserTotp) error
+	ConfirmTotp(ctx context.Context, userID uuid.UUID, step int64, now time.Time) error
+	UseTotpStep(ctx context.Context, userID uuid.UUID, step int64) error
+	DeleteTotp(ctx context.Context, userID uuid.UUID) error
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codes []entity.MFARecoveryCode) error
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string, now time.Time) error
+	CreateChallenge(ctx context.Context, challenge *entity.MFAChallenge) error
+	FindChallenge(ctx context.Context, tokenHash string, now time.Time) (entity.MFAChallenge, error)
+	IncrementChallengeAttempts(ctx context.Context, id uuid.UUID) (int, error)
+	DeleteChallenge(ctx context.Context, id uuid.UUID) error
+	DeleteExpiredChallenges(ctx context.Context) error
+}
+
+type MFAService interface {
+	SignIn(ctx context.Context, user entity.User, amr []string) (dto.LoginResponse, error)
+	Verify(ctx context.Context, req dto.MFAVerifyRequest) (dto.LoginResponse, error)
+	EnrollTotp(ctx context.Context, userID uuid.UUID) (dto.TotpEnrollResponse, error)
+	ConfirmTotp(ctx context.Context, userID uuid.UUID, req dto.TotpCodeRequest) (dto.RecoveryCodesResponse, error)
+	DisableTotp(ctx context.Context, userID uuid.UUID, req dto.MFAStepUpRequest) error
+	RegenerateRecoveryCodes(
+		ctx context.Context,
+		userID uuid.UUID,
+		req dto.MFAStepUpRequest,
+	) (dto.RecoveryCodesResponse, error)
+	Reauthenticate(ctx context.Context, userID uuid.UUID, req dto.ReauthRequest) error
+}
@@ -0,0 +1,22 @@
+package contracts
+
+import (
+	"context"
+	"time"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+)
+
+type OAuthRepository interface {
+	CreateState(ctx context.Context, state *entity.OAuthState) error
+	ConsumeState(ctx context.Context, stateHash string, now time.Time) (entity.OAuthState, error)
+	DeleteExpiredStates(ctx context.Context) error
+	FindIdentity(ctx context.Context, provider string, subject string) (entity.UserIdentity, error)
+	CreateIdentity(ctx context.Context, identity *entity.UserIdentity) error
+}
+
+type OAuthService interface {
+	GoogleLogin(ctx context.Context) (dto.OAuthLoginResponse, error)
+	GoogleCallback(ctx context.Context, req dto.OAuthCallbackRequest) (dto.LoginResponse, error)
+}
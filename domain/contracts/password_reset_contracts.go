@@ -0,0 +1,27 @@
+package contracts
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+)
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *entity.PasswordResetToken) error
+	InvalidateByUserID(ctx context.Context, userID uuid.UUID) error
+	ResetPassword(
+		ctx context.Context,
+		tokenHash string,
+		password string,
+		now time.Time,
+	) (entity.PasswordResetToken, error)
+}
+
+type PasswordResetService interface {
+	ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) error
+}
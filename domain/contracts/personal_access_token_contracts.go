@@ -0,0 +1,34 @@
+package contracts
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
+)
+
+type PersonalAccessTokenRepository interface {
+	Create(ctx context.Context, token *entity.PersonalAccessToken) error
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]entity.PersonalAccessToken, error)
+	FindByHash(ctx context.Context, tokenHash string) (entity.PersonalAccessToken, error)
+	TouchLastUsed(ctx context.Context, id uuid.UUID, ip string, now time.Time) error
+	Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+	DeleteExpired(ctx context.Context) error
+}
+
+type PersonalAccessTokenService interface {
+	Create(
+		ctx context.Context,
+		userID uuid.UUID,
+		req dto.CreatePersonalAccessTokenRequest,
+	) (dto.CreatePersonalAccessTokenResponse, error)
+	List(ctx context.Context, userID uuid.UUID) ([]dto.PersonalAccessTokenResponse, error)
+	Revoke(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+	// Authenticate turns a personal access token into the claims a jwt would
+	// carry, with the scopes of the token attached
+	Authenticate(ctx context.Context, token string, ip string) (jwt.Claims, error)
+}
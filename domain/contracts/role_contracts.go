@@ -0,0 +1,35 @@
+package contracts
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+)
+
+type RoleRepository interface {
+	FindAll(ctx context.Context) ([]entity.Role, error)
+	FindByID(ctx context.Context, id int) (entity.Role, error)
+	Create(ctx context.Context, role *entity.Role) error
+	FindImpliedRoles(ctx context.Context, roleName string) ([]entity.Role, error)
+	FindPermissionsByRoleName(ctx context.Context, roleName string) ([]entity.Permission, error)
+	FindAllPermissions(ctx context.Context) ([]entity.Permission, error)
+	FindPermissionByID(ctx context.Context, id int) (entity.Permission, error)
+	CreatePermission(ctx context.Context, permission *entity.Permission) error
+	AttachPermission(ctx context.Context, roleID int, permissionID int) error
+	DetachPermission(ctx context.Context, roleID int, permissionID int) error
+}
+
+type RoleService interface {
+	HasAnyRole(ctx context.Context, roleName string, required ...string) (bool, error)
+	HasAllPermissions(ctx context.Context, roleName string, required ...string) (bool, error)
+	GetRoles(ctx context.Context) ([]dto.RoleResponse, error)
+	CreateRole(ctx context.Context, req dto.CreateRoleRequest) (dto.RoleResponse, error)
+	GetPermissions(ctx context.Context) ([]dto.PermissionResponse, error)
+	CreatePermission(ctx context.Context, req dto.CreatePermissionRequest) (dto.PermissionResponse, error)
+	AttachPermission(ctx context.Context, roleID int, permissionID int) error
+	DetachPermission(ctx context.Context, roleID int, permissionID int) error
+	GetUserPermissions(ctx context.Context, userID uuid.UUID) (dto.UserPermissionsResponse, error)
+}
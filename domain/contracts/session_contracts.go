@@ -0,0 +1,40 @@
+package contracts
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *entity.RefreshToken) error
+	FindByHash(ctx context.Context, tokenHash string) (entity.RefreshToken, error)
+	Rotate(ctx context.Context, usedID uuid.UUID, next *entity.RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+// RevokedTokenRepository keeps track of access tokens that must be rejected
+// before they expire. Entries only need to live until expiresAt, after which
+// the token is rejected by its own exp claim anyway. A user revocation rejects
+// tokens issued strictly before revokedBefore, iat only has whole seconds.
+type RevokedTokenRepository interface {
+	RevokeToken(ctx context.Context, jti string, userID uuid.UUID, expiresAt time.Time) error
+	RevokeUserTokens(ctx context.Context, userID uuid.UUID, revokedBefore time.Time, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, jti string, userID uuid.UUID, issuedAt time.Time) (bool, error)
+	DeleteExpired(ctx context.Context) error
+}
+
+type SessionService interface {
+	Issue(ctx context.Context, user entity.User, amr []string) (dto.TokenResponse, error)
+	Refresh(ctx context.Context, req dto.RefreshTokenRequest) (dto.TokenResponse, error)
+	Logout(ctx context.Context, claims jwt.Claims, req dto.LogoutRequest) error
+	LogoutAll(ctx context.Context, claims jwt.Claims) error
+	RevokeUserSessions(ctx context.Context, userID uuid.UUID) error
+	RevokeOtherSessions(ctx context.Context, user entity.User, amr []string) (dto.TokenResponse, error)
+}
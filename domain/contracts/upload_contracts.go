@@ -0,0 +1,16 @@
+package contracts
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/storage"
+)
+
+type UploadService interface {
+	Upload(ctx context.Context, userID uuid.UUID, req dto.UploadFileRequest) (dto.UploadResponse, error)
+	Download(ctx context.Context, req dto.DownloadFileRequest) (io.ReadCloser, storage.ObjectInfo, error)
+}
@@ -0,0 +1,68 @@
+package contracts
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
+)
+
+type UserRepository interface {
+	Create(ctx context.Context, user *entity.User) error
+	FindByID(ctx context.Context, id uuid.UUID) (entity.User, error)
+	FindByIDWithDeleted(ctx context.Context, id uuid.UUID) (entity.User, error)
+	FindAll(ctx context.Context, filter entity.UserFilter) ([]entity.User, int64, error)
+	FindByEmail(ctx context.Context, email string) (entity.User, error)
+	UpdatePassword(ctx context.Context, id uuid.UUID, password string) error
+	MarkEmailVerified(ctx context.Context, id uuid.UUID) error
+	UpdateAvatar(ctx context.Context, id uuid.UUID, avatarKey string) error
+	UpdateProfile(ctx context.Context, user *entity.User) error
+	SetPendingEmail(ctx context.Context, id uuid.UUID, email string) error
+	ConfirmPendingEmail(ctx context.Context, id uuid.UUID) error
+	UpdateRole(ctx context.Context, id uuid.UUID, roleID int, audit *entity.AuditLog) error
+	CreateAuditLog(ctx context.Context, audit *entity.AuditLog) error
+	SoftDelete(ctx context.Context, id uuid.UUID) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	FindDeletedBefore(ctx context.Context, cutoff time.Time, limit int) ([]entity.User, error)
+	Purge(ctx context.Context, id uuid.UUID, cutoff time.Time) error
+}
+
+// UserExportRepository reads the data other modules keep about a user for a
+// personal data export
+type UserExportRepository interface {
+	FindIdentities(ctx context.Context, userID uuid.UUID) ([]entity.UserIdentity, error)
+	FindRefreshTokens(ctx context.Context, userID uuid.UUID) ([]entity.RefreshToken, error)
+	FindPasskeys(ctx context.Context, userID uuid.UUID) ([]entity.WebauthnCredential, error)
+	FindPersonalAccessTokens(ctx context.Context, userID uuid.UUID) ([]entity.PersonalAccessToken, error)
+	FindTotp(ctx context.Context, userID uuid.UUID) (entity.UserTotp, error)
+	CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error)
+	FindAuditLogs(ctx context.Context, userID uuid.UUID) ([]entity.AuditLog, error)
+	FindMails(ctx context.Context, recipient string) ([]entity.MailOutbox, error)
+}
+
+type UserService interface {
+	Register(ctx context.Context, req dto.RegisterRequest) (dto.RegisterResponse, error)
+	Login(ctx context.Context, req dto.LoginRequest) (dto.LoginResponse, error)
+	UpdateAvatar(ctx context.Context, userID uuid.UUID, req dto.UploadFileRequest) (dto.UserResponse, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, req dto.UpdateProfileRequest) (dto.UserResponse, error)
+	ChangePassword(ctx context.Context, claims jwt.Claims, req dto.ChangePasswordRequest) (dto.TokenResponse, error)
+	ExportData(ctx context.Context, userID uuid.UUID) (dto.ExportFileResponse, error)
+	DeleteAccount(ctx context.Context, userID uuid.UUID) error
+	PurgeDeletedUsers(ctx context.Context) error
+	ListUsers(ctx context.Context, query dto.UserListQuery) (dto.UserListResponse, error)
+	GetUser(ctx context.Context, id uuid.UUID, includeDeleted bool) (dto.UserResponse, error)
+	CreateUser(ctx context.Context, actorRole string, req dto.CreateUserRequest) (dto.UserResponse, error)
+	UpdateUser(
+		ctx context.Context,
+		actorID uuid.UUID,
+		actorRole string,
+		id uuid.UUID,
+		req dto.UpdateUserRequest,
+	) (dto.UserResponse, error)
+	DeleteUser(ctx context.Context, actorID uuid.UUID, actorRole string, id uuid.UUID) error
+	RestoreUser(ctx context.Context, actorRole string, id uuid.UUID) (dto.UserResponse, error)
+}
@@ -0,0 +1,30 @@
+package contracts
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+)
+
+type WebauthnRepository interface {
+	CreateCredential(ctx context.Context, credential *entity.WebauthnCredential) error
+	FindCredentialsByUserID(ctx context.Context, userID uuid.UUID) ([]entity.WebauthnCredential, error)
+	UpdateSignCount(ctx context.Context, id uuid.UUID, signCount int64, backupState bool, now time.Time) error
+	DeleteCredential(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+	CreateSession(ctx context.Context, session *entity.WebauthnSession) error
+	ConsumeSession(ctx context.Context, id uuid.UUID, ceremony string, now time.Time) (entity.WebauthnSession, error)
+	DeleteExpiredSessions(ctx context.Context) error
+}
+
+type WebauthnService interface {
+	BeginRegistration(ctx context.Context, userID uuid.UUID, req dto.ReauthRequest) (dto.PasskeyOptionsResponse, error)
+	FinishRegistration(ctx context.Context, userID uuid.UUID, req dto.PasskeyRegisterRequest) (dto.PasskeyResponse, error)
+	BeginLogin(ctx context.Context) (dto.PasskeyOptionsResponse, error)
+	FinishLogin(ctx context.Context, req dto.PasskeyLoginRequest) (dto.LoginResponse, error)
+	List(ctx context.Context, userID uuid.UUID) ([]dto.PasskeyResponse, error)
+	Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+}
@@ -0,0 +1,5 @@
+package dto
+
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
@@ -0,0 +1,5 @@
+package dto
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+// MagicLinkRequest can bind the link to the ip and the browser it was asked
+// from, the link then only works when opened from the same place
+type MagicLinkRequest struct {
+	Email      string `json:"email" validate:"required,email,max=255"`
+	BindIP     bool   `json:"bind_ip"`
+	BindDevice bool   `json:"bind_device"`
+	IPAddress  string `json:"-"`
+	UserAgent  string `json:"-"`
+}
+
+type MagicLinkLoginRequest struct {
+	Token     string `json:"token" validate:"required"`
+	IPAddress string `json:"-"`
+	UserAgent string `json:"-"`
+}
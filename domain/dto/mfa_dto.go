@@ -0,0 +1,43 @@
+package dto
+
+type TotpEnrollResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type TotpCodeRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// MFACodeRequest accepts either a current authenticator code or one of the
+// recovery codes
+type MFACodeRequest struct {
+	Code         string `json:"code" validate:"required_without=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code" validate:"required_without=Code"`
+}
+
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	MFACodeRequest
+	IPAddress string `json:"-"`
+}
+
+// MFAStepUpRequest confirms a change to the second factor of a signed in
+// user, failed codes count towards the login lockout like they do on Verify
+type MFAStepUpRequest struct {
+	MFACodeRequest
+	IPAddress string `json:"-"`
+}
+
+// ReauthRequest confirms a sensitive change of a signed in user with their
+// current password or, when enabled, a second factor
+type ReauthRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required_without_all=Code RecoveryCode"`
+	Code            string `json:"code" validate:"omitempty,len=6,numeric"`
+	RecoveryCode    string `json:"recovery_code"`
+	IPAddress       string `json:"-"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
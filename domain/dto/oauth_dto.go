@@ -0,0 +1,14 @@
+package dto
+
+type OAuthLoginResponse struct {
+	URL   string `json:"url"`
+	State string `json:"-"`
+}
+
+type OAuthCallbackRequest struct {
+	Code  string `query:"code" validate:"required"`
+	State string `query:"state" validate:"required"`
+	// Locale comes from the Accept-Language header, it is only used when the
+	// callback registers a new user
+	Locale string `query:"-"`
+}
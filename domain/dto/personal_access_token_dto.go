@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreatePersonalAccessTokenRequest struct {
+	Name      string   `json:"name" validate:"required,min=3,max=255"`
+	Scopes    []string `json:"scopes" validate:"required,min=1,unique,dive,oneof=profile:read profile:write uploads:write users:read users:write"` //nolint:lll // validator tags can't be split
+	ExpiresIn int      `json:"expires_in_days" validate:"required,min=1,max=365"`
+}
+
+type PersonalAccessTokenResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	TokenHint  string     `json:"token_hint"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP *string    `json:"last_used_ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatePersonalAccessTokenResponse is the only time the plain token is
+// returned, it can't be recovered afterwards
+type CreatePersonalAccessTokenResponse struct {
+	PersonalAccessTokenResponse
+	Token string `json:"token"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "github.com/google/uuid"
+
+type RoleResponse struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	InheritsRoleID *int   `json:"inherits_role_id"`
+}
+
+type CreateRoleRequest struct {
+	Name           string `json:"name" validate:"required,min=3,max=255"`
+	InheritsRoleID *int   `json:"inherits_role_id" validate:"omitempty,min=1"`
+}
+
+type PermissionResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type CreatePermissionRequest struct {
+	Name string `json:"name" validate:"required,min=3,max=255"`
+}
+
+type UserPermissionsResponse struct {
+	UserID      uuid.UUID `json:"user_id"`
+	RoleName    string    `json:"role_name"`
+	Roles       []string  `json:"roles"`
+	Permissions []string  `json:"permissions"`
+}
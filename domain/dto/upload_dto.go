@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"io"
+	"time"
+)
+
+type UploadFileRequest struct {
+	Name    string
+	Size    int64
+	Content io.Reader
+}
+
+type UploadResponse struct {
+	Key         string    `json:"key"`
+	URL         string    `json:"url"`
+	URLExpires  time.Time `json:"url_expires_at"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+}
+
+type DownloadFileRequest struct {
+	Key       string
+	Expires   string `query:"expires"`
+	Signature string `query:"signature"`
+}
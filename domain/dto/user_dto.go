@@ -0,0 +1,90 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserResponse struct {
+	ID              uuid.UUID       `json:"id"`
+	Name            string          `json:"name"`
+	Email           string          `json:"email"`
+	EmailVerifiedAt *time.Time      `json:"email_verified_at"`
+	PendingEmail    *string         `json:"pending_email"`
+	Avatar          *AvatarResponse `json:"avatar"`
+	RoleName        string          `json:"role_name"`
+	Locale          string          `json:"locale"`
+	CreatedAt       time.Time       `json:"created_at"`
+	DeletedAt       *time.Time      `json:"deleted_at,omitempty"`
+}
+
+type RegisterRequest struct {
+	Name     string `json:"name" validate:"required,min=3,max=255"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+	// Locale comes from the Accept-Language header, mails to the user use it
+	Locale string `json:"-"`
+}
+
+type RegisterResponse struct {
+	User  UserResponse  `json:"user"`
+	Token TokenResponse `json:"token"`
+}
+
+type LoginRequest struct {
+	Email     string `json:"email" validate:"required,email"`
+	Password  string `json:"password" validate:"required"`
+	IPAddress string `json:"-"`
+}
+
+// UpdateProfileRequest only changes the fields that are sent. A new email
+// needs the current password and is only used once it has been confirmed
+type UpdateProfileRequest struct {
+	Name            *string `json:"name" validate:"omitnil,min=3,max=255"`
+	Email           *string `json:"email" validate:"omitnil,email,max=255"`
+	Locale          *string `json:"locale" validate:"omitnil,oneof=en id"`
+	CurrentPassword string  `json:"current_password" validate:"required_with=Email"`
+	IPAddress       string  `json:"-"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=72"`
+	IPAddress       string `json:"-"`
+}
+
+type AvatarResponse struct {
+	Small  string `json:"small"`
+	Medium string `json:"medium"`
+	Large  string `json:"large"`
+}
+
+type UserListQuery struct {
+	Search         string `query:"search" validate:"max=255"`
+	IncludeDeleted bool   `query:"include_deleted"`
+	Page           int    `query:"page" validate:"omitempty,min=1"`
+	Limit          int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type UserListResponse struct {
+	Users []UserResponse `json:"users"`
+	Page  int            `json:"page"`
+	Limit int            `json:"limit"`
+	Total int64          `json:"total"`
+}
+
+// CreateUserRequest falls back to the default role when RoleID is empty
+type CreateUserRequest struct {
+	Name     string `json:"name" validate:"required,min=3,max=255"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+	RoleID   int    `json:"role_id" validate:"omitempty,min=1"`
+}
+
+// UpdateUserRequest only changes the fields that are sent
+type UpdateUserRequest struct {
+	Name   *string `json:"name" validate:"omitnil,min=3,max=255"`
+	Email  *string `json:"email" validate:"omitnil,email,max=255"`
+	RoleID *int    `json:"role_id" validate:"omitnil,min=1"`
+}
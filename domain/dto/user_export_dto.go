@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserExport is the account.json of a data export. Secrets such as password
+// and token hashes are left out, they are of no use to the user.
+type UserExport struct {
+	ExportedAt           time.Time                     `json:"exported_at"`
+	Profile              ExportProfile                 `json:"profile"`
+	Identities           []ExportIdentity              `json:"identities"`
+	Sessions             []ExportSession               `json:"sessions"`
+	Passkeys             []PasskeyResponse             `json:"passkeys"`
+	PersonalAccessTokens []PersonalAccessTokenResponse `json:"personal_access_tokens"`
+	TwoFactor            ExportTwoFactor               `json:"two_factor"`
+	AuditLogs            []ExportAuditLog              `json:"audit_logs"`
+	Mails                []ExportMail                  `json:"mails"`
+}
+
+type ExportProfile struct {
+	ID              uuid.UUID  `json:"id"`
+	Name            string     `json:"name"`
+	Email           string     `json:"email"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	PendingEmail    *string    `json:"pending_email"`
+	RoleName        string     `json:"role_name"`
+	Locale          string     `json:"locale"`
+	HasAvatar       bool       `json:"has_avatar"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+type ExportIdentity struct {
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExportSession struct {
+	AMR       []string   `json:"amr"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type ExportTwoFactor struct {
+	TotpEnabled            bool       `json:"totp_enabled"`
+	TotpConfirmedAt        *time.Time `json:"totp_confirmed_at"`
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+}
+
+type ExportAuditLog struct {
+	ActorID   *uuid.UUID      `json:"actor_id"`
+	Action    string          `json:"action"`
+	SubjectID *uuid.UUID      `json:"subject_id"`
+	Metadata  json.RawMessage `json:"metadata"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type ExportMail struct {
+	Subject   string     `json:"subject"`
+	Status    string     `json:"status"`
+	SentAt    *time.Time `json:"sent_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ExportFileResponse is the archive handed to the client as a download
+type ExportFileResponse struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasskeyOptionsResponse carries the options to hand to the browser's
+// webauthn api, the ceremony id goes back with its response
+type PasskeyOptionsResponse struct {
+	CeremonyID uuid.UUID       `json:"ceremony_id"`
+	Options    json.RawMessage `json:"options"`
+}
+
+type PasskeyRegisterRequest struct {
+	CeremonyID uuid.UUID       `json:"ceremony_id" validate:"required"`
+	Name       string          `json:"name" validate:"required,max=255"`
+	Credential json.RawMessage `json:"credential" validate:"required"`
+}
+
+type PasskeyLoginRequest struct {
+	CeremonyID uuid.UUID       `json:"ceremony_id" validate:"required"`
+	Credential json.RawMessage `json:"credential" validate:"required"`
+}
+
+type PasskeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditActionUserRoleChanged   = "user.role_changed"
+	AuditActionPasswordLogin     = "auth.password_login"
+	AuditActionPasswordRejected  = "auth.password_rejected"
+	AuditActionMagicLinkSent     = "auth.magic_link_sent"
+	AuditActionMagicLinkLogin    = "auth.magic_link_login"
+	AuditActionMagicLinkRejected = "auth.magic_link_rejected"
+)
+
+// AuditLog records who did what to whom. Metadata holds the action specific
+// details as a json object.
+type AuditLog struct {
+	ID        uuid.UUID       `db:"id"`
+	ActorID   uuid.NullUUID   `db:"actor_id"`
+	Action    string          `db:"action"`
+	SubjectID uuid.NullUUID   `db:"subject_id"`
+	Metadata  json.RawMessage `db:"metadata"`
+	CreatedAt time.Time       `db:"created_at"`
+}
@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+// LoginThrottle counts recent failed logins for a throttle key, which is an
+// email or an ip address prefixed by its kind. Emails are tracked whether or
+// not an account exists so a lockout reveals nothing about registration.
+type LoginThrottle struct {
+	ThrottleKey    string       `db:"throttle_key"`
+	FailedAttempts int          `db:"failed_attempts"`
+	LockedUntil    sql.NullTime `db:"locked_until"`
+	LastFailedAt   time.Time    `db:"last_failed_at"`
+}
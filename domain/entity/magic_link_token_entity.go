@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MagicLinkToken struct {
+	ID            uuid.UUID      `db:"id"`
+	UserID        uuid.UUID      `db:"user_id"`
+	TokenHash     string         `db:"token_hash"`
+	IPAddress     sql.NullString `db:"ip_address"`
+	UserAgentHash sql.NullString `db:"user_agent_hash"`
+	ExpiresAt     time.Time      `db:"expires_at"`
+	UsedAt        sql.NullTime   `db:"used_at"`
+	CreatedAt     time.Time      `db:"created_at"`
+}
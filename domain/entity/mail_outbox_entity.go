@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MailStatusPending = "pending"
+	MailStatusSent    = "sent"
+	MailStatusFailed  = "failed"
+)
+
+type MailOutbox struct {
+	ID            uuid.UUID      `db:"id"`
+	Recipient     string         `db:"recipient"`
+	Subject       string         `db:"subject"`
+	TextBody      string         `db:"text_body"`
+	HTMLBody      string         `db:"html_body"`
+	Status        string         `db:"status"`
+	Attempts      int            `db:"attempts"`
+	LastError     sql.NullString `db:"last_error"`
+	NextAttemptAt time.Time      `db:"next_attempt_at"`
+	SentAt        sql.NullTime   `db:"sent_at"`
+	CreatedAt     time.Time      `db:"created_at"`
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserTotp is a user's authenticator enrollment. Secret is sealed at rest and
+// the enrollment only counts once ConfirmedAt is set.
+type UserTotp struct {
+	UserID       uuid.UUID    `db:"user_id"`
+	Secret       string       `db:"secret"`
+	LastUsedStep int64        `db:"last_used_step"`
+	ConfirmedAt  sql.NullTime `db:"confirmed_at"`
+	CreatedAt    time.Time    `db:"created_at"`
+}
+
+type MFARecoveryCode struct {
+	ID        uuid.UUID    `db:"id"`
+	UserID    uuid.UUID    `db:"user_id"`
+	CodeHash  string       `db:"code_hash"`
+	UsedAt    sql.NullTime `db:"used_at"`
+	CreatedAt time.Time    `db:"created_at"`
+}
+
+// MFAChallenge is the pending login of a user who passed the first factor,
+// AMR holds the methods used so far
+type MFAChallenge struct {
+	ID        uuid.UUID `db:"id"`
+	UserID    uuid.UUID `db:"user_id"`
+	TokenHash string    `db:"token_hash"`
+	AMR       string    `db:"amr"`
+	Attempts  int       `db:"attempts"`
+	ExpiresAt time.Time `db:"expires_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
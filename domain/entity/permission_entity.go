@@ -0,0 +1,6 @@
+package entity
+
+type Permission struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+}
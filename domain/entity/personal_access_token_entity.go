@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PersonalAccessTokenPrefix starts every personal access token, it tells them
+// apart from jwts and lets secret scanners recognise a leaked one
+const PersonalAccessTokenPrefix = "caper_pat_"
+
+// Scopes a personal access token can be granted. A token only reaches routes
+// whose scopes it holds.
+const (
+	ScopeProfileRead  = "profile:read"
+	ScopeProfileWrite = "profile:write"
+	ScopeUploadsWrite = "uploads:write"
+	ScopeUsersRead    = "users:read"
+	ScopeUsersWrite   = "users:write"
+)
+
+// PersonalAccessToken is stored hashed, TokenHint keeps the prefix and the
+// first characters after it so users can tell their tokens apart. Scopes are
+// space separated.
+type PersonalAccessToken struct {
+	ID         uuid.UUID      `db:"id"`
+	UserID     uuid.UUID      `db:"user_id"`
+	Name       string         `db:"name"`
+	TokenHash  string         `db:"token_hash"`
+	TokenHint  string         `db:"token_hint"`
+	Scopes     string         `db:"scopes"`
+	ExpiresAt  time.Time      `db:"expires_at"`
+	LastUsedAt sql.NullTime   `db:"last_used_at"`
+	LastUsedIP sql.NullString `db:"last_used_ip"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RefreshToken struct {
+	ID        uuid.UUID `db:"id"`
+	UserID    uuid.UUID `db:"user_id"`
+	FamilyID  uuid.UUID `db:"family_id"`
+	TokenHash string    `db:"token_hash"`
+	// AMR is the space separated amr claim of the login that started the
+	// family, carried over to every access token it refreshes into
+	AMR       string       `db:"amr"`
+	ExpiresAt time.Time    `db:"expires_at"`
+	UsedAt    sql.NullTime `db:"used_at"`
+	RevokedAt sql.NullTime `db:"revoked_at"`
+	CreatedAt time.Time    `db:"created_at"`
+}
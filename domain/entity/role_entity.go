@@ -0,0 +1,16 @@
+package entity
+
+import "database/sql"
+
+const (
+	RoleSuperAdmin = "superadmin"
+	RoleAdmin      = "admin"
+	RoleModerator  = "moderator"
+	RoleMember     = "member"
+)
+
+type Role struct {
+	ID             int           `db:"id"`
+	Name           string        `db:"name"`
+	InheritsRoleID sql.NullInt32 `db:"inherits_role_id"`
+}
@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type User struct {
+	ID              uuid.UUID      `db:"id"`
+	Name            string         `db:"name"`
+	Email           string         `db:"email"`
+	Password        string         `db:"password" json:"-"`
+	CreatedAt       time.Time      `db:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at"`
+	DeletedAt       sql.NullTime   `db:"deleted_at"`
+	EmailVerifiedAt sql.NullTime   `db:"email_verified_at"`
+	AvatarKey       sql.NullString `db:"avatar_key"`
+	Locale          string         `db:"locale"`
+	PendingEmail    sql.NullString `db:"pending_email"`
+	RoleID          int            `db:"role_id"`
+	Role            Role           `db:"role"`
+}
+
+// UserFilter narrows FindAll, an empty Search matches every user
+type UserFilter struct {
+	Search         string
+	IncludeDeleted bool
+	Limit          int
+	Offset         int
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	WebauthnCeremonyRegistration = "registration"
+	WebauthnCeremonyLogin        = "login"
+)
+
+// WebauthnCredential is a passkey of a user. Transports is space separated
+// and SignCount only ever moves forward.
+type WebauthnCredential struct {
+	ID              uuid.UUID    `db:"id"`
+	UserID          uuid.UUID    `db:"user_id"`
+	Name            string       `db:"name"`
+	CredentialID    []byte       `db:"credential_id"`
+	PublicKey       []byte       `db:"public_key"`
+	AttestationType string       `db:"attestation_type"`
+	Transports      string       `db:"transports"`
+	AAGUID          []byte       `db:"aaguid"`
+	SignCount       int64        `db:"sign_count"`
+	BackupEligible  bool         `db:"backup_eligible"`
+	BackupState     bool         `db:"backup_state"`
+	LastUsedAt      sql.NullTime `db:"last_used_at"`
+	CreatedAt       time.Time    `db:"created_at"`
+}
+
+// WebauthnSession is a ceremony waiting for the authenticator's response.
+// Login ceremonies don't know the user until the response arrives.
+type WebauthnSession struct {
+	ID          uuid.UUID     `db:"id"`
+	UserID      uuid.NullUUID `db:"user_id"`
+	Ceremony    string        `db:"ceremony"`
+	SessionData string        `db:"session_data"`
+	ExpiresAt   time.Time     `db:"expires_at"`
+	CreatedAt   time.Time     `db:"created_at"`
+}
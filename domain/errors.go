@@ -23,6 +23,11 @@ var ErrNotFound = &RequestError{
 	Err:        errors.New("something not found"),
 }
 
+var ErrInvalidRequestBody = &RequestError{
+	StatusCode: http.StatusBadRequest,
+	Err:        errors.New("invalid request body"),
+}
+
 var ErrNoAPIKey = &RequestError{
 	StatusCode: http.StatusUnauthorized,
 	Err:        errors.New("no api key provided"),
@@ -63,9 +68,9 @@ var ErrBearerTokenNotActive = &RequestError{
 	Err:        errors.New("bearer token not active"),
 }
 
-var ErrEmailNotFound = &RequestError{
-	StatusCode: http.StatusNotFound,
-	Err:        errors.New("email not found"),
+var ErrRevokedBearerToken = &RequestError{
+	StatusCode: http.StatusUnauthorized,
+	Err:        errors.New("bearer token has been revoked"),
 }
 
 var ErrCredentialsNotMatch = &RequestError{
@@ -77,3 +82,198 @@ var ErrRoleCantAccessResource = &RequestError{
 	StatusCode: http.StatusForbidden,
 	Err:        errors.New("role can't access resource"),
 }
+
+var ErrInvalidRefreshToken = &RequestError{
+	StatusCode: http.StatusUnauthorized,
+	Err:        errors.New("invalid refresh token"),
+}
+
+var ErrExpiredRefreshToken = &RequestError{
+	StatusCode: http.StatusUnauthorized,
+	Err:        errors.New("expired refresh token"),
+}
+
+var ErrRefreshTokenReused = &RequestError{
+	StatusCode: http.StatusUnauthorized,
+	Err:        errors.New("refresh token has already been used"),
+}
+
+var ErrRoleNotFound = &RequestError{
+	StatusCode: http.StatusNotFound,
+	Err:        errors.New("role not found"),
+}
+
+var ErrRoleAlreadyExists = &RequestError{
+	StatusCode: http.StatusConflict,
+	Err:        errors.New("role already exists"),
+}
+
+var ErrPermissionNotFound = &RequestError{
+	StatusCode: http.StatusNotFound,
+	Err:        errors.New("permission not found"),
+}
+
+var ErrPermissionAlreadyExists = &RequestError{
+	StatusCode: http.StatusConflict,
+	Err:        errors.New("permission already exists"),
+}
+
+var ErrInvalidPasswordResetToken = &RequestError{
+	StatusCode: http.StatusBadRequest,
+	Err:        errors.New("invalid or expired password reset token"),
+}
+
+var ErrInvalidEmailVerificationToken = &RequestError{
+	StatusCode: http.StatusBadRequest,
+	Err:        errors.New("invalid or expired email verification token"),
+}
+
+var ErrEmailAlreadyVerified = &RequestError{
+	StatusCode: http.StatusConflict,
+	Err:        errors.New("email has already been verified"),
+}
+
+var ErrInvalidEmailChangeToken = &RequestError{
+	StatusCode: http.StatusBadRequest,
+	Err:        errors.New("invalid or expired email change token"),
+}
+
+var ErrEmailNotVerified = &RequestError{
+	StatusCode: http.StatusForbidden,
+	Err:        errors.New("email has not been verified"),
+}
+
+var ErrTooManyRequests = &RequestError{
+	StatusCode: http.StatusTooManyRequests,
+	Err:        errors.New("too many requests, please try again later"),
+}
+
+var ErrRateLimitUnavailable = &RequestError{
+	StatusCode: http.StatusServiceUnavailable,
+	Err:        errors.New("service is temporarily unavailable, please try again later"),
+}
+
+var ErrOAuthNotConfigured = &RequestError{
+	StatusCode: http.StatusServiceUnavailable,
+	Err:        errors.New("oauth provider is not configured"),
+}
+
+var ErrInvalidOAuthState = &RequestError{
+	StatusCode: http.StatusBadRequest,
+	Err:        errors.New("invalid or expired oauth state"),
+}
+
+var ErrOAuthAuthenticationFailed = &RequestError{
+	StatusCode: http.StatusUnauthorized,
+	Err:        errors.New("failed to authenticate with oauth provider"),
+}
+
+var ErrOAuthEmailNotVerified = &RequestError{
+	StatusCode: http.StatusForbidden,
+	Err:        errors.New("oauth provider has not verified the email"),
+}
+
+var ErrUserIdentityNotFound = &RequestError{
+	StatusCode: http.StatusNotFound,
+	Err:        errors.New("user identity not found"),
+}
+
+var ErrFileRequired = &RequestError{
+	StatusCode: http.StatusBadRequest,
+	Err:        errors.New("file is required"),
+}
+
+var ErrFileTooLarge = &RequestError{
+	StatusCode: http.StatusRequestEntityTooLarge,
+	Err:        errors.New("file is too large"),
+}
+
+var ErrUnsupportedFileType = &RequestError{
+	StatusCode: http.StatusUnsupportedMediaType,
+	Err:        errors.New("file type is not supported"),
+}
+
+var ErrFileNotFound = &RequestError{
+	StatusCode: http.StatusNotFound,
+	Err:        errors.New("file not found"),
+}
+
+var ErrInvalidImage = &RequestError{
+	StatusCode: http.StatusUnprocessableEntity,
+	Err:        errors.New("file is not a valid image"),
+}
+
+var ErrTooManyLoginAttempts = &RequestError{
+	StatusCode: http.StatusTooManyRequests,
+	Err:        errors.New("too many failed login attempts, please try again later"),
+}
+
+var ErrMFAAlreadyEnabled = &RequestError{
+	StatusCode: http.StatusConflict,
+	Err:        errors.New("two-factor authentication is already enabled"),
+}
+
+var ErrMFANotEnabled = &RequestError{
+	StatusCode: http.StatusBadRequest,
+	Err:        errors.New("two-factor authentication is not enabled"),
+}
+
+var ErrMFAEnrollmentNotFound = &RequestError{
+	StatusCode: http.StatusNotFound,
+	Err:        errors.New("two-factor authentication enrollment not found"),
+}
+
+var ErrInvalidMFACode = &RequestError{
+	StatusCode: http.StatusUnauthorized,
+	Err:        errors.New("invalid two-factor authentication code"),
+}
+
+var ErrInvalidMFAToken = &RequestError{
+	StatusCode: http.StatusUnauthorized,
+	Err:        errors.New("invalid or expired mfa token"),
+}
+
+var ErrInvalidMagicLinkToken = &RequestError{
+	StatusCode: http.StatusUnauthorized,
+	Err:        errors.New("invalid or expired magic link"),
+}
+
+var ErrPasskeyNotFound = &RequestError{
+	StatusCode: http.StatusNotFound,
+	Err:        errors.New("passkey not found"),
+}
+
+var ErrPasskeyAlreadyRegistered = &RequestError{
+	StatusCode: http.StatusConflict,
+	Err:        errors.New("passkey has already been registered"),
+}
+
+var ErrInvalidPasskeyCeremony = &RequestError{
+	StatusCode: http.StatusBadRequest,
+	Err:        errors.New("invalid or expired passkey ceremony"),
+}
+
+var ErrPasskeyVerificationFailed = &RequestError{
+	StatusCode: http.StatusUnauthorized,
+	Err:        errors.New("passkey verification failed"),
+}
+
+var ErrUserNotDeleted = &RequestError{
+	StatusCode: http.StatusConflict,
+	Err:        errors.New("user is not deleted"),
+}
+
+var ErrCantManageOwnAccount = &RequestError{
+	StatusCode: http.StatusForbidden,
+	Err:        errors.New("admins can't change the role of or delete their own account"),
+}
+
+var ErrPersonalAccessTokenNotFound = &RequestError{
+	StatusCode: http.StatusNotFound,
+	Err:        errors.New("personal access token not found"),
+}
+
+var ErrInsufficientTokenScope = &RequestError{
+	StatusCode: http.StatusForbidden,
+	Err:        errors.New("personal access token scopes don't allow this request"),
+}
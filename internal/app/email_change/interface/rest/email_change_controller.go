@@ -0,0 +1,55 @@
+package rest
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/internal/middlewares"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers/http/response"
+	ratelimiter "github.com/kelompok1-swe-academya/caper-be/pkg/rate_limiter"
+)
+
+var confirmRateLimit = middlewares.RateLimitPolicy{
+	Name: "confirm_email_change",
+	Policy: ratelimiter.Policy{
+		Algorithm: ratelimiter.FixedWindow,
+		Limit:     10,
+		Window:    15 * time.Minute,
+	},
+	Key: middlewares.KeyByIP,
+}
+
+type emailChangeController struct {
+	emailChangeService contracts.EmailChangeService
+}
+
+// InitEmailChangeController only serves the confirmation, a change is
+// requested through PATCH /users/me
+func InitEmailChangeController(
+	router fiber.Router,
+	emailChangeService contracts.EmailChangeService,
+	middleware *middlewares.Middleware,
+) {
+	controller := emailChangeController{
+		emailChangeService: emailChangeService,
+	}
+
+	router.Post("/auth/email/change/confirm", middleware.RateLimit(confirmRateLimit), controller.confirm)
+}
+
+func (c *emailChangeController) confirm(ctx *fiber.Ctx) error {
+	var req dto.ConfirmEmailChangeRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	if err := c.emailChangeService.Confirm(ctx.Context(), req); err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, "email has been changed")
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+type emailChangeRepository struct {
+	db *sqlx.DB
+}
+
+func NewEmailChangeRepository(db *sqlx.DB) contracts.EmailChangeRepository {
+	return &emailChangeRepository{
+		db: db,
+	}
+}
+
+func (r *emailChangeRepository) Create(ctx context.Context, token *entity.EmailChangeToken) error {
+	query := `
+		INSERT INTO email_change_tokens (id, user_id, token_hash, expires_at)
+		VALUES (:id, :user_id, :token_hash, :expires_at)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, token)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[EMAIL CHANGE REPOSITORY][Create] failed to create email change token")
+		return err
+	}
+
+	return nil
+}
+
+func (r *emailChangeRepository) InvalidateByUserID(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE email_change_tokens
+		SET used_at = NOW()
+		WHERE user_id = $1 AND used_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[EMAIL CHANGE REPOSITORY][InvalidateByUserID] failed to invalidate email change tokens")
+		return err
+	}
+
+	return nil
+}
+
+// Consume marks the token as used and returns it in a single statement, so a
+// token can only ever be redeemed once even under concurrent requests.
+func (r *emailChangeRepository) Consume(
+	ctx context.Context,
+	tokenHash string,
+	now time.Time,
+) (entity.EmailChangeToken, error) {
+	query := `
+		UPDATE email_change_tokens
+		SET used_at = $2
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+		RETURNING id, user_id, token_hash, expires_at, used_at, created_at
+	`
+
+	var token entity.EmailChangeToken
+	err := r.db.GetContext(ctx, &token, query, tokenHash, now)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return token, domain.ErrInvalidEmailChangeToken
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[EMAIL CHANGE REPOSITORY][Consume] failed to consume email change token")
+		return token, err
+	}
+
+	return token, nil
+}
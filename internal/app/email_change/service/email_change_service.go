@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/mail"
+	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/token"
+	uuidPkg "github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
+)
+
+const emailChangeTokenSize = 32
+
+type emailChangeService struct {
+	emailChangeRepo contracts.EmailChangeRepository
+	userRepo        contracts.UserRepository
+	validator       validator.ValidatorInterface
+	uuid            uuidPkg.UUIDInterface
+	token           token.TokenInterface
+	mailService     contracts.MailService
+	time            timePkg.TimeInterface
+}
+
+func NewEmailChangeService(
+	emailChangeRepo contracts.EmailChangeRepository,
+	userRepo contracts.UserRepository,
+	validator validator.ValidatorInterface,
+	uuid uuidPkg.UUIDInterface,
+	token token.TokenInterface,
+	mailService contracts.MailService,
+	time timePkg.TimeInterface,
+) contracts.EmailChangeService {
+	return &emailChangeService{
+		emailChangeRepo: emailChangeRepo,
+		userRepo:        userRepo,
+		validator:       validator,
+		uuid:            uuid,
+		token:           token,
+		mailService:     mailService,
+		time:            time,
+	}
+}
+
+// Request keeps email as the pending email of the user and mails a
+// confirmation link to it. The account keeps its current email until the link
+// is followed, a newer request replaces any pending one.
+func (s *emailChangeService) Request(ctx context.Context, user entity.User, email string) error {
+	if err := s.emailChangeRepo.InvalidateByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.SetPendingEmail(ctx, user.ID, email); err != nil {
+		return err
+	}
+
+	id, err := s.uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	plain, err := s.token.Generate(emailChangeTokenSize)
+	if err != nil {
+		return err
+	}
+
+	changeToken := entity.EmailChangeToken{
+		ID:        id,
+		UserID:    user.ID,
+		TokenHash: s.token.Hash(plain),
+		ExpiresAt: s.time.Add(env.AppEnv.EmailVerificationExpTime),
+	}
+
+	if err := s.emailChangeRepo.Create(ctx, &changeToken); err != nil {
+		return err
+	}
+
+	return s.mailService.Queue(ctx, email, user.Locale, mail.TemplateEmailChange, mail.LinkData{
+		Name:      user.Name,
+		Link:      buildConfirmLink(plain),
+		ExpiresIn: env.AppEnv.EmailVerificationExpTime.String(),
+	})
+}
+
+// Confirm swaps the pending email in. It fails with ErrUserEmailAlreadyExists
+// when another account took the address in the meantime.
+func (s *emailChangeService) Confirm(ctx context.Context, req dto.ConfirmEmailChangeRequest) error {
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return valErr
+	}
+
+	changeToken, err := s.emailChangeRepo.Consume(ctx, s.token.Hash(req.Token), s.time.Now())
+	if err != nil {
+		return err
+	}
+
+	return s.userRepo.ConfirmPendingEmail(ctx, changeToken.UserID)
+}
+
+func buildConfirmLink(plain string) string {
+	return fmt.Sprintf(
+		"%s/confirm-email?token=%s",
+		strings.TrimRight(env.AppEnv.FrontendURL, "/"),
+		url.QueryEscape(plain),
+	)
+}
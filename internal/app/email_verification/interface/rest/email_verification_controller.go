@@ -0,0 +1,82 @@
+package rest
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/internal/middlewares"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers/http/response"
+	ratelimiter "github.com/kelompok1-swe-academya/caper-be/pkg/rate_limiter"
+)
+
+var (
+	verifyRateLimit = middlewares.RateLimitPolicy{
+		Name: "verify_email",
+		Policy: ratelimiter.Policy{
+			Algorithm: ratelimiter.FixedWindow,
+			Limit:     10,
+			Window:    15 * time.Minute,
+		},
+		Key: middlewares.KeyByIP,
+	}
+
+	// resend is limited per account rather than per ip so a user can't flood
+	// their own inbox from several addresses
+	resendRateLimit = middlewares.RateLimitPolicy{
+		Name: "resend_verification",
+		Policy: ratelimiter.Policy{
+			Algorithm: ratelimiter.FixedWindow,
+			Limit:     3,
+			Window:    15 * time.Minute,
+		},
+		Key: middlewares.KeyByUser,
+	}
+)
+
+type emailVerificationController struct {
+	emailVerificationService contracts.EmailVerificationService
+}
+
+func InitEmailVerificationController(
+	router fiber.Router,
+	emailVerificationService contracts.EmailVerificationService,
+	middleware *middlewares.Middleware,
+) {
+	controller := emailVerificationController{
+		emailVerificationService: emailVerificationService,
+	}
+
+	emailRoute := router.Group("/auth/email")
+	emailRoute.Post("/verify", middleware.RateLimit(verifyRateLimit), controller.verify)
+	emailRoute.Post("/resend", middleware.RequireAuth(), middleware.RateLimit(resendRateLimit), controller.resend)
+}
+
+func (c *emailVerificationController) verify(ctx *fiber.Ctx) error {
+	var req dto.VerifyEmailRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	if err := c.emailVerificationService.Verify(ctx.Context(), req); err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, "email has been verified")
+}
+
+func (c *emailVerificationController) resend(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.emailVerificationService.Resend(ctx.Context(), claims.UserID); err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusAccepted, "verification email has been sent")
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+type emailVerificationRepository struct {
+	db *sqlx.DB
+}
+
+func NewEmailVerificationRepository(db *sqlx.DB) contracts.EmailVerificationRepository {
+	return &emailVerificationRepository{
+		db: db,
+	}
+}
+
+func (r *emailVerificationRepository) Create(ctx context.Context, token *entity.EmailVerificationToken) error {
+	query := `
+		INSERT INTO email_verification_tokens (id, user_id, token_hash, expires_at)
+		VALUES (:id, :user_id, :token_hash, :expires_at)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, token)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[EMAIL VERIFICATION REPOSITORY][Create] failed to create email verification token")
+		return err
+	}
+
+	return nil
+}
+
+func (r *emailVerificationRepository) InvalidateByUserID(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE email_verification_tokens
+		SET used_at = NOW()
+		WHERE user_id = $1 AND used_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[EMAIL VERIFICATION REPOSITORY][InvalidateByUserID] failed to invalidate email verification tokens")
+		return err
+	}
+
+	return nil
+}
+
+// Consume marks the token as used and returns it in a single statement, so a
+// token can only ever be redeemed once even under concurrent requests.
+func (r *emailVerificationRepository) Consume(
+	ctx context.Context,
+	tokenHash string,
+	now time.Time,
+) (entity.EmailVerificationToken, error) {
+	query := `
+		UPDATE email_verification_tokens
+		SET used_at = $2
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+		RETURNING id, user_id, token_hash, expires_at, used_at, created_at
+	`
+
+	var token entity.EmailVerificationToken
+	err := r.db.GetContext(ctx, &token, query, tokenHash, now)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return token, domain.ErrInvalidEmailVerificationToken
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[EMAIL VERIFICATION REPOSITORY][Consume] failed to consume email verification token")
+		return token, err
+	}
+
+	return token, nil
+}
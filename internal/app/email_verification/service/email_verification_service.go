@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/mail"
+	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/token"
+	uuidPkg "github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
+)
+
+const emailVerificationTokenSize = 32
+
+type emailVerificationService struct {
+	emailVerificationRepo contracts.EmailVerificationRepository
+	userRepo              contracts.UserRepository
+	validator             validator.ValidatorInterface
+	uuid                  uuidPkg.UUIDInterface
+	token                 token.TokenInterface
+	mailService           contracts.MailService
+	time                  timePkg.TimeInterface
+}
+
+func NewEmailVerificationService(
+	emailVerificationRepo contracts.EmailVerificationRepository,
+	userRepo contracts.UserRepository,
+	validator validator.ValidatorInterface,
+	uuid uuidPkg.UUIDInterface,
+	token token.TokenInterface,
+	mailService contracts.MailService,
+	time timePkg.TimeInterface,
+) contracts.EmailVerificationService {
+	return &emailVerificationService{
+		emailVerificationRepo: emailVerificationRepo,
+		userRepo:              userRepo,
+		validator:             validator,
+		uuid:                  uuid,
+		token:                 token,
+		mailService:           mailService,
+		time:                  time,
+	}
+}
+
+// Send replaces any pending verification token of the user with a new one
+// and queues the mail.
+func (s *emailVerificationService) Send(ctx context.Context, user entity.User) error {
+	if user.EmailVerifiedAt.Valid {
+		return domain.ErrEmailAlreadyVerified
+	}
+
+	if err := s.emailVerificationRepo.InvalidateByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	id, err := s.uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	plain, err := s.token.Generate(emailVerificationTokenSize)
+	if err != nil {
+		return err
+	}
+
+	verificationToken := entity.EmailVerificationToken{
+		ID:        id,
+		UserID:    user.ID,
+		TokenHash: s.token.Hash(plain),
+		ExpiresAt: s.time.Add(env.AppEnv.EmailVerificationExpTime),
+	}
+
+	if err := s.emailVerificationRepo.Create(ctx, &verificationToken); err != nil {
+		return err
+	}
+
+	return s.mailService.Queue(ctx, user.Email, user.Locale, mail.TemplateEmailVerification, mail.LinkData{
+		Name:      user.Name,
+		Link:      buildVerificationLink(plain),
+		ExpiresIn: env.AppEnv.EmailVerificationExpTime.String(),
+	})
+}
+
+func (s *emailVerificationService) Resend(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.Send(ctx, user)
+}
+
+func (s *emailVerificationService) Verify(ctx context.Context, req dto.VerifyEmailRequest) error {
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return valErr
+	}
+
+	verificationToken, err := s.emailVerificationRepo.Consume(ctx, s.token.Hash(req.Token), s.time.Now())
+	if err != nil {
+		return err
+	}
+
+	return s.userRepo.MarkEmailVerified(ctx, verificationToken.UserID)
+}
+
+func buildVerificationLink(plain string) string {
+	return fmt.Sprintf(
+		"%s/verify-email?token=%s",
+		strings.TrimRight(env.AppEnv.FrontendURL, "/"),
+		url.QueryEscape(plain),
+	)
+}
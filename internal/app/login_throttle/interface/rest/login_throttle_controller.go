@@ -0,0 +1,46 @@
+package rest
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/middlewares"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers/http/response"
+)
+
+type loginThrottleController struct {
+	loginThrottleService contracts.LoginThrottleService
+}
+
+func InitLoginThrottleController(
+	router fiber.Router,
+	loginThrottleService contracts.LoginThrottleService,
+	middleware *middlewares.Middleware,
+) {
+	controller := loginThrottleController{
+		loginThrottleService: loginThrottleService,
+	}
+
+	router.Post(
+		"/users/:id/unlock",
+		middleware.RequireAuth(),
+		middleware.RequireRoles(entity.RoleAdmin),
+		controller.unlock,
+	)
+}
+
+func (c *loginThrottleController) unlock(ctx *fiber.Ctx) error {
+	userID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	if err := c.loginThrottleService.Unlock(ctx.Context(), userID); err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, "account has been unlocked")
+}
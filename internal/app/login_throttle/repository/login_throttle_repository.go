@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+type loginThrottleRepository struct {
+	db *sqlx.DB
+}
+
+func NewLoginThrottleRepository(db *sqlx.DB) contracts.LoginThrottleRepository {
+	return &loginThrottleRepository{
+		db: db,
+	}
+}
+
+// RecordFailure counts a failed login and returns the attempts so far.
+// The count starts over when the previous failure happened before since.
+func (r *loginThrottleRepository) RecordFailure(
+	ctx context.Context,
+	throttleKey string,
+	now time.Time,
+	since time.Time,
+) (int, error) {
+	query := `
+		INSERT INTO login_throttles (throttle_key, failed_attempts, last_failed_at)
+		VALUES ($1, 1, $2)
+		ON CONFLICT (throttle_key) DO UPDATE SET
+			failed_attempts = CASE
+				WHEN login_throttles.last_failed_at < $3 THEN 1
+				ELSE login_throttles.failed_attempts + 1
+			END,
+			last_failed_at = EXCLUDED.last_failed_at
+		RETURNING failed_attempts
+	`
+
+	var attempts int
+	err := r.db.GetContext(ctx, &attempts, query, throttleKey, now, since)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[LOGIN THROTTLE REPOSITORY][RecordFailure] failed to record failed login")
+		return 0, err
+	}
+
+	return attempts, nil
+}
+
+func (r *loginThrottleRepository) Lock(ctx context.Context, throttleKey string, until time.Time) error {
+	query := `
+		UPDATE login_throttles
+		SET locked_until = $2
+		WHERE throttle_key = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, throttleKey, until)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[LOGIN THROTTLE REPOSITORY][Lock] failed to lock throttle key")
+		return err
+	}
+
+	return nil
+}
+
+// FindLockedUntil returns the latest lock among the keys, or the zero time
+// when none of them is locked at now
+func (r *loginThrottleRepository) FindLockedUntil(
+	ctx context.Context,
+	now time.Time,
+	throttleKeys ...string,
+) (time.Time, error) {
+	query := `
+		SELECT MAX(locked_until)
+		FROM login_throttles
+		WHERE throttle_key = ANY($1) AND locked_until > $2
+	`
+
+	var lockedUntil *time.Time
+	err := r.db.GetContext(ctx, &lockedUntil, query, throttleKeys, now)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[LOGIN THROTTLE REPOSITORY][FindLockedUntil] failed to find login lock")
+		return time.Time{}, err
+	}
+
+	if lockedUntil == nil {
+		return time.Time{}, nil
+	}
+
+	return *lockedUntil, nil
+}
+
+func (r *loginThrottleRepository) Reset(ctx context.Context, throttleKey string) error {
+	query := `DELETE FROM login_throttles WHERE throttle_key = $1`
+
+	_, err := r.db.ExecContext(ctx, query, throttleKey)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[LOGIN THROTTLE REPOSITORY][Reset] failed to reset throttle key")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteExpired drops keys whose failures are older than before and that are
+// no longer locked
+func (r *loginThrottleRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	query := `
+		DELETE FROM login_throttles
+		WHERE last_failed_at < $1 AND (locked_until IS NULL OR locked_until <= NOW())
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, before); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[LOGIN THROTTLE REPOSITORY][DeleteExpired] failed to delete expired login throttles")
+		return err
+	}
+
+	return nil
+}
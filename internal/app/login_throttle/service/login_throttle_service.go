@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/mail"
+	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+)
+
+const (
+	// failures older than the window are forgotten, the window restarts on
+	// every failure so a steady attack keeps its count
+	loginFailureWindow = time.Hour
+
+	// an account is slowed down after a few failures and locked after a few
+	// more, each lock doubling the previous one
+	accountDelayAfter = 3
+	accountLockAfter  = 5
+
+	// an ip may be shared by many users behind a nat, so it gets more room
+	ipDelayAfter = 10
+	ipLockAfter  = 20
+
+	loginBaseDelay    = 2 * time.Second
+	loginLockDuration = 15 * time.Minute
+	loginMaxLock      = 24 * time.Hour
+)
+
+type loginThrottleService struct {
+	loginThrottleRepo contracts.LoginThrottleRepository
+	userRepo          contracts.UserRepository
+	mailService       contracts.MailService
+	time              timePkg.TimeInterface
+}
+
+func NewLoginThrottleService(
+	loginThrottleRepo contracts.LoginThrottleRepository,
+	userRepo contracts.UserRepository,
+	mailService contracts.MailService,
+	time timePkg.TimeInterface,
+) contracts.LoginThrottleService {
+	return &loginThrottleService{
+		loginThrottleRepo: loginThrottleRepo,
+		userRepo:          userRepo,
+		mailService:       mailService,
+		time:              time,
+	}
+}
+
+func (s *loginThrottleService) Check(ctx context.Context, email string, ip string) error {
+	lockedUntil, err := s.loginThrottleRepo.FindLockedUntil(ctx, s.time.Now(), emailKey(email), ipKey(ip))
+	if err != nil {
+		return err
+	}
+
+	if !lockedUntil.IsZero() {
+		return domain.ErrTooManyLoginAttempts
+	}
+
+	return nil
+}
+
+func (s *loginThrottleService) RecordFailure(ctx context.Context, email string, ip string) error {
+	attempts, err := s.record(ctx, emailKey(email), accountDelayAfter, accountLockAfter)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.record(ctx, ipKey(ip), ipDelayAfter, ipLockAfter); err != nil {
+		return err
+	}
+
+	// only the first lock is mailed, the following ones would flood the inbox
+	// of a user under attack
+	if attempts == accountLockAfter {
+		s.notifyLocked(ctx, email, lockoutFor(attempts, accountDelayAfter, accountLockAfter))
+	}
+
+	return nil
+}
+
+// RecordSuccess clears the account but leaves the ip alone, otherwise an
+// attacker could reset their ip by signing in to an account of their own
+func (s *loginThrottleService) RecordSuccess(ctx context.Context, email string) error {
+	return s.loginThrottleRepo.Reset(ctx, emailKey(email))
+}
+
+func (s *loginThrottleService) Unlock(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.loginThrottleRepo.Reset(ctx, emailKey(user.Email))
+}
+
+func (s *loginThrottleService) DeleteExpired(ctx context.Context) error {
+	return s.loginThrottleRepo.DeleteExpired(ctx, s.time.Now().Add(-loginFailureWindow))
+}
+
+func (s *loginThrottleService) record(
+	ctx context.Context,
+	throttleKey string,
+	delayAfter int,
+	lockAfter int,
+) (int, error) {
+	now := s.time.Now()
+
+	attempts, err := s.loginThrottleRepo.RecordFailure(ctx, throttleKey, now, now.Add(-loginFailureWindow))
+	if err != nil {
+		return 0, err
+	}
+
+	if lockout := lockoutFor(attempts, delayAfter, lockAfter); lockout > 0 {
+		if err := s.loginThrottleRepo.Lock(ctx, throttleKey, now.Add(lockout)); err != nil {
+			return 0, err
+		}
+	}
+
+	return attempts, nil
+}
+
+// notifyLocked is best effort, failing to mail must not change the response
+// or it would tell registered emails apart
+func (s *loginThrottleService) notifyLocked(ctx context.Context, email string, lockout time.Duration) {
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			log.Warn(log.LogInfo{
+				"error": err.Error(),
+			}, "[LOGIN THROTTLE SERVICE][notifyLocked] failed to find locked user")
+		}
+
+		return
+	}
+
+	err = s.mailService.Queue(ctx, user.Email, user.Locale, mail.TemplateAccountLocked, mail.AccountLockedData{
+		Name:      user.Name,
+		LockedFor: lockout.String(),
+		Link:      fmt.Sprintf("%s/forgot-password", strings.TrimRight(env.AppEnv.FrontendURL, "/")),
+	})
+	if err != nil {
+		log.Warn(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": user.ID,
+		}, "[LOGIN THROTTLE SERVICE][notifyLocked] failed to queue account locked mail")
+	}
+}
+
+// lockoutFor is how long a key is locked after its latest failure, growing
+// from a short delay to locks that double up to loginMaxLock
+func lockoutFor(attempts int, delayAfter int, lockAfter int) time.Duration {
+	switch {
+	case attempts < delayAfter:
+		return 0
+	case attempts < lockAfter:
+		return loginBaseDelay << (attempts - delayAfter)
+	default:
+		return min(loginLockDuration<<min(attempts-lockAfter, 10), loginMaxLock)
+	}
+}
+
+func emailKey(email string) string {
+	return "email:" + email
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
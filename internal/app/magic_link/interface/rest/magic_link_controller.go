@@ -0,0 +1,94 @@
+package rest
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/internal/middlewares"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers/http/response"
+	ratelimiter "github.com/kelompok1-swe-academya/caper-be/pkg/rate_limiter"
+)
+
+var (
+	magicLinkRateLimit = middlewares.RateLimitPolicy{
+		Name: "magic_link",
+		Policy: ratelimiter.Policy{
+			Algorithm: ratelimiter.FixedWindow,
+			Limit:     5,
+			Window:    15 * time.Minute,
+		},
+		Key:        middlewares.KeyByIP,
+		FailClosed: true,
+	}
+
+	// the callback signs users in, so it gets the same limit as login
+	magicLinkLoginRateLimit = middlewares.RateLimitPolicy{
+		Name: "magic_link_login",
+		Policy: ratelimiter.Policy{
+			Algorithm: ratelimiter.SlidingWindow,
+			Limit:     10,
+			Window:    15 * time.Minute,
+		},
+		Key:        middlewares.KeyByIP,
+		FailClosed: true,
+	}
+)
+
+type magicLinkController struct {
+	magicLinkService contracts.MagicLinkService
+}
+
+func InitMagicLinkController(
+	router fiber.Router,
+	magicLinkService contracts.MagicLinkService,
+	middleware *middlewares.Middleware,
+) {
+	controller := magicLinkController{
+		magicLinkService: magicLinkService,
+	}
+
+	authRoute := router.Group("/auth")
+	authRoute.Post("/magic-link", middleware.RateLimit(magicLinkRateLimit), controller.send)
+	authRoute.Post("/magic-link/callback", middleware.RateLimit(magicLinkLoginRateLimit), controller.login)
+}
+
+func (c *magicLinkController) send(ctx *fiber.Ctx) error {
+	var req dto.MagicLinkRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	req.IPAddress = ctx.IP()
+	req.UserAgent = ctx.Get(fiber.HeaderUserAgent)
+
+	if err := c.magicLinkService.Send(ctx.Context(), req); err != nil {
+		return err
+	}
+
+	return response.SendResponse(
+		ctx,
+		fiber.StatusAccepted,
+		"if the email is registered, a sign-in link has been sent",
+	)
+}
+
+func (c *magicLinkController) login(ctx *fiber.Ctx) error {
+	var req dto.MagicLinkLoginRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	req.IPAddress = ctx.IP()
+	req.UserAgent = ctx.Get(fiber.HeaderUserAgent)
+
+	res, err := c.magicLinkService.Login(ctx.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
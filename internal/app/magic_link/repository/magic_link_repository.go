@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+type magicLinkRepository struct {
+	db *sqlx.DB
+}
+
+func NewMagicLinkRepository(db *sqlx.DB) contracts.MagicLinkRepository {
+	return &magicLinkRepository{
+		db: db,
+	}
+}
+
+func (r *magicLinkRepository) Create(ctx context.Context, token *entity.MagicLinkToken) error {
+	query := `
+		INSERT INTO magic_link_tokens (id, user_id, token_hash, ip_address, user_agent_hash, expires_at)
+		VALUES (:id, :user_id, :token_hash, :ip_address, :user_agent_hash, :expires_at)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, token)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[MAGIC LINK REPOSITORY][Create] failed to create magic link token")
+		return err
+	}
+
+	return nil
+}
+
+func (r *magicLinkRepository) InvalidateByUserID(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE magic_link_tokens
+		SET used_at = NOW()
+		WHERE user_id = $1 AND used_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[MAGIC LINK REPOSITORY][InvalidateByUserID] failed to invalidate magic link tokens")
+		return err
+	}
+
+	return nil
+}
+
+// Consume marks the token as used and returns it in a single statement, so a
+// link can only ever be redeemed once even under concurrent requests.
+func (r *magicLinkRepository) Consume(
+	ctx context.Context,
+	tokenHash string,
+	now time.Time,
+) (entity.MagicLinkToken, error) {
+	query := `
+		UPDATE magic_link_tokens
+		SET used_at = $2
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+		RETURNING id, user_id, token_hash, ip_address, user_agent_hash, expires_at, used_at, created_at
+	`
+
+	var token entity.MagicLinkToken
+	err := r.db.GetContext(ctx, &token, query, tokenHash, now)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return token, domain.ErrInvalidMagicLinkToken
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[MAGIC LINK REPOSITORY][Consume] failed to consume magic link token")
+		return token, err
+	}
+
+	return token, nil
+}
+
+func (r *magicLinkRepository) DeleteExpired(ctx context.Context) error {
+	query := `DELETE FROM magic_link_tokens WHERE expires_at <= NOW()`
+
+	if _, err := r.db.ExecContext(ctx, query); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[MAGIC LINK REPOSITORY][DeleteExpired] failed to delete expired magic link tokens")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/mail"
+	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/token"
+	uuidPkg "github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
+)
+
+const magicLinkTokenSize = 32
+
+type magicLinkService struct {
+	magicLinkRepo        contracts.MagicLinkRepository
+	userRepo             contracts.UserRepository
+	mfaService           contracts.MFAService
+	loginThrottleService contracts.LoginThrottleService
+	validator            validator.ValidatorInterface
+	uuid                 uuidPkg.UUIDInterface
+	token                token.TokenInterface
+	mailService          contracts.MailService
+	time                 timePkg.TimeInterface
+}
+
+func NewMagicLinkService(
+	magicLinkRepo contracts.MagicLinkRepository,
+	userRepo contracts.UserRepository,
+	mfaService contracts.MFAService,
+	loginThrottleService contracts.LoginThrottleService,
+	validator validator.ValidatorInterface,
+	uuid uuidPkg.UUIDInterface,
+	token token.TokenInterface,
+	mailService contracts.MailService,
+	time timePkg.TimeInterface,
+) contracts.MagicLinkService {
+	return &magicLinkService{
+		magicLinkRepo:        magicLinkRepo,
+		userRepo:             userRepo,
+		mfaService:           mfaService,
+		loginThrottleService: loginThrottleService,
+		validator:            validator,
+		uuid:                 uuid,
+		token:                token,
+		mailService:          mailService,
+		time:                 time,
+	}
+}
+
+// Send succeeds whether or not the email is registered so the endpoint
+// can't be used to find out which accounts exist. Asking for a new link
+// invalidates the previous ones.
+func (s *magicLinkService) Send(ctx context.Context, req dto.MagicLinkRequest) error {
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return valErr
+	}
+
+	if err := s.loginThrottleService.Check(ctx, req.Email, req.IPAddress); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	if err := s.magicLinkRepo.InvalidateByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	id, err := s.uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	plain, err := s.token.Generate(magicLinkTokenSize)
+	if err != nil {
+		return err
+	}
+
+	magicLink := entity.MagicLinkToken{
+		ID:        id,
+		UserID:    user.ID,
+		TokenHash: s.token.Hash(plain),
+		ExpiresAt: s.time.Add(env.AppEnv.MagicLinkExpTime),
+	}
+
+	if req.BindIP {
+		magicLink.IPAddress = sql.NullString{String: req.IPAddress, Valid: true}
+	}
+
+	if req.BindDevice {
+		magicLink.UserAgentHash = sql.NullString{String: s.token.Hash(req.UserAgent), Valid: true}
+	}
+
+	if err := s.magicLinkRepo.Create(ctx, &magicLink); err != nil {
+		return err
+	}
+
+	err = s.mailService.Queue(ctx, user.Email, user.Locale, mail.TemplateMagicLink, mail.LinkData{
+		Name:      user.Name,
+		Link:      buildMagicLink(plain),
+		ExpiresIn: env.AppEnv.MagicLinkExpTime.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	s.audit(ctx, entity.AuditActionMagicLinkSent, uuid.NullUUID{}, user.ID, map[string]any{
+		"ip_address":  req.IPAddress,
+		"bind_ip":     req.BindIP,
+		"bind_device": req.BindDevice,
+	})
+
+	return nil
+}
+
+// Login signs the user in like a password login would, a link opened from
+// somewhere it wasn't bound to is burned and counted as a failed attempt.
+func (s *magicLinkService) Login(ctx context.Context, req dto.MagicLinkLoginRequest) (dto.LoginResponse, error) {
+	var res dto.LoginResponse
+
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return res, valErr
+	}
+
+	magicLink, err := s.magicLinkRepo.Consume(ctx, s.token.Hash(req.Token), s.time.Now())
+	if err != nil {
+		return res, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, magicLink.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return res, domain.ErrInvalidMagicLinkToken
+		}
+
+		return res, err
+	}
+
+	if err := s.loginThrottleService.Check(ctx, user.Email, req.IPAddress); err != nil {
+		return res, err
+	}
+
+	if !s.boundTo(magicLink, req) {
+		s.audit(ctx, entity.AuditActionMagicLinkRejected, uuid.NullUUID{}, user.ID, map[string]any{
+			"ip_address":    req.IPAddress,
+			"magic_link_id": magicLink.ID,
+		})
+
+		if err := s.loginThrottleService.RecordFailure(ctx, user.Email, req.IPAddress); err != nil {
+			return res, err
+		}
+
+		return res, domain.ErrInvalidMagicLinkToken
+	}
+
+	res, err = s.mfaService.SignIn(ctx, user, []string{jwt.AMRMagicLink})
+	if err != nil {
+		return res, err
+	}
+
+	s.audit(ctx, entity.AuditActionMagicLinkLogin, uuid.NullUUID{UUID: user.ID, Valid: true}, user.ID, map[string]any{
+		"ip_address":    req.IPAddress,
+		"magic_link_id": magicLink.ID,
+		"mfa_required":  res.MFARequired,
+	})
+
+	if !res.MFARequired {
+		if err := s.loginThrottleService.RecordSuccess(ctx, user.Email); err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+func (s *magicLinkService) boundTo(magicLink entity.MagicLinkToken, req dto.MagicLinkLoginRequest) bool {
+	if magicLink.IPAddress.Valid && magicLink.IPAddress.String != req.IPAddress {
+		return false
+	}
+
+	if magicLink.UserAgentHash.Valid && magicLink.UserAgentHash.String != s.token.Hash(req.UserAgent) {
+		return false
+	}
+
+	return true
+}
+
+// audit is best effort, a sign in is not refused because its record could
+// not be written. The actor is only set once the user has signed in.
+func (s *magicLinkService) audit(
+	ctx context.Context,
+	action string,
+	actorID uuid.NullUUID,
+	userID uuid.UUID,
+	details map[string]any,
+) {
+	if err := s.createAuditLog(ctx, action, actorID, userID, details); err != nil {
+		log.Warn(log.LogInfo{
+			"error":   err.Error(),
+			"action":  action,
+			"user_id": userID,
+		}, "[MAGIC LINK SERVICE][audit] failed to write audit log")
+	}
+}
+
+func (s *magicLinkService) createAuditLog(
+	ctx context.Context,
+	action string,
+	actorID uuid.NullUUID,
+	userID uuid.UUID,
+	details map[string]any,
+) error {
+	metadata, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+
+	auditID, err := s.uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	return s.userRepo.CreateAuditLog(ctx, &entity.AuditLog{
+		ID:        auditID,
+		ActorID:   actorID,
+		Action:    action,
+		SubjectID: uuid.NullUUID{UUID: userID, Valid: true},
+		Metadata:  metadata,
+	})
+}
+
+func buildMagicLink(plain string) string {
+	return fmt.Sprintf(
+		"%s/magic-link?token=%s",
+		strings.TrimRight(env.AppEnv.FrontendURL, "/"),
+		url.QueryEscape(plain),
+	)
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+type mailOutboxRepository struct {
+	db *sqlx.DB
+}
+
+func NewMailOutboxRepository(db *sqlx.DB) contracts.MailOutboxRepository {
+	return &mailOutboxRepository{
+		db: db,
+	}
+}
+
+func (r *mailOutboxRepository) Create(ctx context.Context, mail *entity.MailOutbox) error {
+	query := `
+		INSERT INTO mail_outbox (id, recipient, subject, text_body, html_body, status, next_attempt_at)
+		VALUES (:id, :recipient, :subject, :text_body, :html_body, :status, :next_attempt_at)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, mail)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[MAIL OUTBOX REPOSITORY][Create] failed to queue mail")
+		return err
+	}
+
+	return nil
+}
+
+func (r *mailOutboxRepository) Claim(
+	ctx context.Context,
+	limit int,
+	now time.Time,
+	leaseUntil time.Time,
+) ([]entity.MailOutbox, error) {
+	query := `
+		UPDATE mail_outbox
+		SET next_attempt_at = $3, attempts = attempts + 1
+		WHERE id IN (
+			SELECT id
+			FROM mail_outbox
+			WHERE status = 'pending' AND next_attempt_at <= $2
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, recipient, subject, text_body, html_body, status, attempts,
+			last_error, next_attempt_at, sent_at, created_at
+	`
+
+	mails := make([]entity.MailOutbox, 0)
+	err := r.db.SelectContext(ctx, &mails, query, limit, now, leaseUntil)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[MAIL OUTBOX REPOSITORY][Claim] failed to claim pending mails")
+		return nil, err
+	}
+
+	return mails, nil
+}
+
+// MarkSent and MarkFailed clear the bodies, they hold working links whose
+// tokens are only stored hashed everywhere else
+func (r *mailOutboxRepository) MarkSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error {
+	query := `
+		UPDATE mail_outbox
+		SET status = 'sent', sent_at = $2, last_error = NULL, text_body = '', html_body = ''
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id, sentAt)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    id,
+		}, "[MAIL OUTBOX REPOSITORY][MarkSent] failed to mark mail as sent")
+		return err
+	}
+
+	return nil
+}
+
+func (r *mailOutboxRepository) MarkRetry(
+	ctx context.Context,
+	id uuid.UUID,
+	lastError string,
+	nextAttemptAt time.Time,
+) error {
+	query := `
+		UPDATE mail_outbox
+		SET last_error = $2, next_attempt_at = $3
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id, lastError, nextAttemptAt)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    id,
+		}, "[MAIL OUTBOX REPOSITORY][MarkRetry] failed to reschedule mail")
+		return err
+	}
+
+	return nil
+}
+
+func (r *mailOutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error {
+	query := `
+		UPDATE mail_outbox
+		SET status = 'failed', last_error = $2, text_body = '', html_body = ''
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id, lastError)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    id,
+		}, "[MAIL OUTBOX REPOSITORY][MarkFailed] failed to mark mail as failed")
+		return err
+	}
+
+	return nil
+}
+
+func (r *mailOutboxRepository) DeleteFinished(ctx context.Context, before time.Time) error {
+	query := `DELETE FROM mail_outbox WHERE status <> 'pending' AND created_at < $1`
+
+	if _, err := r.db.ExecContext(ctx, query, before); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[MAIL OUTBOX REPOSITORY][DeleteFinished] failed to delete finished mails")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/mail"
+	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+)
+
+const (
+	outboxBatchSize   = 20
+	outboxSendTimeout = 30 * time.Second
+	outboxMaxAttempts = 6
+	outboxBaseBackoff = 30 * time.Second
+	outboxMaxBackoff  = time.Hour
+)
+
+type mailService struct {
+	mailOutboxRepo contracts.MailOutboxRepository
+	mail           mail.MailInterface
+	template       mail.TemplateInterface
+	uuid           uuid.UUIDInterface
+	time           timePkg.TimeInterface
+}
+
+func NewMailService(
+	mailOutboxRepo contracts.MailOutboxRepository,
+	mail mail.MailInterface,
+	template mail.TemplateInterface,
+	uuid uuid.UUIDInterface,
+	time timePkg.TimeInterface,
+) contracts.MailService {
+	return &mailService{
+		mailOutboxRepo: mailOutboxRepo,
+		mail:           mail,
+		template:       template,
+		uuid:           uuid,
+		time:           time,
+	}
+}
+
+// Queue renders the template and stores the mail in the outbox, delivery is
+// left to ProcessOutbox so a slow or failing mail server never affects the
+// request that triggered the mail.
+func (s *mailService) Queue(
+	ctx context.Context,
+	to string,
+	locale string,
+	template string,
+	data any,
+) error {
+	if locale == "" {
+		locale = mail.DefaultLocale
+	}
+
+	msg, err := s.template.Render(locale, template, data)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":    err.Error(),
+			"template": template,
+		}, "[MAIL SERVICE][Queue] failed to render mail template")
+		return err
+	}
+
+	id, err := s.uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	return s.mailOutboxRepo.Create(ctx, &entity.MailOutbox{
+		ID:            id,
+		Recipient:     to,
+		Subject:       msg.Subject,
+		TextBody:      msg.Text,
+		HTMLBody:      msg.HTML,
+		Status:        entity.MailStatusPending,
+		NextAttemptAt: s.time.Now(),
+	})
+}
+
+// ProcessOutbox delivers up to one batch of due mails. Mails are claimed one
+// at a time and leased for longer than a send can take, so a lease never runs
+// out while its mail is being sent and a replica dying mid-send only delays it.
+func (s *mailService) ProcessOutbox(ctx context.Context) error {
+	for range outboxBatchSize {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		now := s.time.Now()
+
+		mails, err := s.mailOutboxRepo.Claim(ctx, 1, now, now.Add(2*outboxSendTimeout))
+		if err != nil {
+			return err
+		}
+
+		if len(mails) == 0 {
+			return nil
+		}
+
+		s.deliver(ctx, mails[0])
+	}
+
+	return nil
+}
+
+func (s *mailService) deliver(ctx context.Context, outbox entity.MailOutbox) {
+	sendCtx, cancel := context.WithTimeout(ctx, outboxSendTimeout)
+	defer cancel()
+
+	err := s.mail.Send(sendCtx, mail.Message{
+		To:      []string{outbox.Recipient},
+		Subject: outbox.Subject,
+		Text:    outbox.TextBody,
+		HTML:    outbox.HTMLBody,
+	})
+	if err == nil {
+		_ = s.mailOutboxRepo.MarkSent(ctx, outbox.ID, s.time.Now())
+		return
+	}
+
+	if outbox.Attempts >= outboxMaxAttempts {
+		log.Error(log.LogInfo{
+			"error":    err.Error(),
+			"id":       outbox.ID,
+			"attempts": outbox.Attempts,
+		}, "[MAIL SERVICE][deliver] giving up on mail")
+
+		_ = s.mailOutboxRepo.MarkFailed(ctx, outbox.ID, err.Error())
+		return
+	}
+
+	_ = s.mailOutboxRepo.MarkRetry(ctx, outbox.ID, err.Error(), s.time.Add(backoff(outbox.Attempts)))
+}
+
+// PurgeFinished drops sent and failed mails once the retention is over, a
+// retention of zero keeps them
+func (s *mailService) PurgeFinished(ctx context.Context) error {
+	retention := env.AppEnv.MailOutboxRetention
+	if retention <= 0 {
+		return nil
+	}
+
+	return s.mailOutboxRepo.DeleteFinished(ctx, s.time.Now().Add(-retention))
+}
+
+// backoff doubles the delay after every failed attempt, 30s, 1m, 2m, ...
+func backoff(attempts int) time.Duration {
+	delay := outboxBaseBackoff
+	for i := 1; i < attempts && delay < outboxMaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, outboxMaxBackoff)
+}
@@ -0,0 +1,161 @@
+package rest
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/internal/middlewares"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers/http/response"
+	ratelimiter "github.com/kelompok1-swe-academya/caper-be/pkg/rate_limiter"
+)
+
+var (
+	mfaVerifyRateLimit = middlewares.RateLimitPolicy{
+		Name: "mfa_verify",
+		Policy: ratelimiter.Policy{
+			Algorithm: ratelimiter.SlidingWindow,
+			Limit:     10,
+			Window:    15 * time.Minute,
+		},
+		Key:        middlewares.KeyByIP,
+		FailClosed: true,
+	}
+
+	// keyed by user so a stolen session can't spread its guesses over ips
+	mfaStepUpRateLimit = middlewares.RateLimitPolicy{
+		Name: "mfa_step_up",
+		Policy: ratelimiter.Policy{
+			Algorithm: ratelimiter.SlidingWindow,
+			Limit:     5,
+			Window:    15 * time.Minute,
+		},
+		Key:        middlewares.KeyByUser,
+		FailClosed: true,
+	}
+)
+
+type mfaController struct {
+	mfaService contracts.MFAService
+}
+
+func InitMFAController(
+	router fiber.Router,
+	mfaService contracts.MFAService,
+	middleware *middlewares.Middleware,
+) {
+	controller := mfaController{
+		mfaService: mfaService,
+	}
+
+	mfaRoute := router.Group("/auth/mfa")
+	mfaRoute.Post("/verify", middleware.RateLimit(mfaVerifyRateLimit), controller.verify)
+
+	mfaRoute.Post("/totp/enroll", middleware.RequireAuth(), controller.enrollTotp)
+	mfaRoute.Post("/totp/confirm", middleware.RequireAuth(), controller.confirmTotp)
+	mfaRoute.Delete(
+		"/totp",
+		middleware.RequireAuth(),
+		middleware.RateLimit(mfaStepUpRateLimit),
+		controller.disableTotp,
+	)
+	mfaRoute.Post(
+		"/recovery-codes",
+		middleware.RequireAuth(),
+		middleware.RateLimit(mfaStepUpRateLimit),
+		controller.regenerateRecoveryCodes,
+	)
+}
+
+func (c *mfaController) verify(ctx *fiber.Ctx) error {
+	var req dto.MFAVerifyRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	req.IPAddress = ctx.IP()
+
+	res, err := c.mfaService.Verify(ctx.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
+
+func (c *mfaController) enrollTotp(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.mfaService.EnrollTotp(ctx.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
+
+func (c *mfaController) confirmTotp(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	var req dto.TotpCodeRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	res, err := c.mfaService.ConfirmTotp(ctx.Context(), claims.UserID, req)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
+
+func (c *mfaController) disableTotp(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	var req dto.MFAStepUpRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	req.IPAddress = ctx.IP()
+
+	if err := c.mfaService.DisableTotp(ctx.Context(), claims.UserID, req); err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, "two-factor authentication has been disabled")
+}
+
+func (c *mfaController) regenerateRecoveryCodes(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	var req dto.MFAStepUpRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	req.IPAddress = ctx.IP()
+
+	res, err := c.mfaService.RegenerateRecoveryCodes(ctx.Context(), claims.UserID, req)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
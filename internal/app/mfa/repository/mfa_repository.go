@@ -0,0 +1,355 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+type mfaRepository struct {
+	db *sqlx.DB
+}
+
+func NewMFARepository(db *sqlx.DB) contracts.MFARepository {
+	return &mfaRepository{
+		db: db,
+	}
+}
+
+func (r *mfaRepository) FindTotp(ctx context.Context, userID uuid.UUID) (entity.UserTotp, error) {
+	query := `
+		SELECT user_id, secret, last_used_step, confirmed_at, created_at
+		FROM user_totp
+		WHERE user_id = $1
+	`
+
+	var totp entity.UserTotp
+	err := r.db.GetContext(ctx, &totp, query, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return totp, domain.ErrMFAEnrollmentNotFound
+		}
+
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[MFA REPOSITORY][FindTotp] failed to find totp enrollment")
+		return totp, err
+	}
+
+	return totp, nil
+}
+
+// SaveTotp starts a new enrollment, replacing one that was never confirmed.
+// A confirmed enrollment is left untouched.
+func (r *mfaRepository) SaveTotp(ctx context.Context, totp *entity.UserTotp) error {
+	query := `
+		INSERT INTO user_totp (user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET
+			secret = EXCLUDED.secret,
+			last_used_step = 0,
+			created_at = CURRENT_TIMESTAMP
+		WHERE user_totp.confirmed_at IS NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, query, totp.UserID, totp.Secret)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": totp.UserID,
+		}, "[MFA REPOSITORY][SaveTotp] failed to save totp enrollment")
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrMFAAlreadyEnabled
+	}
+
+	return nil
+}
+
+func (r *mfaRepository) ConfirmTotp(ctx context.Context, userID uuid.UUID, step int64, now time.Time) error {
+	query := `
+		UPDATE user_totp
+		SET confirmed_at = $3, last_used_step = $2
+		WHERE user_id = $1 AND confirmed_at IS NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, query, userID, step, now)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[MFA REPOSITORY][ConfirmTotp] failed to confirm totp enrollment")
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrMFAAlreadyEnabled
+	}
+
+	return nil
+}
+
+// UseTotpStep records the time step of an accepted code. Only a later step
+// than the last used one is accepted, so a code can't be replayed.
+func (r *mfaRepository) UseTotpStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	query := `
+		UPDATE user_totp
+		SET last_used_step = $2
+		WHERE user_id = $1 AND last_used_step < $2
+	`
+
+	res, err := r.db.ExecContext(ctx, query, userID, step)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[MFA REPOSITORY][UseTotpStep] failed to record totp step")
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+func (r *mfaRepository) DeleteTotp(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[MFA REPOSITORY][DeleteTotp] failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[MFA REPOSITORY][DeleteTotp] failed to delete recovery codes")
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_totp WHERE user_id = $1`, userID); err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[MFA REPOSITORY][DeleteTotp] failed to delete totp enrollment")
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[MFA REPOSITORY][DeleteTotp] failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func (r *mfaRepository) ReplaceRecoveryCodes(
+	ctx context.Context,
+	userID uuid.UUID,
+	codes []entity.MFARecoveryCode,
+) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[MFA REPOSITORY][ReplaceRecoveryCodes] failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[MFA REPOSITORY][ReplaceRecoveryCodes] failed to delete recovery codes")
+		return err
+	}
+
+	query := `
+		INSERT INTO mfa_recovery_codes (id, user_id, code_hash)
+		VALUES (:id, :user_id, :code_hash)
+	`
+
+	if _, err := tx.NamedExecContext(ctx, query, codes); err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[MFA REPOSITORY][ReplaceRecoveryCodes] failed to create recovery codes")
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[MFA REPOSITORY][ReplaceRecoveryCodes] failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func (r *mfaRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string, now time.Time) error {
+	query := `
+		UPDATE mfa_recovery_codes
+		SET used_at = $3
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, query, userID, codeHash, now)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[MFA REPOSITORY][UseRecoveryCode] failed to use recovery code")
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+func (r *mfaRepository) CreateChallenge(ctx context.Context, challenge *entity.MFAChallenge) error {
+	query := `
+		INSERT INTO mfa_challenges (id, user_id, token_hash, amr, expires_at)
+		VALUES (:id, :user_id, :token_hash, :amr, :expires_at)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, challenge)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[MFA REPOSITORY][CreateChallenge] failed to create mfa challenge")
+		return err
+	}
+
+	return nil
+}
+
+func (r *mfaRepository) FindChallenge(ctx context.Context, tokenHash string, now time.Time) (entity.MFAChallenge, error) {
+	query := `
+		SELECT id, user_id, token_hash, amr, attempts, expires_at, created_at
+		FROM mfa_challenges
+		WHERE token_hash = $1
+	`
+
+	var challenge entity.MFAChallenge
+	err := r.db.GetContext(ctx, &challenge, query, tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return challenge, domain.ErrInvalidMFAToken
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[MFA REPOSITORY][FindChallenge] failed to find mfa challenge")
+		return challenge, err
+	}
+
+	if !now.Before(challenge.ExpiresAt) {
+		return challenge, domain.ErrInvalidMFAToken
+	}
+
+	return challenge, nil
+}
+
+func (r *mfaRepository) IncrementChallengeAttempts(ctx context.Context, id uuid.UUID) (int, error) {
+	query := `
+		UPDATE mfa_challenges
+		SET attempts = attempts + 1
+		WHERE id = $1
+		RETURNING attempts
+	`
+
+	var attempts int
+	err := r.db.GetContext(ctx, &attempts, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, domain.ErrInvalidMFAToken
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[MFA REPOSITORY][IncrementChallengeAttempts] failed to count mfa attempt")
+		return 0, err
+	}
+
+	return attempts, nil
+}
+
+// DeleteChallenge fails with ErrInvalidMFAToken when the challenge is already
+// gone, so of two concurrent verifications only one completes the login
+func (r *mfaRepository) DeleteChallenge(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM mfa_challenges WHERE id = $1`, id)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[MFA REPOSITORY][DeleteChallenge] failed to delete mfa challenge")
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrInvalidMFAToken
+	}
+
+	return nil
+}
+
+func (r *mfaRepository) DeleteExpiredChallenges(ctx context.Context) error {
+	query := `DELETE FROM mfa_challenges WHERE expires_at <= NOW()`
+
+	if _, err := r.db.ExecContext(ctx, query); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[MFA REPOSITORY][DeleteExpiredChallenges] failed to delete expired mfa challenges")
+		return err
+	}
+
+	return nil
+}
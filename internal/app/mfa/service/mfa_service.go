@@ -0,0 +1,460 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/bcrypt"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
+	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/token"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/totp"
+	uuidPkg "github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
+)
+
+const (
+	mfaTokenSize         = 32
+	mfaChallengeExpTime  = 5 * time.Minute
+	mfaChallengeAttempts = 5
+	recoveryCodeCount    = 10
+	recoveryCodeSize     = 10
+)
+
+type mfaService struct {
+	mfaRepo              contracts.MFARepository
+	userRepo             contracts.UserRepository
+	sessionService       contracts.SessionService
+	loginThrottleService contracts.LoginThrottleService
+	validator            validator.ValidatorInterface
+	uuid                 uuidPkg.UUIDInterface
+	bcrypt               bcrypt.BcryptInterface
+	token                token.TokenInterface
+	totp                 totp.TotpInterface
+	time                 timePkg.TimeInterface
+}
+
+func NewMFAService(
+	mfaRepo contracts.MFARepository,
+	userRepo contracts.UserRepository,
+	sessionService contracts.SessionService,
+	loginThrottleService contracts.LoginThrottleService,
+	validator validator.ValidatorInterface,
+	uuid uuidPkg.UUIDInterface,
+	bcrypt bcrypt.BcryptInterface,
+	token token.TokenInterface,
+	totp totp.TotpInterface,
+	time timePkg.TimeInterface,
+) contracts.MFAService {
+	return &mfaService{
+		mfaRepo:              mfaRepo,
+		userRepo:             userRepo,
+		sessionService:       sessionService,
+		loginThrottleService: loginThrottleService,
+		validator:            validator,
+		uuid:                 uuid,
+		bcrypt:               bcrypt,
+		token:                token,
+		totp:                 totp,
+		time:                 time,
+	}
+}
+
+// SignIn completes a login that passed its first factor. Users with a
+// confirmed authenticator get an mfa token instead of a session.
+func (s *mfaService) SignIn(ctx context.Context, user entity.User, amr []string) (dto.LoginResponse, error) {
+	var res dto.LoginResponse
+
+	enrollment, err := s.mfaRepo.FindTotp(ctx, user.ID)
+	if err != nil && !errors.Is(err, domain.ErrMFAEnrollmentNotFound) {
+		return res, err
+	}
+
+	if err != nil || !enrollment.ConfirmedAt.Valid {
+		token, err := s.sessionService.Issue(ctx, user, amr)
+		if err != nil {
+			return res, err
+		}
+
+		res.TokenResponse = &token
+		return res, nil
+	}
+
+	id, err := s.uuid.NewV7()
+	if err != nil {
+		return res, err
+	}
+
+	plain, err := s.token.Generate(mfaTokenSize)
+	if err != nil {
+		return res, err
+	}
+
+	challenge := entity.MFAChallenge{
+		ID:        id,
+		UserID:    user.ID,
+		TokenHash: s.token.Hash(plain),
+		AMR:       strings.Join(amr, " "),
+		ExpiresAt: s.time.Add(mfaChallengeExpTime),
+	}
+
+	if err := s.mfaRepo.CreateChallenge(ctx, &challenge); err != nil {
+		return res, err
+	}
+
+	res.MFARequired = true
+	res.MFAToken = plain
+	res.MFAExpiresIn = int64(mfaChallengeExpTime.Seconds())
+
+	return res, nil
+}
+
+// Verify exchanges an mfa token and a second factor for a session. Failed
+// codes count towards the login lockout of the account, and a challenge is
+// dropped after a few of them so a new one needs the first factor again.
+func (s *mfaService) Verify(ctx context.Context, req dto.MFAVerifyRequest) (dto.LoginResponse, error) {
+	var res dto.LoginResponse
+
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return res, valErr
+	}
+
+	challenge, err := s.mfaRepo.FindChallenge(ctx, s.token.Hash(req.MFAToken), s.time.Now())
+	if err != nil {
+		return res, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return res, domain.ErrInvalidMFAToken
+		}
+
+		return res, err
+	}
+
+	if err := s.loginThrottleService.Check(ctx, user.Email, req.IPAddress); err != nil {
+		return res, err
+	}
+
+	method, err := s.verifyCode(ctx, user.ID, req.MFACodeRequest)
+	if err != nil {
+		if !errors.Is(err, domain.ErrInvalidMFACode) {
+			return res, err
+		}
+
+		return res, s.failChallenge(ctx, challenge, user, req.IPAddress)
+	}
+
+	if err := s.mfaRepo.DeleteChallenge(ctx, challenge.ID); err != nil {
+		return res, err
+	}
+
+	if err := s.loginThrottleService.RecordSuccess(ctx, user.Email); err != nil {
+		return res, err
+	}
+
+	amr := append(strings.Fields(challenge.AMR), method)
+	if !slices.Contains(amr, jwt.AMRMultiFactor) {
+		amr = append(amr, jwt.AMRMultiFactor)
+	}
+
+	token, err := s.sessionService.Issue(ctx, user, amr)
+	if err != nil {
+		return res, err
+	}
+
+	res.TokenResponse = &token
+
+	return res, nil
+}
+
+// EnrollTotp starts over any enrollment that was not confirmed yet, the
+// secret is only shown here
+func (s *mfaService) EnrollTotp(ctx context.Context, userID uuid.UUID) (dto.TotpEnrollResponse, error) {
+	var res dto.TotpEnrollResponse
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return res, err
+	}
+
+	secret, uri, err := s.totp.Generate(user.Email)
+	if err != nil {
+		return res, err
+	}
+
+	sealed, err := s.totp.Seal(secret)
+	if err != nil {
+		return res, err
+	}
+
+	enrollment := entity.UserTotp{
+		UserID: user.ID,
+		Secret: sealed,
+	}
+
+	if err := s.mfaRepo.SaveTotp(ctx, &enrollment); err != nil {
+		return res, err
+	}
+
+	res.Secret = secret
+	res.URI = uri
+
+	return res, nil
+}
+
+// ConfirmTotp turns the enrollment on once the user proves their
+// authenticator works and hands out the first set of recovery codes
+func (s *mfaService) ConfirmTotp(
+	ctx context.Context,
+	userID uuid.UUID,
+	req dto.TotpCodeRequest,
+) (dto.RecoveryCodesResponse, error) {
+	var res dto.RecoveryCodesResponse
+
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return res, valErr
+	}
+
+	enrollment, err := s.mfaRepo.FindTotp(ctx, userID)
+	if err != nil {
+		return res, err
+	}
+
+	if enrollment.ConfirmedAt.Valid {
+		return res, domain.ErrMFAAlreadyEnabled
+	}
+
+	step, err := s.validateTotp(enrollment, req.Code)
+	if err != nil {
+		return res, err
+	}
+
+	if err := s.mfaRepo.ConfirmTotp(ctx, userID, step, s.time.Now()); err != nil {
+		return res, err
+	}
+
+	return s.replaceRecoveryCodes(ctx, userID)
+}
+
+func (s *mfaService) DisableTotp(ctx context.Context, userID uuid.UUID, req dto.MFAStepUpRequest) error {
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return valErr
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.stepUp(ctx, user, req); err != nil {
+		return err
+	}
+
+	return s.mfaRepo.DeleteTotp(ctx, userID)
+}
+
+// RegenerateRecoveryCodes invalidates every previous recovery code
+func (s *mfaService) RegenerateRecoveryCodes(
+	ctx context.Context,
+	userID uuid.UUID,
+	req dto.MFAStepUpRequest,
+) (dto.RecoveryCodesResponse, error) {
+	var res dto.RecoveryCodesResponse
+
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return res, valErr
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return res, err
+	}
+
+	if err := s.stepUp(ctx, user, req); err != nil {
+		return res, err
+	}
+
+	return s.replaceRecoveryCodes(ctx, userID)
+}
+
+// Reauthenticate takes a second factor when one is given and the current
+// password otherwise
+func (s *mfaService) Reauthenticate(ctx context.Context, userID uuid.UUID, req dto.ReauthRequest) error {
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return valErr
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if req.Code == "" && req.RecoveryCode == "" {
+		return s.throttle(ctx, user, req.IPAddress, func() error {
+			if !s.bcrypt.Compare(req.CurrentPassword, user.Password) {
+				return domain.ErrCredentialsNotMatch
+			}
+
+			return nil
+		})
+	}
+
+	return s.stepUp(ctx, user, dto.MFAStepUpRequest{
+		MFACodeRequest: dto.MFACodeRequest{
+			Code:         req.Code,
+			RecoveryCode: req.RecoveryCode,
+		},
+		IPAddress: req.IPAddress,
+	})
+}
+
+// stepUp checks the second factor of a signed in user
+func (s *mfaService) stepUp(ctx context.Context, user entity.User, req dto.MFAStepUpRequest) error {
+	return s.throttle(ctx, user, req.IPAddress, func() error {
+		_, err := s.verifyCode(ctx, user.ID, req.MFACodeRequest)
+		return err
+	})
+}
+
+// throttle counts a wrong password or code of a signed in user towards the
+// same lockout as failed logins, so a stolen access token can't be used to
+// guess them
+func (s *mfaService) throttle(ctx context.Context, user entity.User, ip string, verify func() error) error {
+	if err := s.loginThrottleService.Check(ctx, user.Email, ip); err != nil {
+		return err
+	}
+
+	if err := verify(); err != nil {
+		if !errors.Is(err, domain.ErrInvalidMFACode) && !errors.Is(err, domain.ErrCredentialsNotMatch) {
+			return err
+		}
+
+		if recordErr := s.loginThrottleService.RecordFailure(ctx, user.Email, ip); recordErr != nil {
+			return recordErr
+		}
+
+		return err
+	}
+
+	return s.loginThrottleService.RecordSuccess(ctx, user.Email)
+}
+
+// verifyCode checks a second factor of a user with a confirmed enrollment
+// and returns the amr value of the method that was used
+func (s *mfaService) verifyCode(ctx context.Context, userID uuid.UUID, req dto.MFACodeRequest) (string, error) {
+	enrollment, err := s.mfaRepo.FindTotp(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMFAEnrollmentNotFound) {
+			return "", domain.ErrMFANotEnabled
+		}
+
+		return "", err
+	}
+
+	if !enrollment.ConfirmedAt.Valid {
+		return "", domain.ErrMFANotEnabled
+	}
+
+	if req.Code == "" {
+		codeHash := s.token.Hash(strings.TrimSpace(req.RecoveryCode))
+		if err := s.mfaRepo.UseRecoveryCode(ctx, userID, codeHash, s.time.Now()); err != nil {
+			return "", err
+		}
+
+		return jwt.AMRRecoveryCode, nil
+	}
+
+	step, err := s.validateTotp(enrollment, req.Code)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.mfaRepo.UseTotpStep(ctx, userID, step); err != nil {
+		return "", err
+	}
+
+	return jwt.AMROTP, nil
+}
+
+func (s *mfaService) validateTotp(enrollment entity.UserTotp, code string) (int64, error) {
+	secret, err := s.totp.Open(enrollment.Secret)
+	if err != nil {
+		return 0, err
+	}
+
+	step, ok := s.totp.Validate(code, secret, s.time.Now())
+	if !ok || step <= enrollment.LastUsedStep {
+		return 0, domain.ErrInvalidMFACode
+	}
+
+	return step, nil
+}
+
+func (s *mfaService) failChallenge(
+	ctx context.Context,
+	challenge entity.MFAChallenge,
+	user entity.User,
+	ip string,
+) error {
+	if err := s.loginThrottleService.RecordFailure(ctx, user.Email, ip); err != nil {
+		return err
+	}
+
+	attempts, err := s.mfaRepo.IncrementChallengeAttempts(ctx, challenge.ID)
+	if err != nil {
+		return err
+	}
+
+	if attempts >= mfaChallengeAttempts {
+		if err := s.mfaRepo.DeleteChallenge(ctx, challenge.ID); err != nil && !errors.Is(err, domain.ErrInvalidMFAToken) {
+			return err
+		}
+	}
+
+	return domain.ErrInvalidMFACode
+}
+
+func (s *mfaService) replaceRecoveryCodes(ctx context.Context, userID uuid.UUID) (dto.RecoveryCodesResponse, error) {
+	var res dto.RecoveryCodesResponse
+
+	plains := make([]string, 0, recoveryCodeCount)
+	codes := make([]entity.MFARecoveryCode, 0, recoveryCodeCount)
+
+	for range recoveryCodeCount {
+		id, err := s.uuid.NewV7()
+		if err != nil {
+			return res, err
+		}
+
+		plain, err := s.token.Generate(recoveryCodeSize)
+		if err != nil {
+			return res, err
+		}
+
+		plains = append(plains, plain)
+		codes = append(codes, entity.MFARecoveryCode{
+			ID:       id,
+			UserID:   userID,
+			CodeHash: s.token.Hash(plain),
+		})
+	}
+
+	if err := s.mfaRepo.ReplaceRecoveryCodes(ctx, userID, codes); err != nil {
+		return res, err
+	}
+
+	res.RecoveryCodes = plains
+
+	return res, nil
+}
@@ -0,0 +1,83 @@
+package rest
+
+import (
+	"crypto/subtle"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers/http/response"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/mail"
+)
+
+const (
+	GoogleLoginPath    = "/api/v1/auth/google/login"
+	GoogleCallbackPath = "/api/v1/auth/google/callback"
+)
+
+const (
+	stateCookieName   = "oauth_state"
+	stateCookieMaxAge = 10 * time.Minute
+)
+
+type oauthController struct {
+	oauthService contracts.OAuthService
+}
+
+func InitOAuthController(router fiber.Router, oauthService contracts.OAuthService) {
+	controller := oauthController{
+		oauthService: oauthService,
+	}
+
+	googleRoute := router.Group("/auth/google")
+	googleRoute.Get("/login", controller.googleLogin)
+	googleRoute.Get("/callback", controller.googleCallback)
+}
+
+// googleLogin binds the state to the browser with a cookie so a callback
+// started by somebody else can't log the user into the wrong account.
+func (c *oauthController) googleLogin(ctx *fiber.Ctx) error {
+	res, err := c.oauthService.GoogleLogin(ctx.Context())
+	if err != nil {
+		return err
+	}
+
+	ctx.Cookie(&fiber.Cookie{
+		Name:     stateCookieName,
+		Value:    res.State,
+		Path:     GoogleCallbackPath,
+		MaxAge:   int(stateCookieMaxAge.Seconds()),
+		Secure:   env.AppEnv.AppEnv != "development",
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+
+	return ctx.Redirect(res.URL, fiber.StatusFound)
+}
+
+func (c *oauthController) googleCallback(ctx *fiber.Ctx) error {
+	var req dto.OAuthCallbackRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	cookieState := ctx.Cookies(stateCookieName)
+	ctx.ClearCookie(stateCookieName)
+
+	if cookieState == "" || subtle.ConstantTimeCompare([]byte(cookieState), []byte(req.State)) != 1 {
+		return domain.ErrInvalidOAuthState
+	}
+
+	req.Locale = ctx.AcceptsLanguages(mail.Locales...)
+
+	res, err := c.oauthService.GoogleCallback(ctx.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+type oauthRepository struct {
+	db *sqlx.DB
+}
+
+func NewOAuthRepository(db *sqlx.DB) contracts.OAuthRepository {
+	return &oauthRepository{
+		db: db,
+	}
+}
+
+func (r *oauthRepository) CreateState(ctx context.Context, state *entity.OAuthState) error {
+	query := `
+		INSERT INTO oauth_states (id, state_hash, nonce, code_verifier, expires_at)
+		VALUES (:id, :state_hash, :nonce, :code_verifier, :expires_at)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, state)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[OAUTH REPOSITORY][CreateState] failed to create oauth state")
+		return err
+	}
+
+	return nil
+}
+
+// ConsumeState deletes the state while reading it so an authorization
+// response can't be replayed.
+func (r *oauthRepository) ConsumeState(ctx context.Context, stateHash string, now time.Time) (entity.OAuthState, error) {
+	query := `
+		DELETE FROM oauth_states
+		WHERE state_hash = $1
+		RETURNING id, state_hash, nonce, code_verifier, expires_at, created_at
+	`
+
+	var state entity.OAuthState
+	err := r.db.GetContext(ctx, &state, query, stateHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return state, domain.ErrInvalidOAuthState
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[OAUTH REPOSITORY][ConsumeState] failed to consume oauth state")
+		return state, err
+	}
+
+	if !now.Before(state.ExpiresAt) {
+		return state, domain.ErrInvalidOAuthState
+	}
+
+	return state, nil
+}
+
+func (r *oauthRepository) DeleteExpiredStates(ctx context.Context) error {
+	query := `DELETE FROM oauth_states WHERE expires_at <= NOW()`
+
+	if _, err := r.db.ExecContext(ctx, query); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[OAUTH REPOSITORY][DeleteExpiredStates] failed to delete expired oauth states")
+		return err
+	}
+
+	return nil
+}
+
+func (r *oauthRepository) FindIdentity(ctx context.Context, provider string, subject string) (entity.UserIdentity, error) {
+	query := `
+		SELECT id, user_id, provider, subject, email, created_at
+		FROM user_identities
+		WHERE provider = $1 AND subject = $2
+	`
+
+	var identity entity.UserIdentity
+	err := r.db.GetContext(ctx, &identity, query, provider, subject)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return identity, domain.ErrUserIdentityNotFound
+		}
+
+		log.Error(log.LogInfo{
+			"error":    err.Error(),
+			"provider": provider,
+		}, "[OAUTH REPOSITORY][FindIdentity] failed to find user identity")
+		return identity, err
+	}
+
+	return identity, nil
+}
+
+func (r *oauthRepository) CreateIdentity(ctx context.Context, identity *entity.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (id, user_id, provider, subject, email)
+		VALUES (:id, :user_id, :provider, :subject, :email)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, identity)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":    err.Error(),
+			"user_id":  identity.UserID,
+			"provider": identity.Provider,
+		}, "[OAUTH REPOSITORY][CreateIdentity] failed to create user identity")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/bcrypt"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/mail"
+	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/token"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
+)
+
+const (
+	oauthStateSize       = 32
+	oauthStateExpTime    = 10 * time.Minute
+	unusablePasswordSize = 32
+	providerHTTPTimeout  = 10 * time.Second
+)
+
+type googleClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+type oauthService struct {
+	oauthRepo      contracts.OAuthRepository
+	userRepo       contracts.UserRepository
+	sessionService contracts.SessionService
+	mfaService     contracts.MFAService
+	validator      validator.ValidatorInterface
+	uuid           uuid.UUIDInterface
+	bcrypt         bcrypt.BcryptInterface
+	token          token.TokenInterface
+	time           timePkg.TimeInterface
+
+	mu       sync.Mutex
+	config   *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+func NewOAuthService(
+	oauthRepo contracts.OAuthRepository,
+	userRepo contracts.UserRepository,
+	sessionService contracts.SessionService,
+	mfaService contracts.MFAService,
+	validator validator.ValidatorInterface,
+	uuid uuid.UUIDInterface,
+	bcrypt bcrypt.BcryptInterface,
+	token token.TokenInterface,
+	time timePkg.TimeInterface,
+) contracts.OAuthService {
+	return &oauthService{
+		oauthRepo:      oauthRepo,
+		userRepo:       userRepo,
+		sessionService: sessionService,
+		mfaService:     mfaService,
+		validator:      validator,
+		uuid:           uuid,
+		bcrypt:         bcrypt,
+		token:          token,
+		time:           time,
+	}
+}
+
+func (s *oauthService) GoogleLogin(ctx context.Context) (dto.OAuthLoginResponse, error) {
+	var res dto.OAuthLoginResponse
+
+	config, _, err := s.google()
+	if err != nil {
+		return res, err
+	}
+
+	id, err := s.uuid.NewV7()
+	if err != nil {
+		return res, err
+	}
+
+	plainState, err := s.token.Generate(oauthStateSize)
+	if err != nil {
+		return res, err
+	}
+
+	nonce, err := s.token.Generate(oauthStateSize)
+	if err != nil {
+		return res, err
+	}
+
+	state := entity.OAuthState{
+		ID:           id,
+		StateHash:    s.token.Hash(plainState),
+		Nonce:        nonce,
+		CodeVerifier: oauth2.GenerateVerifier(),
+		ExpiresAt:    s.time.Add(oauthStateExpTime),
+	}
+
+	if err := s.oauthRepo.CreateState(ctx, &state); err != nil {
+		return res, err
+	}
+
+	res.URL = config.AuthCodeURL(
+		plainState,
+		oidc.Nonce(state.Nonce),
+		oauth2.S256ChallengeOption(state.CodeVerifier),
+	)
+	res.State = plainState
+
+	return res, nil
+}
+
+func (s *oauthService) GoogleCallback(ctx context.Context, req dto.OAuthCallbackRequest) (dto.LoginResponse, error) {
+	var res dto.LoginResponse
+
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return res, valErr
+	}
+
+	config, verifier, err := s.google()
+	if err != nil {
+		return res, err
+	}
+
+	state, err := s.oauthRepo.ConsumeState(ctx, s.token.Hash(req.State), s.time.Now())
+	if err != nil {
+		return res, err
+	}
+
+	providerCtx := oidc.ClientContext(ctx, &http.Client{Timeout: providerHTTPTimeout})
+
+	oauthToken, err := config.Exchange(providerCtx, req.Code, oauth2.VerifierOption(state.CodeVerifier))
+	if err != nil {
+		log.Warn(log.LogInfo{
+			"error": err.Error(),
+		}, "[OAUTH SERVICE][GoogleCallback] failed to exchange authorization code")
+		return res, domain.ErrOAuthAuthenticationFailed
+	}
+
+	rawIDToken, ok := oauthToken.Extra("id_token").(string)
+	if !ok {
+		return res, domain.ErrOAuthAuthenticationFailed
+	}
+
+	idToken, err := verifier.Verify(providerCtx, rawIDToken)
+	if err != nil {
+		log.Warn(log.LogInfo{
+			"error": err.Error(),
+		}, "[OAUTH SERVICE][GoogleCallback] failed to verify id token")
+		return res, domain.ErrOAuthAuthenticationFailed
+	}
+
+	if idToken.Nonce != state.Nonce {
+		return res, domain.ErrOAuthAuthenticationFailed
+	}
+
+	var claims googleClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return res, domain.ErrOAuthAuthenticationFailed
+	}
+
+	if !claims.EmailVerified || claims.Email == "" {
+		return res, domain.ErrOAuthEmailNotVerified
+	}
+
+	user, err := s.resolveUser(ctx, idToken.Subject, claims, req.Locale)
+	if err != nil {
+		return res, err
+	}
+
+	return s.mfaService.SignIn(ctx, user, []string{jwt.AMRFederated})
+}
+
+// resolveUser finds the user linked to the google account, links an existing
+// user with the same email or registers a new one.
+func (s *oauthService) resolveUser(
+	ctx context.Context,
+	subject string,
+	claims googleClaims,
+	locale string,
+) (entity.User, error) {
+	identity, err := s.oauthRepo.FindIdentity(ctx, entity.OAuthProviderGoogle, subject)
+	if err == nil {
+		return s.userRepo.FindByID(ctx, identity.UserID)
+	}
+
+	if !errors.Is(err, domain.ErrUserIdentityNotFound) {
+		return entity.User{}, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	switch {
+	case err == nil:
+		if err := s.claimUnverifiedAccount(ctx, user); err != nil {
+			return user, err
+		}
+	case errors.Is(err, domain.ErrUserNotFound):
+		if user, err = s.createUser(ctx, email, claims.Name, locale); err != nil {
+			return user, err
+		}
+	default:
+		return user, err
+	}
+
+	id, err := s.uuid.NewV7()
+	if err != nil {
+		return user, err
+	}
+
+	err = s.oauthRepo.CreateIdentity(ctx, &entity.UserIdentity{
+		ID:       id,
+		UserID:   user.ID,
+		Provider: entity.OAuthProviderGoogle,
+		Subject:  subject,
+		Email:    email,
+	})
+	if err != nil {
+		return user, err
+	}
+
+	return s.userRepo.FindByID(ctx, user.ID)
+}
+
+// claimUnverifiedAccount protects against an account registered in advance
+// with somebody else's email. Once google proves the email belongs to the
+// caller, the password and sessions of the unverified account are dropped.
+func (s *oauthService) claimUnverifiedAccount(ctx context.Context, user entity.User) error {
+	if user.EmailVerifiedAt.Valid {
+		return nil
+	}
+
+	password, err := s.unusablePassword()
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, password); err != nil {
+		return err
+	}
+
+	if err := s.sessionService.RevokeUserSessions(ctx, user.ID); err != nil {
+		return err
+	}
+
+	return s.userRepo.MarkEmailVerified(ctx, user.ID)
+}
+
+func (s *oauthService) createUser(
+	ctx context.Context,
+	email string,
+	name string,
+	locale string,
+) (entity.User, error) {
+	var user entity.User
+
+	id, err := s.uuid.NewV7()
+	if err != nil {
+		return user, err
+	}
+
+	password, err := s.unusablePassword()
+	if err != nil {
+		return user, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name, _, _ = strings.Cut(email, "@")
+	}
+
+	user = entity.User{
+		ID:       id,
+		Name:     name,
+		Email:    email,
+		Password: password,
+		Locale:   mail.NormalizeLocale(locale),
+	}
+
+	if err := s.userRepo.Create(ctx, &user); err != nil {
+		return user, err
+	}
+
+	if err := s.userRepo.MarkEmailVerified(ctx, user.ID); err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+// unusablePassword hashes a random secret nobody knows, users created through
+// google can still set a password with the reset flow.
+func (s *oauthService) unusablePassword() (string, error) {
+	secret, err := s.token.Generate(unusablePasswordSize)
+	if err != nil {
+		return "", err
+	}
+
+	return s.bcrypt.Hash(secret)
+}
+
+// google discovers the provider on first use and keeps it, a failed
+// discovery is retried on the next request. The discovery context outlives
+// the request because the verifier uses it to refresh the cached JWKS.
+func (s *oauthService) google() (*oauth2.Config, *oidc.IDTokenVerifier, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.config != nil {
+		return s.config, s.verifier, nil
+	}
+
+	if env.AppEnv.GoogleClientID == "" || env.AppEnv.GoogleClientSecret == "" {
+		return nil, nil, domain.ErrOAuthNotConfigured
+	}
+
+	issuer := env.AppEnv.GoogleIssuerURL
+	if issuer == "" {
+		issuer = "https://accounts.google.com"
+	}
+
+	providerCtx := oidc.ClientContext(context.Background(), &http.Client{Timeout: providerHTTPTimeout})
+
+	provider, err := oidc.NewProvider(providerCtx, issuer)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":  err.Error(),
+			"issuer": issuer,
+		}, "[OAUTH SERVICE][google] failed to discover oidc provider")
+		return nil, nil, domain.ErrOAuthNotConfigured
+	}
+
+	s.config = &oauth2.Config{
+		ClientID:     env.AppEnv.GoogleClientID,
+		ClientSecret: env.AppEnv.GoogleClientSecret,
+		RedirectURL:  env.AppEnv.GoogleRedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       []string{oidc.ScopeOpenID, "email", "profile"},
+	}
+	s.verifier = provider.Verifier(&oidc.Config{
+		ClientID: env.AppEnv.GoogleClientID,
+	})
+
+	return s.config, s.verifier, nil
+}
@@ -0,0 +1,86 @@
+package rest
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/internal/middlewares"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers/http/response"
+	ratelimiter "github.com/kelompok1-swe-academya/caper-be/pkg/rate_limiter"
+)
+
+var (
+	forgotPasswordRateLimit = middlewares.RateLimitPolicy{
+		Name: "forgot_password",
+		Policy: ratelimiter.Policy{
+			Algorithm: ratelimiter.FixedWindow,
+			Limit:     5,
+			Window:    15 * time.Minute,
+		},
+		Key:        middlewares.KeyByIP,
+		FailClosed: true,
+	}
+
+	resetPasswordRateLimit = middlewares.RateLimitPolicy{
+		Name: "reset_password",
+		Policy: ratelimiter.Policy{
+			Algorithm: ratelimiter.SlidingWindow,
+			Limit:     10,
+			Window:    15 * time.Minute,
+		},
+		Key:        middlewares.KeyByIP,
+		FailClosed: true,
+	}
+)
+
+type passwordResetController struct {
+	passwordResetService contracts.PasswordResetService
+}
+
+func InitPasswordResetController(
+	router fiber.Router,
+	passwordResetService contracts.PasswordResetService,
+	middleware *middlewares.Middleware,
+) {
+	controller := passwordResetController{
+		passwordResetService: passwordResetService,
+	}
+
+	authRoute := router.Group("/auth")
+	authRoute.Post("/forgot-password", middleware.RateLimit(forgotPasswordRateLimit), controller.forgotPassword)
+	authRoute.Post("/reset-password", middleware.RateLimit(resetPasswordRateLimit), controller.resetPassword)
+}
+
+func (c *passwordResetController) forgotPassword(ctx *fiber.Ctx) error {
+	var req dto.ForgotPasswordRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	if err := c.passwordResetService.ForgotPassword(ctx.Context(), req); err != nil {
+		return err
+	}
+
+	return response.SendResponse(
+		ctx,
+		fiber.StatusAccepted,
+		"if the email is registered, a password reset link has been sent",
+	)
+}
+
+func (c *passwordResetController) resetPassword(ctx *fiber.Ctx) error {
+	var req dto.ResetPasswordRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	if err := c.passwordResetService.ResetPassword(ctx.Context(), req); err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, "password has been reset")
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+type passwordResetRepository struct {
+	db *sqlx.DB
+}
+
+func NewPasswordResetRepository(db *sqlx.DB) contracts.PasswordResetRepository {
+	return &passwordResetRepository{
+		db: db,
+	}
+}
+
+func (r *passwordResetRepository) Create(ctx context.Context, token *entity.PasswordResetToken) error {
+	query := `
+		INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at)
+		VALUES (:id, :user_id, :token_hash, :expires_at)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, token)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[PASSWORD RESET REPOSITORY][Create] failed to create password reset token")
+		return err
+	}
+
+	return nil
+}
+
+func (r *passwordResetRepository) InvalidateByUserID(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = NOW()
+		WHERE user_id = $1 AND used_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[PASSWORD RESET REPOSITORY][InvalidateByUserID] failed to invalidate password reset tokens")
+		return err
+	}
+
+	return nil
+}
+
+// ResetPassword consumes the token and stores the new password in one
+// transaction. The token is marked as used and returned in a single
+// statement, so it can only ever be redeemed once even under concurrent
+// requests, and it stays usable when the password can't be stored.
+func (r *passwordResetRepository) ResetPassword(
+	ctx context.Context,
+	tokenHash string,
+	password string,
+	now time.Time,
+) (entity.PasswordResetToken, error) {
+	var token entity.PasswordResetToken
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[PASSWORD RESET REPOSITORY][ResetPassword] failed to begin transaction")
+		return token, err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	consumeQuery := `
+		UPDATE password_reset_tokens
+		SET used_at = $2
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+		RETURNING id, user_id, token_hash, expires_at, used_at, created_at
+	`
+
+	if err := tx.GetContext(ctx, &token, consumeQuery, tokenHash, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return token, domain.ErrInvalidPasswordResetToken
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[PASSWORD RESET REPOSITORY][ResetPassword] failed to consume password reset token")
+		return token, err
+	}
+
+	passwordQuery := `
+		UPDATE users
+		SET password = $2, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, passwordQuery, token.UserID, password)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": token.UserID,
+		}, "[PASSWORD RESET REPOSITORY][ResetPassword] failed to update user password")
+		return token, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return token, err
+	}
+
+	// the account was deleted after the link was sent
+	if rows == 0 {
+		return token, domain.ErrInvalidPasswordResetToken
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[PASSWORD RESET REPOSITORY][ResetPassword] failed to commit transaction")
+		return token, err
+	}
+
+	return token, nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/bcrypt"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/mail"
+	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/token"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
+)
+
+const passwordResetTokenSize = 32
+
+type passwordResetService struct {
+	passwordResetRepo contracts.PasswordResetRepository
+	userRepo          contracts.UserRepository
+	sessionService    contracts.SessionService
+	validator         validator.ValidatorInterface
+	uuid              uuid.UUIDInterface
+	bcrypt            bcrypt.BcryptInterface
+	token             token.TokenInterface
+	mailService       contracts.MailService
+	time              timePkg.TimeInterface
+}
+
+func NewPasswordResetService(
+	passwordResetRepo contracts.PasswordResetRepository,
+	userRepo contracts.UserRepository,
+	sessionService contracts.SessionService,
+	validator validator.ValidatorInterface,
+	uuid uuid.UUIDInterface,
+	bcrypt bcrypt.BcryptInterface,
+	token token.TokenInterface,
+	mailService contracts.MailService,
+	time timePkg.TimeInterface,
+) contracts.PasswordResetService {
+	return &passwordResetService{
+		passwordResetRepo: passwordResetRepo,
+		userRepo:          userRepo,
+		sessionService:    sessionService,
+		validator:         validator,
+		uuid:              uuid,
+		bcrypt:            bcrypt,
+		token:             token,
+		mailService:       mailService,
+		time:              time,
+	}
+}
+
+// ForgotPassword succeeds whether or not the email is registered so the
+// endpoint can't be used to find out which accounts exist.
+func (s *passwordResetService) ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) error {
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return valErr
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	if err := s.passwordResetRepo.InvalidateByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	id, err := s.uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	plain, err := s.token.Generate(passwordResetTokenSize)
+	if err != nil {
+		return err
+	}
+
+	resetToken := entity.PasswordResetToken{
+		ID:        id,
+		UserID:    user.ID,
+		TokenHash: s.token.Hash(plain),
+		ExpiresAt: s.time.Add(env.AppEnv.PasswordResetExpTime),
+	}
+
+	if err := s.passwordResetRepo.Create(ctx, &resetToken); err != nil {
+		return err
+	}
+
+	return s.mailService.Queue(ctx, user.Email, user.Locale, mail.TemplatePasswordReset, mail.LinkData{
+		Name:      user.Name,
+		Link:      buildResetLink(plain),
+		ExpiresIn: env.AppEnv.PasswordResetExpTime.String(),
+	})
+}
+
+func (s *passwordResetService) ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) error {
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return valErr
+	}
+
+	hashed, err := s.bcrypt.Hash(req.Password)
+	if err != nil {
+		return err
+	}
+
+	// a failure after the token is consumed would otherwise burn the link
+	// without changing the password
+	resetToken, err := s.passwordResetRepo.ResetPassword(ctx, s.token.Hash(req.Token), hashed, s.time.Now())
+	if err != nil {
+		return err
+	}
+
+	return s.sessionService.RevokeUserSessions(ctx, resetToken.UserID)
+}
+
+func buildResetLink(plain string) string {
+	return fmt.Sprintf(
+		"%s/reset-password?token=%s",
+		strings.TrimRight(env.AppEnv.FrontendURL, "/"),
+		url.QueryEscape(plain),
+	)
+}
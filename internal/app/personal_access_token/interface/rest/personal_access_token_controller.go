@@ -0,0 +1,84 @@
+package rest
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/internal/middlewares"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers/http/response"
+)
+
+type personalAccessTokenController struct {
+	personalAccessTokenService contracts.PersonalAccessTokenService
+}
+
+func InitPersonalAccessTokenController(
+	router fiber.Router,
+	personalAccessTokenService contracts.PersonalAccessTokenService,
+	middleware *middlewares.Middleware,
+) {
+	controller := personalAccessTokenController{
+		personalAccessTokenService: personalAccessTokenService,
+	}
+
+	// without scopes RequireAuth turns personal access tokens away, so a
+	// leaked token can't mint or revoke others
+	tokenRoute := router.Group("/users/me/access-tokens", middleware.RequireAuth())
+	tokenRoute.Get("/", controller.list)
+	tokenRoute.Post("/", controller.create)
+	tokenRoute.Delete("/:id", controller.revoke)
+}
+
+func (c *personalAccessTokenController) list(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.personalAccessTokenService.List(ctx.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
+
+func (c *personalAccessTokenController) create(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	var req dto.CreatePersonalAccessTokenRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	res, err := c.personalAccessTokenService.Create(ctx.Context(), claims.UserID, req)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusCreated, res)
+}
+
+func (c *personalAccessTokenController) revoke(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return domain.ErrPersonalAccessTokenNotFound
+	}
+
+	if err := c.personalAccessTokenService.Revoke(ctx.Context(), claims.UserID, id); err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, "personal access token has been revoked")
+}
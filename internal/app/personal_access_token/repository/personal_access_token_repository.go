@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+// lastUsedPrecision keeps a busy token from writing on every request, the
+// last use is only updated once it is this old or the ip changed
+const lastUsedPrecision = time.Minute
+
+type personalAccessTokenRepository struct {
+	db *sqlx.DB
+}
+
+func NewPersonalAccessTokenRepository(db *sqlx.DB) contracts.PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{
+		db: db,
+	}
+}
+
+func (r *personalAccessTokenRepository) Create(ctx context.Context, token *entity.PersonalAccessToken) error {
+	query := `
+		INSERT INTO personal_access_tokens (id, user_id, name, token_hash, token_hint, scopes, expires_at)
+		VALUES (:id, :user_id, :name, :token_hash, :token_hint, :scopes, :expires_at)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, token)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": token.UserID,
+		}, "[PERSONAL ACCESS TOKEN REPOSITORY][Create] failed to create personal access token")
+		return err
+	}
+
+	return nil
+}
+
+func (r *personalAccessTokenRepository) FindByUserID(
+	ctx context.Context,
+	userID uuid.UUID,
+) ([]entity.PersonalAccessToken, error) {
+	query := `
+		SELECT id, user_id, name, token_hash, token_hint, scopes, expires_at, last_used_at, last_used_ip, created_at
+		FROM personal_access_tokens
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	tokens := make([]entity.PersonalAccessToken, 0)
+	if err := r.db.SelectContext(ctx, &tokens, query, userID); err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[PERSONAL ACCESS TOKEN REPOSITORY][FindByUserID] failed to find personal access tokens")
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (r *personalAccessTokenRepository) FindByHash(
+	ctx context.Context,
+	tokenHash string,
+) (entity.PersonalAccessToken, error) {
+	query := `
+		SELECT id, user_id, name, token_hash, token_hint, scopes, expires_at, last_used_at, last_used_ip, created_at
+		FROM personal_access_tokens
+		WHERE token_hash = $1
+	`
+
+	var token entity.PersonalAccessToken
+	err := r.db.GetContext(ctx, &token, query, tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return token, domain.ErrPersonalAccessTokenNotFound
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[PERSONAL ACCESS TOKEN REPOSITORY][FindByHash] failed to find personal access token")
+		return token, err
+	}
+
+	return token, nil
+}
+
+func (r *personalAccessTokenRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, ip string, now time.Time) error {
+	query := `
+		UPDATE personal_access_tokens
+		SET last_used_at = $3, last_used_ip = $2
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at <= $4 OR last_used_ip IS DISTINCT FROM $2)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id, ip, now, now.Add(-lastUsedPrecision))
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    id,
+		}, "[PERSONAL ACCESS TOKEN REPOSITORY][TouchLastUsed] failed to update personal access token last use")
+		return err
+	}
+
+	return nil
+}
+
+func (r *personalAccessTokenRepository) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	query := `DELETE FROM personal_access_tokens WHERE id = $1 AND user_id = $2`
+
+	res, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[PERSONAL ACCESS TOKEN REPOSITORY][Delete] failed to delete personal access token")
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrPersonalAccessTokenNotFound
+	}
+
+	return nil
+}
+
+func (r *personalAccessTokenRepository) DeleteExpired(ctx context.Context) error {
+	query := `DELETE FROM personal_access_tokens WHERE expires_at <= NOW()`
+
+	if _, err := r.db.ExecContext(ctx, query); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[PERSONAL ACCESS TOKEN REPOSITORY][DeleteExpired] failed to delete expired personal access tokens")
+		return err
+	}
+
+	return nil
+}
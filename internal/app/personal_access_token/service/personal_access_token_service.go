@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
+	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/token"
+	uuidPkg "github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
+)
+
+const (
+	personalAccessTokenSize = 32
+	tokenHintLength         = 4
+)
+
+type personalAccessTokenService struct {
+	personalAccessTokenRepo contracts.PersonalAccessTokenRepository
+	userRepo                contracts.UserRepository
+	validator               validator.ValidatorInterface
+	uuid                    uuidPkg.UUIDInterface
+	token                   token.TokenInterface
+	time                    timePkg.TimeInterface
+}
+
+func NewPersonalAccessTokenService(
+	personalAccessTokenRepo contracts.PersonalAccessTokenRepository,
+	userRepo contracts.UserRepository,
+	validator validator.ValidatorInterface,
+	uuid uuidPkg.UUIDInterface,
+	token token.TokenInterface,
+	time timePkg.TimeInterface,
+) contracts.PersonalAccessTokenService {
+	return &personalAccessTokenService{
+		personalAccessTokenRepo: personalAccessTokenRepo,
+		userRepo:                userRepo,
+		validator:               validator,
+		uuid:                    uuid,
+		token:                   token,
+		time:                    time,
+	}
+}
+
+func (s *personalAccessTokenService) Create(
+	ctx context.Context,
+	userID uuid.UUID,
+	req dto.CreatePersonalAccessTokenRequest,
+) (dto.CreatePersonalAccessTokenResponse, error) {
+	var res dto.CreatePersonalAccessTokenResponse
+
+	req.Name = strings.TrimSpace(req.Name)
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return res, valErr
+	}
+
+	id, err := s.uuid.NewV7()
+	if err != nil {
+		return res, err
+	}
+
+	secret, err := s.token.Generate(personalAccessTokenSize)
+	if err != nil {
+		return res, err
+	}
+
+	plain := entity.PersonalAccessTokenPrefix + secret
+
+	personalAccessToken := entity.PersonalAccessToken{
+		ID:        id,
+		UserID:    userID,
+		Name:      req.Name,
+		TokenHash: s.token.Hash(plain),
+		TokenHint: plain[:len(entity.PersonalAccessTokenPrefix)+tokenHintLength],
+		Scopes:    strings.Join(req.Scopes, " "),
+		ExpiresAt: s.time.Add(time.Duration(req.ExpiresIn) * 24 * time.Hour),
+		CreatedAt: s.time.Now(),
+	}
+
+	if err := s.personalAccessTokenRepo.Create(ctx, &personalAccessToken); err != nil {
+		return res, err
+	}
+
+	res.PersonalAccessTokenResponse = toPersonalAccessTokenResponse(personalAccessToken)
+	res.Token = plain
+
+	return res, nil
+}
+
+func (s *personalAccessTokenService) List(
+	ctx context.Context,
+	userID uuid.UUID,
+) ([]dto.PersonalAccessTokenResponse, error) {
+	tokens, err := s.personalAccessTokenRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.PersonalAccessTokenResponse, 0, len(tokens))
+	for _, personalAccessToken := range tokens {
+		res = append(res, toPersonalAccessTokenResponse(personalAccessToken))
+	}
+
+	return res, nil
+}
+
+func (s *personalAccessTokenService) Revoke(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	return s.personalAccessTokenRepo.Delete(ctx, userID, id)
+}
+
+// Authenticate fails the same way a bad jwt does. The role is read from the
+// user on every request, so a role change applies to tokens right away.
+func (s *personalAccessTokenService) Authenticate(ctx context.Context, plain string, ip string) (jwt.Claims, error) {
+	var claims jwt.Claims
+
+	personalAccessToken, err := s.personalAccessTokenRepo.FindByHash(ctx, s.token.Hash(plain))
+	if err != nil {
+		if errors.Is(err, domain.ErrPersonalAccessTokenNotFound) {
+			return claims, domain.ErrInvalidBearerToken
+		}
+
+		return claims, err
+	}
+
+	now := s.time.Now()
+	if !now.Before(personalAccessToken.ExpiresAt) {
+		return claims, domain.ErrExpiredBearerToken
+	}
+
+	// soft deleted users aren't found, so their tokens stop working with them
+	user, err := s.userRepo.FindByID(ctx, personalAccessToken.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return claims, domain.ErrInvalidBearerToken
+		}
+
+		return claims, err
+	}
+
+	if err := s.personalAccessTokenRepo.TouchLastUsed(ctx, personalAccessToken.ID, ip, now); err != nil {
+		return claims, err
+	}
+
+	claims.Subject = user.ID.String()
+	claims.UserID = user.ID
+	claims.RoleName = user.Role.Name
+	claims.PersonalAccessTokenID = personalAccessToken.ID
+	claims.Scopes = strings.Fields(personalAccessToken.Scopes)
+
+	return claims, nil
+}
+
+func toPersonalAccessTokenResponse(personalAccessToken entity.PersonalAccessToken) dto.PersonalAccessTokenResponse {
+	res := dto.PersonalAccessTokenResponse{
+		ID:        personalAccessToken.ID,
+		Name:      personalAccessToken.Name,
+		TokenHint: personalAccessToken.TokenHint,
+		Scopes:    strings.Fields(personalAccessToken.Scopes),
+		ExpiresAt: personalAccessToken.ExpiresAt,
+		CreatedAt: personalAccessToken.CreatedAt,
+	}
+
+	if personalAccessToken.LastUsedAt.Valid {
+		res.LastUsedAt = &personalAccessToken.LastUsedAt.Time
+	}
+
+	if personalAccessToken.LastUsedIP.Valid {
+		res.LastUsedIP = &personalAccessToken.LastUsedIP.String
+	}
+
+	return res
+}
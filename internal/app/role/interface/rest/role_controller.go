@@ -0,0 +1,141 @@
+package rest
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/middlewares"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers/http/response"
+)
+
+type roleController struct {
+	roleService contracts.RoleService
+}
+
+func InitRoleController(
+	router fiber.Router,
+	roleService contracts.RoleService,
+	middleware *middlewares.Middleware,
+) {
+	controller := roleController{
+		roleService: roleService,
+	}
+
+	requireAdmin := []fiber.Handler{middleware.RequireAuth(), middleware.RequireRoles(entity.RoleAdmin)}
+
+	roleRoute := router.Group("/roles", requireAdmin...)
+	roleRoute.Get("/", controller.getRoles)
+	roleRoute.Post("/", controller.createRole)
+	roleRoute.Put("/:id/permissions/:permissionId", controller.attachPermission)
+	roleRoute.Delete("/:id/permissions/:permissionId", controller.detachPermission)
+
+	permissionRoute := router.Group("/permissions", requireAdmin...)
+	permissionRoute.Get("/", controller.getPermissions)
+	permissionRoute.Post("/", controller.createPermission)
+
+	router.Get("/users/:id/permissions", append(requireAdmin, controller.getUserPermissions)...)
+}
+
+func (c *roleController) getRoles(ctx *fiber.Ctx) error {
+	res, err := c.roleService.GetRoles(ctx.Context())
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
+
+func (c *roleController) createRole(ctx *fiber.Ctx) error {
+	var req dto.CreateRoleRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	res, err := c.roleService.CreateRole(ctx.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusCreated, res)
+}
+
+func (c *roleController) attachPermission(ctx *fiber.Ctx) error {
+	roleID, permissionID, err := parseRolePermissionParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.roleService.AttachPermission(ctx.Context(), roleID, permissionID); err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusNoContent, nil)
+}
+
+func (c *roleController) detachPermission(ctx *fiber.Ctx) error {
+	roleID, permissionID, err := parseRolePermissionParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.roleService.DetachPermission(ctx.Context(), roleID, permissionID); err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusNoContent, nil)
+}
+
+func (c *roleController) getPermissions(ctx *fiber.Ctx) error {
+	res, err := c.roleService.GetPermissions(ctx.Context())
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
+
+func (c *roleController) createPermission(ctx *fiber.Ctx) error {
+	var req dto.CreatePermissionRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	res, err := c.roleService.CreatePermission(ctx.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusCreated, res)
+}
+
+func (c *roleController) getUserPermissions(ctx *fiber.Ctx) error {
+	userID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	res, err := c.roleService.GetUserPermissions(ctx.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
+
+func parseRolePermissionParams(ctx *fiber.Ctx) (int, int, error) {
+	roleID, err := ctx.ParamsInt("id")
+	if err != nil {
+		return 0, 0, domain.ErrRoleNotFound
+	}
+
+	permissionID, err := ctx.ParamsInt("permissionId")
+	if err != nil {
+		return 0, 0, domain.ErrPermissionNotFound
+	}
+
+	return roleID, permissionID, nil
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+// impliedRolesCTE walks roles.inherits_role_id starting from the role named
+// $1, so a role implies itself and every role it inherits from. UNION keeps
+// the recursion finite if the hierarchy is ever misconfigured into a cycle.
+const impliedRolesCTE = `
+	WITH RECURSIVE implied_roles AS (
+		SELECT id, name, inherits_role_id
+		FROM roles
+		WHERE name = $1
+		UNION
+		SELECT r.id, r.name, r.inherits_role_id
+		FROM roles r
+		JOIN implied_roles ir ON r.id = ir.inherits_role_id
+	)
+`
+
+type roleRepository struct {
+	db *sqlx.DB
+}
+
+func NewRoleRepository(db *sqlx.DB) contracts.RoleRepository {
+	return &roleRepository{
+		db: db,
+	}
+}
+
+func (r *roleRepository) FindAll(ctx context.Context) ([]entity.Role, error) {
+	query := `
+		SELECT id, name, inherits_role_id
+		FROM roles
+		ORDER BY id
+	`
+
+	roles := make([]entity.Role, 0)
+	err := r.db.SelectContext(ctx, &roles, query)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[ROLE REPOSITORY][FindAll] failed to find roles")
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (r *roleRepository) FindByID(ctx context.Context, id int) (entity.Role, error) {
+	query := `
+		SELECT id, name, inherits_role_id
+		FROM roles
+		WHERE id = $1
+	`
+
+	var role entity.Role
+	err := r.db.GetContext(ctx, &role, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return role, domain.ErrRoleNotFound
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    id,
+		}, "[ROLE REPOSITORY][FindByID] failed to find role by id")
+		return role, err
+	}
+
+	return role, nil
+}
+
+func (r *roleRepository) Create(ctx context.Context, role *entity.Role) error {
+	query := `
+		INSERT INTO roles (name, inherits_role_id)
+		VALUES ($1, $2)
+		RETURNING id
+	`
+
+	err := r.db.GetContext(ctx, &role.ID, query, role.Name, role.InheritsRoleID)
+	if err != nil {
+		if helpers.IsUniqueViolation(err) {
+			return domain.ErrRoleAlreadyExists
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[ROLE REPOSITORY][Create] failed to create role")
+		return err
+	}
+
+	return nil
+}
+
+func (r *roleRepository) FindImpliedRoles(ctx context.Context, roleName string) ([]entity.Role, error) {
+	query := impliedRolesCTE + `
+		SELECT id, name, inherits_role_id
+		FROM implied_roles
+	`
+
+	roles := make([]entity.Role, 0)
+	err := r.db.SelectContext(ctx, &roles, query, roleName)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":     err.Error(),
+			"role_name": roleName,
+		}, "[ROLE REPOSITORY][FindImpliedRoles] failed to find implied roles")
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (r *roleRepository) FindPermissionsByRoleName(ctx context.Context, roleName string) ([]entity.Permission, error) {
+	query := impliedRolesCTE + `
+		SELECT DISTINCT p.id, p.name
+		FROM permissions p
+		JOIN role_permissions rp ON rp.permission_id = p.id
+		JOIN implied_roles ir ON ir.id = rp.role_id
+	`
+
+	permissions := make([]entity.Permission, 0)
+	err := r.db.SelectContext(ctx, &permissions, query, roleName)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":     err.Error(),
+			"role_name": roleName,
+		}, "[ROLE REPOSITORY][FindPermissionsByRoleName] failed to find role permissions")
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+func (r *roleRepository) FindAllPermissions(ctx context.Context) ([]entity.Permission, error) {
+	query := `
+		SELECT id, name
+		FROM permissions
+		ORDER BY name
+	`
+
+	permissions := make([]entity.Permission, 0)
+	err := r.db.SelectContext(ctx, &permissions, query)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[ROLE REPOSITORY][FindAllPermissions] failed to find permissions")
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+func (r *roleRepository) FindPermissionByID(ctx context.Context, id int) (entity.Permission, error) {
+	query := `
+		SELECT id, name
+		FROM permissions
+		WHERE id = $1
+	`
+
+	var permission entity.Permission
+	err := r.db.GetContext(ctx, &permission, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return permission, domain.ErrPermissionNotFound
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    id,
+		}, "[ROLE REPOSITORY][FindPermissionByID] failed to find permission by id")
+		return permission, err
+	}
+
+	return permission, nil
+}
+
+func (r *roleRepository) CreatePermission(ctx context.Context, permission *entity.Permission) error {
+	query := `
+		INSERT INTO permissions (name)
+		VALUES ($1)
+		RETURNING id
+	`
+
+	err := r.db.GetContext(ctx, &permission.ID, query, permission.Name)
+	if err != nil {
+		if helpers.IsUniqueViolation(err) {
+			return domain.ErrPermissionAlreadyExists
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[ROLE REPOSITORY][CreatePermission] failed to create permission")
+		return err
+	}
+
+	return nil
+}
+
+func (r *roleRepository) AttachPermission(ctx context.Context, roleID int, permissionID int) error {
+	query := `
+		INSERT INTO role_permissions (role_id, permission_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query, roleID, permissionID)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":         err.Error(),
+			"role_id":       roleID,
+			"permission_id": permissionID,
+		}, "[ROLE REPOSITORY][AttachPermission] failed to attach permission to role")
+		return err
+	}
+
+	return nil
+}
+
+func (r *roleRepository) DetachPermission(ctx context.Context, roleID int, permissionID int) error {
+	query := `
+		DELETE FROM role_permissions
+		WHERE role_id = $1 AND permission_id = $2
+	`
+
+	_, err := r.db.ExecContext(ctx, query, roleID, permissionID)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":         err.Error(),
+			"role_id":       roleID,
+			"permission_id": permissionID,
+		}, "[ROLE REPOSITORY][DetachPermission] failed to detach permission from role")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/cache"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
+)
+
+const (
+	roleAccessKeyPrefix = "role_access:"
+	// roleAccessTag is shared by every cached role because a change to one
+	// role can affect all roles inheriting from it
+	roleAccessTag = "role_access"
+)
+
+// roleAccess is the resolved view of a role: every role it implies through
+// the hierarchy and the union of their permissions.
+type roleAccess struct {
+	roles       map[string]struct{}
+	permissions map[string]struct{}
+}
+
+// cachedRoleAccess is how a roleAccess is stored in the cache
+type cachedRoleAccess struct {
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
+
+type roleService struct {
+	roleRepo  contracts.RoleRepository
+	userRepo  contracts.UserRepository
+	validator validator.ValidatorInterface
+	cache     cache.CacheInterface
+}
+
+func NewRoleService(
+	roleRepo contracts.RoleRepository,
+	userRepo contracts.UserRepository,
+	validator validator.ValidatorInterface,
+	cacheProvider cache.CacheInterface,
+) contracts.RoleService {
+	return &roleService{
+		roleRepo:  roleRepo,
+		userRepo:  userRepo,
+		validator: validator,
+		cache:     cacheProvider,
+	}
+}
+
+func (s *roleService) HasAnyRole(ctx context.Context, roleName string, required ...string) (bool, error) {
+	access, err := s.getAccess(ctx, roleName)
+	if err != nil {
+		return false, err
+	}
+
+	for _, name := range required {
+		if _, ok := access.roles[name]; ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (s *roleService) HasAllPermissions(ctx context.Context, roleName string, required ...string) (bool, error) {
+	access, err := s.getAccess(ctx, roleName)
+	if err != nil {
+		return false, err
+	}
+
+	for _, name := range required {
+		if _, ok := access.permissions[name]; !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (s *roleService) GetRoles(ctx context.Context) ([]dto.RoleResponse, error) {
+	roles, err := s.roleRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.RoleResponse, 0, len(roles))
+	for _, role := range roles {
+		res = append(res, toRoleResponse(role))
+	}
+
+	return res, nil
+}
+
+func (s *roleService) CreateRole(ctx context.Context, req dto.CreateRoleRequest) (dto.RoleResponse, error) {
+	var res dto.RoleResponse
+
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return res, valErr
+	}
+
+	role := entity.Role{
+		Name: req.Name,
+	}
+
+	if req.InheritsRoleID != nil {
+		inherited, err := s.roleRepo.FindByID(ctx, *req.InheritsRoleID)
+		if err != nil {
+			return res, err
+		}
+
+		role.InheritsRoleID = sql.NullInt32{Int32: int32(inherited.ID), Valid: true} //nolint:gosec // role ids are serial
+	}
+
+	if err := s.roleRepo.Create(ctx, &role); err != nil {
+		return res, err
+	}
+
+	s.invalidate(ctx)
+
+	return toRoleResponse(role), nil
+}
+
+func (s *roleService) GetPermissions(ctx context.Context) ([]dto.PermissionResponse, error) {
+	permissions, err := s.roleRepo.FindAllPermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.PermissionResponse, 0, len(permissions))
+	for _, permission := range permissions {
+		res = append(res, dto.PermissionResponse{
+			ID:   permission.ID,
+			Name: permission.Name,
+		})
+	}
+
+	return res, nil
+}
+
+func (s *roleService) CreatePermission(
+	ctx context.Context,
+	req dto.CreatePermissionRequest,
+) (dto.PermissionResponse, error) {
+	var res dto.PermissionResponse
+
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return res, valErr
+	}
+
+	permission := entity.Permission{
+		Name: req.Name,
+	}
+
+	if err := s.roleRepo.CreatePermission(ctx, &permission); err != nil {
+		return res, err
+	}
+
+	res.ID = permission.ID
+	res.Name = permission.Name
+
+	return res, nil
+}
+
+func (s *roleService) AttachPermission(ctx context.Context, roleID int, permissionID int) error {
+	if err := s.ensureRolePermissionExist(ctx, roleID, permissionID); err != nil {
+		return err
+	}
+
+	if err := s.roleRepo.AttachPermission(ctx, roleID, permissionID); err != nil {
+		return err
+	}
+
+	s.invalidate(ctx)
+
+	return nil
+}
+
+func (s *roleService) DetachPermission(ctx context.Context, roleID int, permissionID int) error {
+	if err := s.ensureRolePermissionExist(ctx, roleID, permissionID); err != nil {
+		return err
+	}
+
+	if err := s.roleRepo.DetachPermission(ctx, roleID, permissionID); err != nil {
+		return err
+	}
+
+	s.invalidate(ctx)
+
+	return nil
+}
+
+func (s *roleService) GetUserPermissions(ctx context.Context, userID uuid.UUID) (dto.UserPermissionsResponse, error) {
+	var res dto.UserPermissionsResponse
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return res, err
+	}
+
+	access, err := s.getAccess(ctx, user.Role.Name)
+	if err != nil {
+		return res, err
+	}
+
+	res.UserID = user.ID
+	res.RoleName = user.Role.Name
+	res.Roles = sortedKeys(access.roles)
+	res.Permissions = sortedKeys(access.permissions)
+
+	return res, nil
+}
+
+func (s *roleService) ensureRolePermissionExist(ctx context.Context, roleID int, permissionID int) error {
+	if _, err := s.roleRepo.FindByID(ctx, roleID); err != nil {
+		return err
+	}
+
+	if _, err := s.roleRepo.FindPermissionByID(ctx, permissionID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getAccess serves authorization checks from the cache. Every role or
+// permission change made through this service invalidates the role_access
+// tag, which reaches all replicas when CACHE_DRIVER is redis. ROLE_CACHE_TTL
+// bounds how long a change can go unnoticed otherwise.
+func (s *roleService) getAccess(ctx context.Context, roleName string) (roleAccess, error) {
+	var access roleAccess
+
+	value, err := s.cache.GetOrLoad(
+		ctx,
+		roleAccessKeyPrefix+roleName,
+		env.AppEnv.RoleCacheTTL,
+		func(ctx context.Context) ([]byte, error) {
+			return s.loadAccess(ctx, roleName)
+		},
+		roleAccessTag,
+	)
+	if err != nil {
+		return access, err
+	}
+
+	var cached cachedRoleAccess
+	if err := json.Unmarshal(value, &cached); err != nil {
+		return access, err
+	}
+
+	access = roleAccess{
+		roles:       make(map[string]struct{}, len(cached.Roles)),
+		permissions: make(map[string]struct{}, len(cached.Permissions)),
+	}
+
+	for _, name := range cached.Roles {
+		access.roles[name] = struct{}{}
+	}
+
+	for _, name := range cached.Permissions {
+		access.permissions[name] = struct{}{}
+	}
+
+	return access, nil
+}
+
+func (s *roleService) loadAccess(ctx context.Context, roleName string) ([]byte, error) {
+	roles, err := s.roleRepo.FindImpliedRoles(ctx, roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := s.roleRepo.FindPermissionsByRoleName(ctx, roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	cached := cachedRoleAccess{
+		Roles:       make([]string, 0, len(roles)),
+		Permissions: make([]string, 0, len(permissions)),
+	}
+
+	for _, role := range roles {
+		cached.Roles = append(cached.Roles, role.Name)
+	}
+
+	for _, permission := range permissions {
+		cached.Permissions = append(cached.Permissions, permission.Name)
+	}
+
+	return json.Marshal(cached)
+}
+
+// invalidate drops every cached role. The change itself is already stored,
+// so a failure is only logged and the TTL takes over.
+func (s *roleService) invalidate(ctx context.Context) {
+	if err := s.cache.InvalidateTags(ctx, roleAccessTag); err != nil {
+		log.Warn(log.LogInfo{
+			"error": err.Error(),
+		}, "[ROLE SERVICE][invalidate] failed to invalidate role cache")
+	}
+}
+
+func toRoleResponse(role entity.Role) dto.RoleResponse {
+	res := dto.RoleResponse{
+		ID:   role.ID,
+		Name: role.Name,
+	}
+
+	if role.InheritsRoleID.Valid {
+		inheritsRoleID := int(role.InheritsRoleID.Int32)
+		res.InheritsRoleID = &inheritsRoleID
+	}
+
+	return res
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/internal/middlewares"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers/http/response"
+)
+
+type sessionController struct {
+	sessionService contracts.SessionService
+}
+
+func InitSessionController(
+	router fiber.Router,
+	sessionService contracts.SessionService,
+	middleware *middlewares.Middleware,
+) {
+	controller := sessionController{
+		sessionService: sessionService,
+	}
+
+	authRoute := router.Group("/auth")
+	authRoute.Post("/refresh", controller.refresh)
+	authRoute.Post("/logout", middleware.RequireAuth(), controller.logout)
+	authRoute.Post("/logout-all", middleware.RequireAuth(), controller.logoutAll)
+}
+
+func (c *sessionController) refresh(ctx *fiber.Ctx) error {
+	var req dto.RefreshTokenRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	res, err := c.sessionService.Refresh(ctx.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
+
+func (c *sessionController) logout(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	var req dto.LogoutRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return domain.ErrInvalidRequestBody
+		}
+	}
+
+	if err := c.sessionService.Logout(ctx.Context(), claims, req); err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusNoContent, nil)
+}
+
+func (c *sessionController) logoutAll(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.sessionService.LogoutAll(ctx.Context(), claims); err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusNoContent, nil)
+}
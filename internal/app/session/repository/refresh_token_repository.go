@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+const insertRefreshTokenQuery = `
+	INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, amr, expires_at)
+	VALUES (:id, :user_id, :family_id, :token_hash, :amr, :expires_at)
+`
+
+type refreshTokenRepository struct {
+	db *sqlx.DB
+}
+
+func NewRefreshTokenRepository(db *sqlx.DB) contracts.RefreshTokenRepository {
+	return &refreshTokenRepository{
+		db: db,
+	}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *entity.RefreshToken) error {
+	_, err := r.db.NamedExecContext(ctx, insertRefreshTokenQuery, token)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[REFRESH TOKEN REPOSITORY][Create] failed to create refresh token")
+		return err
+	}
+
+	return nil
+}
+
+func (r *refreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (entity.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, family_id, token_hash, amr, expires_at, used_at, revoked_at, created_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`
+
+	var token entity.RefreshToken
+	err := r.db.GetContext(ctx, &token, query, tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return token, domain.ErrInvalidRefreshToken
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[REFRESH TOKEN REPOSITORY][FindByHash] failed to find refresh token")
+		return token, err
+	}
+
+	return token, nil
+}
+
+// Rotate marks usedID as used and stores next in the same transaction. The
+// update only matches an unused, unrevoked token so two concurrent refreshes
+// with the same token can't both succeed.
+func (r *refreshTokenRepository) Rotate(ctx context.Context, usedID uuid.UUID, next *entity.RefreshToken) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[REFRESH TOKEN REPOSITORY][Rotate] failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	query := `
+		UPDATE refresh_tokens
+		SET used_at = NOW()
+		WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, query, usedID)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[REFRESH TOKEN REPOSITORY][Rotate] failed to mark refresh token as used")
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.ErrRefreshTokenReused
+	}
+
+	if _, err := tx.NamedExecContext(ctx, insertRefreshTokenQuery, next); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[REFRESH TOKEN REPOSITORY][Rotate] failed to create rotated refresh token")
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[REFRESH TOKEN REPOSITORY][Rotate] failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = NOW()
+		WHERE family_id = $1 AND revoked_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, familyID)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":     err.Error(),
+			"family_id": familyID,
+		}, "[REFRESH TOKEN REPOSITORY][RevokeFamily] failed to revoke refresh token family")
+		return err
+	}
+
+	return nil
+}
+
+func (r *refreshTokenRepository) RevokeByUserID(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = NOW()
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[REFRESH TOKEN REPOSITORY][RevokeByUserID] failed to revoke user refresh tokens")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+)
+
+type userRevocation struct {
+	revokedBefore time.Time
+	expiresAt     time.Time
+}
+
+// revokedTokenMemoryRepository keeps revocations in process memory. It is
+// meant for tests and single instance development, revocations are neither
+// shared between replicas nor kept across restarts.
+type revokedTokenMemoryRepository struct {
+	mu     sync.RWMutex
+	time   timePkg.TimeInterface
+	tokens map[string]time.Time
+	users  map[uuid.UUID]userRevocation
+}
+
+func NewRevokedTokenMemoryRepository(timeProvider timePkg.TimeInterface) contracts.RevokedTokenRepository {
+	return &revokedTokenMemoryRepository{
+		time:   timeProvider,
+		tokens: make(map[string]time.Time),
+		users:  make(map[uuid.UUID]userRevocation),
+	}
+}
+
+func (r *revokedTokenMemoryRepository) RevokeToken(
+	_ context.Context,
+	jti string,
+	_ uuid.UUID,
+	expiresAt time.Time,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tokens[jti] = expiresAt
+
+	return nil
+}
+
+func (r *revokedTokenMemoryRepository) RevokeUserTokens(
+	_ context.Context,
+	userID uuid.UUID,
+	revokedBefore time.Time,
+	expiresAt time.Time,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.users[userID] = userRevocation{
+		revokedBefore: revokedBefore,
+		expiresAt:     expiresAt,
+	}
+
+	return nil
+}
+
+func (r *revokedTokenMemoryRepository) IsRevoked(
+	_ context.Context,
+	jti string,
+	userID uuid.UUID,
+	issuedAt time.Time,
+) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.time.Now()
+
+	if expiresAt, ok := r.tokens[jti]; ok && now.Before(expiresAt) {
+		return true, nil
+	}
+
+	if revocation, ok := r.users[userID]; ok && now.Before(revocation.expiresAt) {
+		return issuedAt.Before(revocation.revokedBefore), nil
+	}
+
+	return false, nil
+}
+
+func (r *revokedTokenMemoryRepository) DeleteExpired(_ context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.time.Now()
+
+	for jti, expiresAt := range r.tokens {
+		if !now.Before(expiresAt) {
+			delete(r.tokens, jti)
+		}
+	}
+
+	for userID, revocation := range r.users {
+		if !now.Before(revocation.expiresAt) {
+			delete(r.users, userID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+type revokedTokenRepository struct {
+	db *sqlx.DB
+}
+
+func NewRevokedTokenRepository(db *sqlx.DB) contracts.RevokedTokenRepository {
+	return &revokedTokenRepository{
+		db: db,
+	}
+}
+
+func (r *revokedTokenRepository) RevokeToken(
+	ctx context.Context,
+	jti string,
+	userID uuid.UUID,
+	expiresAt time.Time,
+) error {
+	query := `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query, jti, userID, expiresAt)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"jti":   jti,
+		}, "[REVOKED TOKEN REPOSITORY][RevokeToken] failed to revoke token")
+		return err
+	}
+
+	return nil
+}
+
+func (r *revokedTokenRepository) RevokeUserTokens(
+	ctx context.Context,
+	userID uuid.UUID,
+	revokedBefore time.Time,
+	expiresAt time.Time,
+) error {
+	query := `
+		INSERT INTO user_token_revocations (user_id, revoked_before, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET revoked_before = EXCLUDED.revoked_before, expires_at = EXCLUDED.expires_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userID, revokedBefore, expiresAt)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[REVOKED TOKEN REPOSITORY][RevokeUserTokens] failed to revoke user tokens")
+		return err
+	}
+
+	return nil
+}
+
+func (r *revokedTokenRepository) IsRevoked(
+	ctx context.Context,
+	jti string,
+	userID uuid.UUID,
+	issuedAt time.Time,
+) (bool, error) {
+	query := `
+		SELECT
+			EXISTS (
+				SELECT 1 FROM revoked_tokens
+				WHERE jti = $1 AND expires_at > NOW()
+			)
+			OR EXISTS (
+				SELECT 1 FROM user_token_revocations
+				WHERE user_id = $2 AND revoked_before > $3 AND expires_at > NOW()
+			)
+	`
+
+	var revoked bool
+	err := r.db.GetContext(ctx, &revoked, query, jti, userID, issuedAt)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"jti":   jti,
+		}, "[REVOKED TOKEN REPOSITORY][IsRevoked] failed to check token revocation")
+		return false, err
+	}
+
+	return revoked, nil
+}
+
+func (r *revokedTokenRepository) DeleteExpired(ctx context.Context) error {
+	queries := []string{
+		`DELETE FROM revoked_tokens WHERE expires_at <= NOW()`,
+		`DELETE FROM user_token_revocations WHERE expires_at <= NOW()`,
+	}
+
+	for _, query := range queries {
+		if _, err := r.db.ExecContext(ctx, query); err != nil {
+			log.Error(log.LogInfo{
+				"error": err.Error(),
+			}, "[REVOKED TOKEN REPOSITORY][DeleteExpired] failed to delete expired revocations")
+			return err
+		}
+	}
+
+	return nil
+}
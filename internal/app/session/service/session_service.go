@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/token"
+	uuidPkg "github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
+)
+
+const refreshTokenSize = 32
+
+type sessionService struct {
+	refreshTokenRepo contracts.RefreshTokenRepository
+	revokedTokenRepo contracts.RevokedTokenRepository
+	userRepo         contracts.UserRepository
+	validator        validator.ValidatorInterface
+	uuid             uuidPkg.UUIDInterface
+	jwt              jwt.JwtInterface
+	token            token.TokenInterface
+	time             timePkg.TimeInterface
+}
+
+func NewSessionService(
+	refreshTokenRepo contracts.RefreshTokenRepository,
+	revokedTokenRepo contracts.RevokedTokenRepository,
+	userRepo contracts.UserRepository,
+	validator validator.ValidatorInterface,
+	uuid uuidPkg.UUIDInterface,
+	jwt jwt.JwtInterface,
+	token token.TokenInterface,
+	time timePkg.TimeInterface,
+) contracts.SessionService {
+	return &sessionService{
+		refreshTokenRepo: refreshTokenRepo,
+		revokedTokenRepo: revokedTokenRepo,
+		userRepo:         userRepo,
+		validator:        validator,
+		uuid:             uuid,
+		jwt:              jwt,
+		token:            token,
+		time:             time,
+	}
+}
+
+// Issue starts a new session, amr lists the authentication methods the user
+// went through and is kept for every refresh of the session
+func (s *sessionService) Issue(ctx context.Context, user entity.User, amr []string) (dto.TokenResponse, error) {
+	var res dto.TokenResponse
+
+	familyID, err := s.uuid.NewV7()
+	if err != nil {
+		return res, err
+	}
+
+	refreshToken, plain, err := s.newRefreshToken(user.ID, familyID, strings.Join(amr, " "))
+	if err != nil {
+		return res, err
+	}
+
+	if err := s.refreshTokenRepo.Create(ctx, &refreshToken); err != nil {
+		return res, err
+	}
+
+	return s.buildTokenResponse(user, plain, amr)
+}
+
+func (s *sessionService) Refresh(ctx context.Context, req dto.RefreshTokenRequest) (dto.TokenResponse, error) {
+	var res dto.TokenResponse
+
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return res, valErr
+	}
+
+	stored, err := s.refreshTokenRepo.FindByHash(ctx, s.token.Hash(req.RefreshToken))
+	if err != nil {
+		return res, err
+	}
+
+	if stored.RevokedAt.Valid {
+		return res, domain.ErrInvalidRefreshToken
+	}
+
+	if stored.UsedAt.Valid {
+		return res, s.revokeReusedFamily(ctx, stored)
+	}
+
+	if !s.time.Now().Before(stored.ExpiresAt) {
+		return res, domain.ErrExpiredRefreshToken
+	}
+
+	user, err := s.userRepo.FindByID(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return res, domain.ErrInvalidRefreshToken
+		}
+
+		return res, err
+	}
+
+	next, plain, err := s.newRefreshToken(user.ID, stored.FamilyID, stored.AMR)
+	if err != nil {
+		return res, err
+	}
+
+	err = s.refreshTokenRepo.Rotate(ctx, stored.ID, &next)
+	if err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenReused) {
+			return res, s.revokeReusedFamily(ctx, stored)
+		}
+
+		return res, err
+	}
+
+	return s.buildTokenResponse(user, plain, strings.Fields(stored.AMR))
+}
+
+func (s *sessionService) Logout(ctx context.Context, claims jwt.Claims, req dto.LogoutRequest) error {
+	if err := s.revokedTokenRepo.RevokeToken(ctx, claims.ID, claims.UserID, claims.ExpiresAt.Time); err != nil {
+		return err
+	}
+
+	if req.RefreshToken == "" {
+		return nil
+	}
+
+	stored, err := s.refreshTokenRepo.FindByHash(ctx, s.token.Hash(req.RefreshToken))
+	if err != nil {
+		return err
+	}
+
+	if stored.UserID != claims.UserID {
+		return domain.ErrInvalidRefreshToken
+	}
+
+	return s.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID)
+}
+
+func (s *sessionService) LogoutAll(ctx context.Context, claims jwt.Claims) error {
+	return s.RevokeUserSessions(ctx, claims.UserID)
+}
+
+// RevokeUserSessions rejects every access token issued to the user up to and
+// including the current second and revokes all of their refresh tokens. The
+// cutoff only has to outlive the longest lived access token.
+func (s *sessionService) RevokeUserSessions(ctx context.Context, userID uuid.UUID) error {
+	_, err := s.revokeSessions(ctx, userID)
+	return err
+}
+
+// RevokeOtherSessions ends every session of the user and starts a new one for
+// the caller, so only the device that asked stays signed in. The new access
+// token is only issued once the cutoff has passed, which takes up to a
+// second, otherwise it would carry a revoked iat.
+func (s *sessionService) RevokeOtherSessions(
+	ctx context.Context,
+	user entity.User,
+	amr []string,
+) (dto.TokenResponse, error) {
+	var res dto.TokenResponse
+
+	cutoff, err := s.revokeSessions(ctx, user.ID)
+	if err != nil {
+		return res, err
+	}
+
+	timer := time.NewTimer(time.Until(cutoff))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return res, ctx.Err()
+	}
+
+	return s.Issue(ctx, user, amr)
+}
+
+// revokeSessions cuts off at the start of the next second. iat only has whole
+// seconds, so a cutoff truncated to the current second would let a token
+// issued earlier in that same second through. Only tokens with an iat at or
+// after the returned cutoff stay valid.
+func (s *sessionService) revokeSessions(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	cutoff := s.time.Now().Truncate(time.Second).Add(time.Second)
+
+	err := s.revokedTokenRepo.RevokeUserTokens(ctx, userID, cutoff, cutoff.Add(env.AppEnv.JwtExpTime))
+	if err != nil {
+		return cutoff, err
+	}
+
+	return cutoff, s.refreshTokenRepo.RevokeByUserID(ctx, userID)
+}
+
+// revokeReusedFamily is called when a refresh token that was already rotated
+// is presented again. Either the client or an attacker holds a stale copy, so
+// every token descending from the same login is revoked.
+func (s *sessionService) revokeReusedFamily(ctx context.Context, stored entity.RefreshToken) error {
+	log.Warn(log.LogInfo{
+		"user_id":   stored.UserID,
+		"family_id": stored.FamilyID,
+	}, "[SESSION SERVICE][revokeReusedFamily] refresh token reuse detected, revoking family")
+
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+		return err
+	}
+
+	return domain.ErrRefreshTokenReused
+}
+
+func (s *sessionService) newRefreshToken(
+	userID uuid.UUID,
+	familyID uuid.UUID,
+	amr string,
+) (entity.RefreshToken, string, error) {
+	var refreshToken entity.RefreshToken
+
+	id, err := s.uuid.NewV7()
+	if err != nil {
+		return refreshToken, "", err
+	}
+
+	plain, err := s.token.Generate(refreshTokenSize)
+	if err != nil {
+		return refreshToken, "", err
+	}
+
+	refreshToken = entity.RefreshToken{
+		ID:        id,
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: s.token.Hash(plain),
+		AMR:       amr,
+		ExpiresAt: s.time.Add(env.AppEnv.RefreshTokenExpTime),
+	}
+
+	return refreshToken, plain, nil
+}
+
+func (s *sessionService) buildTokenResponse(
+	user entity.User,
+	refreshToken string,
+	amr []string,
+) (dto.TokenResponse, error) {
+	var res dto.TokenResponse
+
+	accessToken, err := s.jwt.Create(user.ID, user.Role.Name, amr)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[SESSION SERVICE][buildTokenResponse] failed to create access token")
+		return res, err
+	}
+
+	res.AccessToken = accessToken
+	res.RefreshToken = refreshToken
+	res.TokenType = "Bearer"
+	res.ExpiresIn = int64(env.AppEnv.JwtExpTime.Seconds())
+
+	return res, nil
+}
@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/middlewares"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers/http/response"
+)
+
+// FilesPath serves signed urls of the local storage, browsers load them
+// directly so the api key can't be required
+const FilesPath = "/api/v1/files/"
+
+type uploadController struct {
+	uploadService contracts.UploadService
+}
+
+func InitUploadController(
+	router fiber.Router,
+	uploadService contracts.UploadService,
+	middleware *middlewares.Middleware,
+) {
+	controller := uploadController{
+		uploadService: uploadService,
+	}
+
+	router.Post("/uploads", middleware.RequireAuth(entity.ScopeUploadsWrite), controller.upload)
+	router.Get("/files/*", controller.download)
+}
+
+func (c *uploadController) upload(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return domain.ErrFileRequired
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	res, err := c.uploadService.Upload(ctx.Context(), claims.UserID, dto.UploadFileRequest{
+		Name:    fileHeader.Filename,
+		Size:    fileHeader.Size,
+		Content: file,
+	})
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusCreated, res)
+}
+
+func (c *uploadController) download(ctx *fiber.Ctx) error {
+	var req dto.DownloadFileRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return domain.ErrFileNotFound
+	}
+	req.Key = ctx.Params("*")
+
+	body, info, err := c.uploadService.Download(ctx.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	ctx.Set(fiber.HeaderContentType, info.ContentType)
+	ctx.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+
+	return ctx.SendStream(body, int(info.Size))
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/storage"
+	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+	uuidPkg "github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+)
+
+const signedURLExpiry = 15 * time.Minute
+
+// allowedTypes maps the sniffed content type to the extension the object is
+// stored with, the extension sent by the client is never trusted.
+var allowedTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+type uploadService struct {
+	storage storage.StorageInterface
+	uuid    uuidPkg.UUIDInterface
+	time    timePkg.TimeInterface
+}
+
+func NewUploadService(
+	storage storage.StorageInterface,
+	uuid uuidPkg.UUIDInterface,
+	time timePkg.TimeInterface,
+) contracts.UploadService {
+	return &uploadService{
+		storage: storage,
+		uuid:    uuid,
+		time:    time,
+	}
+}
+
+func (s *uploadService) Upload(
+	ctx context.Context,
+	userID uuid.UUID,
+	req dto.UploadFileRequest,
+) (dto.UploadResponse, error) {
+	var res dto.UploadResponse
+
+	if req.Size > env.AppEnv.UploadMaxSize {
+		return res, domain.ErrFileTooLarge
+	}
+
+	contentType, body, err := helpers.SniffContentType(req.Content)
+	if err != nil {
+		return res, err
+	}
+
+	if contentType == "" {
+		return res, domain.ErrFileRequired
+	}
+
+	ext, ok := allowedTypes[contentType]
+	if !ok {
+		return res, domain.ErrUnsupportedFileType
+	}
+
+	id, err := s.uuid.NewV7()
+	if err != nil {
+		return res, err
+	}
+
+	key := fmt.Sprintf("uploads/%s/%s%s", userID, id, ext)
+
+	if err := s.storage.Put(ctx, key, body, req.Size, contentType); err != nil {
+		return res, err
+	}
+
+	expiresAt := s.time.Add(signedURLExpiry)
+
+	url, err := s.storage.SignedURL(ctx, key, expiresAt)
+	if err != nil {
+		return res, err
+	}
+
+	res.Key = key
+	res.URL = url
+	res.URLExpires = expiresAt
+	res.ContentType = contentType
+	res.Size = req.Size
+
+	return res, nil
+}
+
+// Download serves objects of backends that sign their urls themselves, other
+// backends hand out urls pointing straight to the storage service.
+func (s *uploadService) Download(
+	ctx context.Context,
+	req dto.DownloadFileRequest,
+) (io.ReadCloser, storage.ObjectInfo, error) {
+	verifier, ok := s.storage.(storage.SignatureVerifier)
+	if !ok || !verifier.Verify(req.Key, req.Expires, req.Signature) {
+		return nil, storage.ObjectInfo{}, domain.ErrFileNotFound
+	}
+
+	body, info, err := s.storage.Get(ctx, req.Key)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, info, domain.ErrFileNotFound
+		}
+
+		return nil, info, err
+	}
+
+	return body, info, nil
+}
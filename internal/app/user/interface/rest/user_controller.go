@@ -0,0 +1,381 @@
+package rest
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/middlewares"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers/http/response"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/mail"
+	ratelimiter "github.com/kelompok1-swe-academya/caper-be/pkg/rate_limiter"
+)
+
+var (
+	registerRateLimit = middlewares.RateLimitPolicy{
+		Name: "register",
+		Policy: ratelimiter.Policy{
+			Algorithm: ratelimiter.FixedWindow,
+			Limit:     10,
+			Window:    time.Hour,
+		},
+		Key:        middlewares.KeyByIP,
+		FailClosed: true,
+	}
+
+	// login is the main brute force target, the sliding window keeps an
+	// attacker from doubling their attempts around a window boundary
+	loginRateLimit = middlewares.RateLimitPolicy{
+		Name: "login",
+		Policy: ratelimiter.Policy{
+			Algorithm: ratelimiter.SlidingWindow,
+			Limit:     10,
+			Window:    15 * time.Minute,
+		},
+		Key:        middlewares.KeyByIP,
+		FailClosed: true,
+	}
+
+	changePasswordRateLimit = middlewares.RateLimitPolicy{
+		Name: "change_password",
+		Policy: ratelimiter.Policy{
+			Algorithm: ratelimiter.SlidingWindow,
+			Limit:     5,
+			Window:    15 * time.Minute,
+		},
+		Key:        middlewares.KeyByIP,
+		FailClosed: true,
+	}
+
+	// an export reads every table holding user data, so it is kept rare
+	exportRateLimit = middlewares.RateLimitPolicy{
+		Name: "data_export",
+		Policy: ratelimiter.Policy{
+			Algorithm: ratelimiter.FixedWindow,
+			Limit:     5,
+			Window:    time.Hour,
+		},
+		Key: middlewares.KeyByIP,
+	}
+)
+
+type userController struct {
+	userService contracts.UserService
+}
+
+func InitUserController(
+	router fiber.Router,
+	userService contracts.UserService,
+	middleware *middlewares.Middleware,
+) {
+	controller := userController{
+		userService: userService,
+	}
+
+	authRoute := router.Group("/auth")
+	authRoute.Post("/register", middleware.RateLimit(registerRateLimit), controller.register)
+	authRoute.Post("/login", middleware.RateLimit(loginRateLimit), controller.login)
+
+	// registered before the admin routes, otherwise /users/:id would catch
+	// /users/me. Each route carries its own auth because a group would also
+	// put it in front of the other /users/me/* routes. Deleting the account,
+	// exporting it, changing the password and changing the email stay closed
+	// to personal access tokens.
+	meRoute := router.Group("/users/me")
+	meRoute.Get("/", middleware.RequireAuth(entity.ScopeProfileRead), controller.getProfile)
+	meRoute.Patch("/", middleware.RequireAuth(entity.ScopeProfileWrite), controller.updateProfile)
+	meRoute.Delete("/", middleware.RequireAuth(), controller.deleteAccount)
+	meRoute.Get("/export", middleware.RequireAuth(), middleware.RateLimit(exportRateLimit), controller.exportData)
+	meRoute.Put(
+		"/password",
+		middleware.RequireAuth(),
+		middleware.RateLimit(changePasswordRateLimit),
+		controller.changePassword,
+	)
+	meRoute.Put("/avatar", middleware.RequireAuth(entity.ScopeProfileWrite), controller.updateAvatar)
+
+	// a /users group would put the admin check in front of /users/me as well,
+	// so every admin route carries it on its own
+	requireUsersRead := []fiber.Handler{
+		middleware.RequireAuth(entity.ScopeUsersRead),
+		middleware.RequireRoles(entity.RoleAdmin),
+	}
+	requireUsersWrite := []fiber.Handler{
+		middleware.RequireAuth(entity.ScopeUsersWrite),
+		middleware.RequireRoles(entity.RoleAdmin),
+	}
+
+	router.Get("/users", append(requireUsersRead, controller.listUsers)...)
+	router.Post("/users", append(requireUsersWrite, controller.createUser)...)
+	router.Get("/users/:id", append(requireUsersRead, controller.getUser)...)
+	router.Patch("/users/:id", append(requireUsersWrite, controller.updateUser)...)
+	router.Delete("/users/:id", append(requireUsersWrite, controller.deleteUser)...)
+	router.Post("/users/:id/restore", append(requireUsersWrite, controller.restoreUser)...)
+}
+
+func (c *userController) register(ctx *fiber.Ctx) error {
+	var req dto.RegisterRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	req.Locale = ctx.AcceptsLanguages(mail.Locales...)
+
+	res, err := c.userService.Register(ctx.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusCreated, res)
+}
+
+func (c *userController) login(ctx *fiber.Ctx) error {
+	var req dto.LoginRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	req.IPAddress = ctx.IP()
+
+	res, err := c.userService.Login(ctx.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
+
+func (c *userController) getProfile(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.userService.GetUser(ctx.Context(), claims.UserID, false)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
+
+func (c *userController) updateProfile(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	var req dto.UpdateProfileRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	// the email is what password resets and magic links go to, so changing it
+	// stays closed to personal access tokens like the password
+	if req.Email != nil && claims.PersonalAccessTokenID != uuid.Nil {
+		return domain.ErrInsufficientTokenScope
+	}
+
+	req.IPAddress = ctx.IP()
+
+	res, err := c.userService.UpdateProfile(ctx.Context(), claims.UserID, req)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
+
+func (c *userController) changePassword(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	var req dto.ChangePasswordRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	req.IPAddress = ctx.IP()
+
+	res, err := c.userService.ChangePassword(ctx.Context(), claims, req)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
+
+func (c *userController) deleteAccount(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.userService.DeleteAccount(ctx.Context(), claims.UserID); err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, "account has been deleted")
+}
+
+func (c *userController) exportData(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.userService.ExportData(ctx.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	ctx.Attachment(res.FileName)
+	ctx.Set(fiber.HeaderContentType, res.ContentType)
+	ctx.Set(fiber.HeaderCacheControl, "no-store")
+
+	return ctx.Send(res.Content)
+}
+
+func (c *userController) updateAvatar(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	fileHeader, err := ctx.FormFile("avatar")
+	if err != nil {
+		return domain.ErrFileRequired
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	res, err := c.userService.UpdateAvatar(ctx.Context(), claims.UserID, dto.UploadFileRequest{
+		Name:    fileHeader.Filename,
+		Size:    fileHeader.Size,
+		Content: file,
+	})
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
+
+func (c *userController) listUsers(ctx *fiber.Ctx) error {
+	var query dto.UserListQuery
+	if err := ctx.QueryParser(&query); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	res, err := c.userService.ListUsers(ctx.Context(), query)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
+
+func (c *userController) getUser(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	res, err := c.userService.GetUser(ctx.Context(), id, ctx.QueryBool("include_deleted"))
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
+
+func (c *userController) createUser(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	var req dto.CreateUserRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	res, err := c.userService.CreateUser(ctx.Context(), claims.RoleName, req)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusCreated, res)
+}
+
+func (c *userController) updateUser(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	var req dto.UpdateUserRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	res, err := c.userService.UpdateUser(ctx.Context(), claims.UserID, claims.RoleName, id, req)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
+
+func (c *userController) deleteUser(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	if err := c.userService.DeleteUser(ctx.Context(), claims.UserID, claims.RoleName, id); err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, "user has been deleted")
+}
+
+func (c *userController) restoreUser(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	res, err := c.userService.RestoreUser(ctx.Context(), claims.RoleName, id)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
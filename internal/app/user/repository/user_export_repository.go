@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+// userExportRepository gathers what other modules store about a user for a
+// data export. It only reads, and never selects secrets or token hashes.
+type userExportRepository struct {
+	db *sqlx.DB
+}
+
+func NewUserExportRepository(db *sqlx.DB) contracts.UserExportRepository {
+	return &userExportRepository{
+		db: db,
+	}
+}
+
+func (r *userExportRepository) FindIdentities(ctx context.Context, userID uuid.UUID) ([]entity.UserIdentity, error) {
+	query := `
+		SELECT id, user_id, provider, subject, email, created_at
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	identities := make([]entity.UserIdentity, 0)
+	if err := r.db.SelectContext(ctx, &identities, query, userID); err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[USER EXPORT REPOSITORY][FindIdentities] failed to find user identities")
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+func (r *userExportRepository) FindRefreshTokens(ctx context.Context, userID uuid.UUID) ([]entity.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, family_id, amr, expires_at, used_at, revoked_at, created_at
+		FROM refresh_tokens
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	tokens := make([]entity.RefreshToken, 0)
+	if err := r.db.SelectContext(ctx, &tokens, query, userID); err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[USER EXPORT REPOSITORY][FindRefreshTokens] failed to find refresh tokens")
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (r *userExportRepository) FindPasskeys(ctx context.Context, userID uuid.UUID) ([]entity.WebauthnCredential, error) {
+	query := `
+		SELECT id, user_id, name, last_used_at, created_at
+		FROM webauthn_credentials
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	passkeys := make([]entity.WebauthnCredential, 0)
+	if err := r.db.SelectContext(ctx, &passkeys, query, userID); err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[USER EXPORT REPOSITORY][FindPasskeys] failed to find passkeys")
+		return nil, err
+	}
+
+	return passkeys, nil
+}
+
+func (r *userExportRepository) FindPersonalAccessTokens(
+	ctx context.Context,
+	userID uuid.UUID,
+) ([]entity.PersonalAccessToken, error) {
+	query := `
+		SELECT id, user_id, name, token_hint, scopes, expires_at, last_used_at, last_used_ip, created_at
+		FROM personal_access_tokens
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	tokens := make([]entity.PersonalAccessToken, 0)
+	if err := r.db.SelectContext(ctx, &tokens, query, userID); err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[USER EXPORT REPOSITORY][FindPersonalAccessTokens] failed to find personal access tokens")
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (r *userExportRepository) FindTotp(ctx context.Context, userID uuid.UUID) (entity.UserTotp, error) {
+	query := `
+		SELECT user_id, confirmed_at, created_at
+		FROM user_totp
+		WHERE user_id = $1
+	`
+
+	var totp entity.UserTotp
+	err := r.db.GetContext(ctx, &totp, query, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return totp, domain.ErrMFAEnrollmentNotFound
+		}
+
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[USER EXPORT REPOSITORY][FindTotp] failed to find totp enrollment")
+		return totp, err
+	}
+
+	return totp, nil
+}
+
+func (r *userExportRepository) CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM mfa_recovery_codes WHERE user_id = $1 AND used_at IS NULL`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, userID); err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[USER EXPORT REPOSITORY][CountRecoveryCodes] failed to count recovery codes")
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// FindAuditLogs returns the entries where the user either acted or was acted on
+func (r *userExportRepository) FindAuditLogs(ctx context.Context, userID uuid.UUID) ([]entity.AuditLog, error) {
+	query := `
+		SELECT id, actor_id, action, subject_id, metadata, created_at
+		FROM audit_logs
+		WHERE actor_id = $1 OR subject_id = $1
+		ORDER BY created_at
+	`
+
+	logs := make([]entity.AuditLog, 0)
+	if err := r.db.SelectContext(ctx, &logs, query, userID); err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[USER EXPORT REPOSITORY][FindAuditLogs] failed to find audit logs")
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+// FindMails leaves the bodies out, they hold sign-in and reset links that may
+// still be valid.
+func (r *userExportRepository) FindMails(ctx context.Context, recipient string) ([]entity.MailOutbox, error) {
+	query := `
+		SELECT id, recipient, subject, status, sent_at, created_at
+		FROM mail_outbox
+		WHERE recipient = $1
+		ORDER BY created_at
+	`
+
+	mails := make([]entity.MailOutbox, 0)
+	if err := r.db.SelectContext(ctx, &mails, query, recipient); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[USER EXPORT REPOSITORY][FindMails] failed to find mails")
+		return nil, err
+	}
+
+	return mails, nil
+}
@@ -0,0 +1,569 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+const selectUserQuery = `
+	SELECT
+		u.id,
+		u.name,
+		u.email,
+		u.password,
+		u.created_at,
+		u.updated_at,
+		u.deleted_at,
+		u.email_verified_at,
+		u.avatar_key,
+		u.locale,
+		u.pending_email,
+		u.role_id,
+		r.id AS "role.id",
+		r.name AS "role.name"
+	FROM users u
+	JOIN roles r ON r.id = u.role_id
+`
+
+// filterUserQuery is shared by FindAll and its count so both see the same rows
+const filterUserQuery = `
+	WHERE ($1 OR u.deleted_at IS NULL)
+	AND ($2 = '' OR u.name ILIKE '%' || $2 || '%' OR u.email ILIKE '%' || $2 || '%')
+`
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type userRepository struct {
+	db *sqlx.DB
+}
+
+func NewUserRepository(db *sqlx.DB) contracts.UserRepository {
+	return &userRepository{
+		db: db,
+	}
+}
+
+// Create leaves role_id to the column default unless the user already has a
+// role set.
+func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
+	query := `
+		INSERT INTO users (id, name, email, password, locale)
+		VALUES (:id, :name, :email, :password, :locale)
+	`
+	if user.RoleID != 0 {
+		query = `
+			INSERT INTO users (id, name, email, password, locale, role_id)
+			VALUES (:id, :name, :email, :password, :locale, :role_id)
+		`
+	}
+
+	_, err := r.db.NamedExecContext(ctx, query, user)
+	if err != nil {
+		if helpers.IsUniqueViolation(err) {
+			return domain.ErrUserEmailAlreadyExists
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[USER REPOSITORY][Create] failed to create user")
+		return err
+	}
+
+	return nil
+}
+
+func (r *userRepository) FindByID(ctx context.Context, id uuid.UUID) (entity.User, error) {
+	query := selectUserQuery + `WHERE u.id = $1 AND u.deleted_at IS NULL`
+
+	var user entity.User
+	err := r.db.GetContext(ctx, &user, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return user, domain.ErrUserNotFound
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    id,
+		}, "[USER REPOSITORY][FindByID] failed to find user by id")
+		return user, err
+	}
+
+	return user, nil
+}
+
+// FindByIDWithDeleted also finds soft deleted users, it is meant for admins
+// looking at or restoring an account.
+func (r *userRepository) FindByIDWithDeleted(ctx context.Context, id uuid.UUID) (entity.User, error) {
+	query := selectUserQuery + `WHERE u.id = $1`
+
+	var user entity.User
+	err := r.db.GetContext(ctx, &user, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return user, domain.ErrUserNotFound
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    id,
+		}, "[USER REPOSITORY][FindByIDWithDeleted] failed to find user by id")
+		return user, err
+	}
+
+	return user, nil
+}
+
+// FindAll returns a page of users, newest first, along with the number of
+// users matching the filter across every page.
+func (r *userRepository) FindAll(ctx context.Context, filter entity.UserFilter) ([]entity.User, int64, error) {
+	search := likeEscaper.Replace(filter.Search)
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM users u` + filterUserQuery
+	err := r.db.GetContext(ctx, &total, countQuery, filter.IncludeDeleted, search)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[USER REPOSITORY][FindAll] failed to count users")
+		return nil, 0, err
+	}
+
+	query := selectUserQuery + filterUserQuery + `
+		ORDER BY u.created_at DESC, u.id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	users := make([]entity.User, 0)
+	err = r.db.SelectContext(ctx, &users, query, filter.IncludeDeleted, search, filter.Limit, filter.Offset)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[USER REPOSITORY][FindAll] failed to find users")
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (entity.User, error) {
+	query := selectUserQuery + `WHERE u.email = $1 AND u.deleted_at IS NULL`
+
+	var user entity.User
+	err := r.db.GetContext(ctx, &user, query, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return user, domain.ErrUserNotFound
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"email": email,
+		}, "[USER REPOSITORY][FindByEmail] failed to find user by email")
+		return user, err
+	}
+
+	return user, nil
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, id uuid.UUID, password string) error {
+	query := `
+		UPDATE users
+		SET password = $2, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, password)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    id,
+		}, "[USER REPOSITORY][UpdatePassword] failed to update user password")
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// MarkEmailVerified only sets email_verified_at once, later calls keep the
+// original timestamp.
+func (r *userRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET email_verified_at = COALESCE(email_verified_at, NOW()), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    id,
+		}, "[USER REPOSITORY][MarkEmailVerified] failed to mark user email as verified")
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateAvatar(ctx context.Context, id uuid.UUID, avatarKey string) error {
+	query := `
+		UPDATE users
+		SET avatar_key = $2, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, avatarKey)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    id,
+		}, "[USER REPOSITORY][UpdateAvatar] failed to update user avatar")
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UpdateProfile saves the name, email and locale, a changed email has to be
+// verified again.
+func (r *userRepository) UpdateProfile(ctx context.Context, user *entity.User) error {
+	query := `
+		UPDATE users
+		SET
+			name = :name,
+			email = :email,
+			email_verified_at = CASE WHEN email = :email THEN email_verified_at ELSE NULL END,
+			locale = :locale,
+			updated_at = NOW()
+		WHERE id = :id AND deleted_at IS NULL
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, user)
+	if err != nil {
+		if helpers.IsUniqueViolation(err) {
+			return domain.ErrUserEmailAlreadyExists
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    user.ID,
+		}, "[USER REPOSITORY][UpdateProfile] failed to update user profile")
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *userRepository) SetPendingEmail(ctx context.Context, id uuid.UUID, email string) error {
+	query := `
+		UPDATE users
+		SET pending_email = $2, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, email)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    id,
+		}, "[USER REPOSITORY][SetPendingEmail] failed to set user pending email")
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// ConfirmPendingEmail swaps the pending email in as the verified address, the
+// confirmation link went to it so it needs no further verification.
+func (r *userRepository) ConfirmPendingEmail(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET email = pending_email, pending_email = NULL, email_verified_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND pending_email IS NOT NULL AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		if helpers.IsUniqueViolation(err) {
+			return domain.ErrUserEmailAlreadyExists
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    id,
+		}, "[USER REPOSITORY][ConfirmPendingEmail] failed to confirm user pending email")
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.ErrInvalidEmailChangeToken
+	}
+
+	return nil
+}
+
+// UpdateRole writes the audit log in the same transaction, a role change is
+// never saved without a record of who made it.
+func (r *userRepository) UpdateRole(ctx context.Context, id uuid.UUID, roleID int, audit *entity.AuditLog) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[USER REPOSITORY][UpdateRole] failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	query := `
+		UPDATE users
+		SET role_id = $2, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, query, id, roleID)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    id,
+		}, "[USER REPOSITORY][UpdateRole] failed to update user role")
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	auditQuery := `
+		INSERT INTO audit_logs (id, actor_id, action, subject_id, metadata)
+		VALUES (:id, :actor_id, :action, :subject_id, :metadata)
+	`
+
+	if _, err := tx.NamedExecContext(ctx, auditQuery, audit); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    id,
+		}, "[USER REPOSITORY][UpdateRole] failed to create audit log")
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[USER REPOSITORY][UpdateRole] failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func (r *userRepository) CreateAuditLog(ctx context.Context, audit *entity.AuditLog) error {
+	query := `
+		INSERT INTO audit_logs (id, actor_id, action, subject_id, metadata)
+		VALUES (:id, :actor_id, :action, :subject_id, :metadata)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, audit); err != nil {
+		log.Error(log.LogInfo{
+			"error":  err.Error(),
+			"action": audit.Action,
+		}, "[USER REPOSITORY][CreateAuditLog] failed to create audit log")
+		return err
+	}
+
+	return nil
+}
+
+func (r *userRepository) SoftDelete(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    id,
+		}, "[USER REPOSITORY][SoftDelete] failed to delete user")
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *userRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET deleted_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    id,
+		}, "[USER REPOSITORY][Restore] failed to restore user")
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *userRepository) FindDeletedBefore(ctx context.Context, cutoff time.Time, limit int) ([]entity.User, error) {
+	query := selectUserQuery + `
+		WHERE u.deleted_at IS NOT NULL AND u.deleted_at <= $1
+		ORDER BY u.deleted_at
+		LIMIT $2
+	`
+
+	users := make([]entity.User, 0)
+	if err := r.db.SelectContext(ctx, &users, query, cutoff, limit); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[USER REPOSITORY][FindDeletedBefore] failed to find deleted users")
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// Purge erases a soft deleted user for good. Everything the user owns goes
+// with the row through on delete cascade, the mail outbox is only linked by
+// address so it is cleared here. Users restored in the meantime are skipped.
+func (r *userRepository) Purge(ctx context.Context, id uuid.UUID, cutoff time.Time) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[USER REPOSITORY][Purge] failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	query := `
+		DELETE FROM users
+		WHERE id = $1 AND deleted_at IS NOT NULL AND deleted_at <= $2
+		RETURNING email, pending_email
+	`
+
+	var user entity.User
+	err = tx.GetContext(ctx, &user, query, id, cutoff)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    id,
+		}, "[USER REPOSITORY][Purge] failed to delete user")
+		return err
+	}
+
+	// a pending email was mailed its confirmation link as well
+	query = `DELETE FROM mail_outbox WHERE recipient = $1 OR recipient = $2`
+	if _, err := tx.ExecContext(ctx, query, user.Email, user.PendingEmail); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    id,
+		}, "[USER REPOSITORY][Purge] failed to delete mails")
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[USER REPOSITORY][Purge] failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,1143 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/bcrypt"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers"
+	imageprocessor "github.com/kelompok1-swe-academya/caper-be/pkg/image_processor"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/mail"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/storage"
+	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+	uuidPkg "github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
+)
+
+const (
+	avatarSmall     = 64
+	avatarMedium    = 128
+	avatarLarge     = 256
+	avatarURLExpiry = time.Hour
+
+	defaultUserPageLimit = 20
+	purgeBatchSize       = 100
+)
+
+// dummyPasswordHash is compared against when the email is unknown so the
+// response takes as long as for a wrong password
+const dummyPasswordHash = "$2a$10$wZg10brq5aGXojff7PfVGOnA4e.OX8f7csTLEm8.kqKXFNZea/O8u"
+
+var avatarTypes = map[string]struct{}{
+	"image/jpeg": {},
+	"image/png":  {},
+	"image/webp": {},
+}
+
+type userService struct {
+	userRepo                 contracts.UserRepository
+	userExportRepo           contracts.UserExportRepository
+	roleRepo                 contracts.RoleRepository
+	sessionService           contracts.SessionService
+	mfaService               contracts.MFAService
+	loginThrottleService     contracts.LoginThrottleService
+	emailVerificationService contracts.EmailVerificationService
+	emailChangeService       contracts.EmailChangeService
+	roleService              contracts.RoleService
+	validator                validator.ValidatorInterface
+	uuid                     uuidPkg.UUIDInterface
+	bcrypt                   bcrypt.BcryptInterface
+	storage                  storage.StorageInterface
+	imageProcessor           imageprocessor.ImageProcessorInterface
+	time                     timePkg.TimeInterface
+}
+
+func NewUserService(
+	userRepo contracts.UserRepository,
+	userExportRepo contracts.UserExportRepository,
+	roleRepo contracts.RoleRepository,
+	sessionService contracts.SessionService,
+	mfaService contracts.MFAService,
+	loginThrottleService contracts.LoginThrottleService,
+	emailVerificationService contracts.EmailVerificationService,
+	emailChangeService contracts.EmailChangeService,
+	roleService contracts.RoleService,
+	validator validator.ValidatorInterface,
+	uuid uuidPkg.UUIDInterface,
+	bcrypt bcrypt.BcryptInterface,
+	storage storage.StorageInterface,
+	imageProcessor imageprocessor.ImageProcessorInterface,
+	time timePkg.TimeInterface,
+) contracts.UserService {
+	return &userService{
+		userRepo:                 userRepo,
+		userExportRepo:           userExportRepo,
+		roleRepo:                 roleRepo,
+		sessionService:           sessionService,
+		mfaService:               mfaService,
+		loginThrottleService:     loginThrottleService,
+		emailVerificationService: emailVerificationService,
+		emailChangeService:       emailChangeService,
+		roleService:              roleService,
+		validator:                validator,
+		uuid:                     uuid,
+		bcrypt:                   bcrypt,
+		storage:                  storage,
+		imageProcessor:           imageProcessor,
+		time:                     time,
+	}
+}
+
+func (s *userService) Register(ctx context.Context, req dto.RegisterRequest) (dto.RegisterResponse, error) {
+	var res dto.RegisterResponse
+
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return res, valErr
+	}
+
+	_, err := s.userRepo.FindByEmail(ctx, req.Email)
+	if err == nil {
+		return res, domain.ErrUserEmailAlreadyExists
+	}
+
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		return res, err
+	}
+
+	id, err := s.uuid.NewV7()
+	if err != nil {
+		return res, err
+	}
+
+	hashedPassword, err := s.bcrypt.Hash(req.Password)
+	if err != nil {
+		return res, err
+	}
+
+	user := entity.User{
+		ID:       id,
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: hashedPassword,
+		Locale:   mail.NormalizeLocale(req.Locale),
+	}
+
+	if err := s.userRepo.Create(ctx, &user); err != nil {
+		return res, err
+	}
+
+	user, err = s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return res, err
+	}
+
+	// the account is usable without a verified email, the user can ask for
+	// another verification mail if this one never arrives
+	if err := s.emailVerificationService.Send(ctx, user); err != nil {
+		log.Warn(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": user.ID,
+		}, "[USER SERVICE][Register] failed to send verification email")
+	}
+
+	token, err := s.sessionService.Issue(ctx, user, []string{jwt.AMRPassword})
+	if err != nil {
+		return res, err
+	}
+
+	res.User = s.toUserResponse(ctx, user)
+	res.Token = token
+
+	return res, nil
+}
+
+func (s *userService) Login(ctx context.Context, req dto.LoginRequest) (dto.LoginResponse, error) {
+	var res dto.LoginResponse
+
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return res, valErr
+	}
+
+	if err := s.loginThrottleService.Check(ctx, req.Email, req.IPAddress); err != nil {
+		return res, err
+	}
+
+	// an unknown email fails exactly like a wrong password so responses
+	// don't reveal which emails are registered
+	user, err := s.userRepo.FindByEmail(ctx, req.Email)
+	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+		return res, err
+	}
+
+	found := err == nil
+	hashed := dummyPasswordHash
+	if found {
+		hashed = user.Password
+	}
+
+	if !s.bcrypt.Compare(req.Password, hashed) || !found {
+		if found {
+			s.audit(ctx, entity.AuditActionPasswordRejected, uuid.NullUUID{}, user.ID, map[string]any{
+				"ip_address": req.IPAddress,
+			})
+		}
+
+		if err := s.loginThrottleService.RecordFailure(ctx, req.Email, req.IPAddress); err != nil {
+			return res, err
+		}
+
+		return res, domain.ErrCredentialsNotMatch
+	}
+
+	res, err = s.mfaService.SignIn(ctx, user, []string{jwt.AMRPassword})
+	if err != nil {
+		return res, err
+	}
+
+	s.audit(ctx, entity.AuditActionPasswordLogin, uuid.NullUUID{UUID: user.ID, Valid: true}, user.ID, map[string]any{
+		"ip_address":   req.IPAddress,
+		"mfa_required": res.MFARequired,
+	})
+
+	// with a second factor pending the counters are only cleared once it is
+	// verified, so the password alone can't reset the lockout
+	if !res.MFARequired {
+		if err := s.loginThrottleService.RecordSuccess(ctx, req.Email); err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+// UpdateAvatar stores the thumbnails under a new key on every upload so
+// cached urls of the previous avatar never serve the new image.
+func (s *userService) UpdateAvatar(
+	ctx context.Context,
+	userID uuid.UUID,
+	req dto.UploadFileRequest,
+) (dto.UserResponse, error) {
+	var res dto.UserResponse
+
+	if req.Size > env.AppEnv.UploadMaxSize {
+		return res, domain.ErrFileTooLarge
+	}
+
+	contentType, body, err := helpers.SniffContentType(req.Content)
+	if err != nil {
+		return res, err
+	}
+
+	if contentType == "" {
+		return res, domain.ErrFileRequired
+	}
+
+	if _, ok := avatarTypes[contentType]; !ok {
+		return res, domain.ErrUnsupportedFileType
+	}
+
+	thumbnails, err := s.imageProcessor.Thumbnails(body, avatarSmall, avatarMedium, avatarLarge)
+	if err != nil {
+		if errors.Is(err, imageprocessor.ErrInvalidImage) || errors.Is(err, imageprocessor.ErrImageTooLarge) {
+			return res, domain.ErrInvalidImage
+		}
+
+		return res, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return res, err
+	}
+
+	id, err := s.uuid.NewV7()
+	if err != nil {
+		return res, err
+	}
+
+	avatarKey := fmt.Sprintf("avatars/%s/%s", user.ID, id)
+	for size, thumbnail := range thumbnails {
+		err := s.storage.Put(ctx, avatarThumbnailKey(avatarKey, size), bytes.NewReader(thumbnail), int64(len(thumbnail)), "image/jpeg")
+		if err != nil {
+			s.deleteAvatar(ctx, avatarKey)
+			return res, err
+		}
+	}
+
+	if err := s.userRepo.UpdateAvatar(ctx, user.ID, avatarKey); err != nil {
+		s.deleteAvatar(ctx, avatarKey)
+		return res, err
+	}
+
+	if user.AvatarKey.Valid {
+		s.deleteAvatar(ctx, user.AvatarKey.String)
+	}
+
+	user.AvatarKey.String = avatarKey
+	user.AvatarKey.Valid = true
+
+	return s.toUserResponse(ctx, user), nil
+}
+
+// UpdateProfile lets users change their own name, email and locale, the role
+// and everything else stays with the admins. A new email is only kept as
+// pending until the link mailed to it is followed.
+func (s *userService) UpdateProfile(
+	ctx context.Context,
+	userID uuid.UUID,
+	req dto.UpdateProfileRequest,
+) (dto.UserResponse, error) {
+	var res dto.UserResponse
+
+	req.Name = normalizeName(req.Name)
+	req.Email = normalizeEmail(req.Email)
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return res, valErr
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return res, err
+	}
+
+	emailChanged := req.Email != nil && *req.Email != user.Email
+	if emailChanged {
+		if err := s.verifyPassword(ctx, user, req.CurrentPassword, req.IPAddress); err != nil {
+			return res, err
+		}
+
+		_, err := s.userRepo.FindByEmail(ctx, *req.Email)
+		if err == nil {
+			return res, domain.ErrUserEmailAlreadyExists
+		}
+
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			return res, err
+		}
+	}
+
+	if err := s.saveProfile(ctx, user, req.Name, nil, req.Locale); err != nil {
+		return res, err
+	}
+
+	user, err = s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return res, err
+	}
+
+	if emailChanged {
+		if err := s.emailChangeService.Request(ctx, user, *req.Email); err != nil {
+			return res, err
+		}
+
+		user.PendingEmail = sql.NullString{String: *req.Email, Valid: true}
+	}
+
+	return s.toUserResponse(ctx, user), nil
+}
+
+// ChangePassword signs every other device out. The caller gets a new token
+// pair since the tokens they used for this request are revoked as well.
+func (s *userService) ChangePassword(
+	ctx context.Context,
+	claims jwt.Claims,
+	req dto.ChangePasswordRequest,
+) (dto.TokenResponse, error) {
+	var res dto.TokenResponse
+
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return res, valErr
+	}
+
+	user, err := s.userRepo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return res, err
+	}
+
+	if err := s.verifyPassword(ctx, user, req.CurrentPassword, req.IPAddress); err != nil {
+		return res, err
+	}
+
+	hashedPassword, err := s.bcrypt.Hash(req.NewPassword)
+	if err != nil {
+		return res, err
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		return res, err
+	}
+
+	return s.sessionService.RevokeOtherSessions(ctx, user, claims.AMR)
+}
+
+// ExportData packs everything stored about the user into a zip holding
+// account.json and, when one is set, the avatar.
+func (s *userService) ExportData(ctx context.Context, userID uuid.UUID) (dto.ExportFileResponse, error) {
+	var res dto.ExportFileResponse
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return res, err
+	}
+
+	export, err := s.buildExport(ctx, user)
+	if err != nil {
+		return res, err
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return res, err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	file, err := archive.Create("account.json")
+	if err != nil {
+		return res, err
+	}
+
+	if _, err := file.Write(data); err != nil {
+		return res, err
+	}
+
+	if user.AvatarKey.Valid {
+		if err := s.exportAvatar(ctx, archive, user.AvatarKey.String); err != nil {
+			return res, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return res, err
+	}
+
+	res.FileName = fmt.Sprintf("caper-export-%s.zip", export.ExportedAt.Format("20060102"))
+	res.ContentType = "application/zip"
+	res.Content = buf.Bytes()
+
+	return res, nil
+}
+
+// DeleteAccount only soft deletes, the account can still be restored by an
+// admin until PurgeDeletedUsers erases it after the grace period.
+func (s *userService) DeleteAccount(ctx context.Context, userID uuid.UUID) error {
+	if err := s.userRepo.SoftDelete(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.sessionService.RevokeUserSessions(ctx, userID)
+}
+
+// PurgeDeletedUsers erases users deleted longer than the grace period ago,
+// whether they deleted themselves or an admin did. It handles one batch per
+// run and leaves the rest to the next one.
+func (s *userService) PurgeDeletedUsers(ctx context.Context) error {
+	// an unset grace period would erase accounts the moment they are deleted
+	if env.AppEnv.AccountDeletionGracePeriod <= 0 {
+		return nil
+	}
+
+	cutoff := s.time.Now().Add(-env.AppEnv.AccountDeletionGracePeriod)
+
+	users, err := s.userRepo.FindDeletedBefore(ctx, cutoff, purgeBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		// the avatar goes first, once the row is gone nothing points to it
+		if user.AvatarKey.Valid {
+			if err := s.purgeAvatar(ctx, user.AvatarKey.String); err != nil {
+				return err
+			}
+		}
+
+		err := s.userRepo.Purge(ctx, user.ID, cutoff)
+		if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *userService) ListUsers(ctx context.Context, query dto.UserListQuery) (dto.UserListResponse, error) {
+	var res dto.UserListResponse
+
+	query.Search = strings.TrimSpace(query.Search)
+	if valErr := s.validator.Validate(query); valErr != nil {
+		return res, valErr
+	}
+
+	if query.Page == 0 {
+		query.Page = 1
+	}
+
+	if query.Limit == 0 {
+		query.Limit = defaultUserPageLimit
+	}
+
+	users, total, err := s.userRepo.FindAll(ctx, entity.UserFilter{
+		Search:         query.Search,
+		IncludeDeleted: query.IncludeDeleted,
+		Limit:          query.Limit,
+		Offset:         (query.Page - 1) * query.Limit,
+	})
+	if err != nil {
+		return res, err
+	}
+
+	res.Users = make([]dto.UserResponse, 0, len(users))
+	for _, user := range users {
+		res.Users = append(res.Users, s.toUserResponse(ctx, user))
+	}
+
+	res.Page = query.Page
+	res.Limit = query.Limit
+	res.Total = total
+
+	return res, nil
+}
+
+func (s *userService) GetUser(ctx context.Context, id uuid.UUID, includeDeleted bool) (dto.UserResponse, error) {
+	findUser := s.userRepo.FindByID
+	if includeDeleted {
+		findUser = s.userRepo.FindByIDWithDeleted
+	}
+
+	user, err := findUser(ctx, id)
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
+
+	return s.toUserResponse(ctx, user), nil
+}
+
+func (s *userService) CreateUser(
+	ctx context.Context,
+	actorRole string,
+	req dto.CreateUserRequest,
+) (dto.UserResponse, error) {
+	var res dto.UserResponse
+
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return res, valErr
+	}
+
+	if req.RoleID != 0 {
+		role, err := s.roleRepo.FindByID(ctx, req.RoleID)
+		if err != nil {
+			return res, err
+		}
+
+		if err := s.ensureCanManage(ctx, actorRole, role.Name); err != nil {
+			return res, err
+		}
+	}
+
+	id, err := s.uuid.NewV7()
+	if err != nil {
+		return res, err
+	}
+
+	hashedPassword, err := s.bcrypt.Hash(req.Password)
+	if err != nil {
+		return res, err
+	}
+
+	user := entity.User{
+		ID:       id,
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: hashedPassword,
+		Locale:   mail.DefaultLocale,
+		RoleID:   req.RoleID,
+	}
+
+	if err := s.userRepo.Create(ctx, &user); err != nil {
+		return res, err
+	}
+
+	user, err = s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return res, err
+	}
+
+	if err := s.emailVerificationService.Send(ctx, user); err != nil {
+		log.Warn(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": user.ID,
+		}, "[USER SERVICE][CreateUser] failed to send verification email")
+	}
+
+	return s.toUserResponse(ctx, user), nil
+}
+
+// UpdateUser checks every field before writing any of them so a rejected role
+// change doesn't leave a half applied update behind.
+func (s *userService) UpdateUser(
+	ctx context.Context,
+	actorID uuid.UUID,
+	actorRole string,
+	id uuid.UUID,
+	req dto.UpdateUserRequest,
+) (dto.UserResponse, error) {
+	var res dto.UserResponse
+
+	req.Name = normalizeName(req.Name)
+	req.Email = normalizeEmail(req.Email)
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return res, valErr
+	}
+
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return res, err
+	}
+
+	if err := s.ensureCanManage(ctx, actorRole, user.Role.Name); err != nil {
+		return res, err
+	}
+
+	var role entity.Role
+	roleChanged := req.RoleID != nil && *req.RoleID != user.RoleID
+	if roleChanged {
+		if id == actorID {
+			return res, domain.ErrCantManageOwnAccount
+		}
+
+		role, err = s.roleRepo.FindByID(ctx, *req.RoleID)
+		if err != nil {
+			return res, err
+		}
+
+		if err := s.ensureCanManage(ctx, actorRole, role.Name); err != nil {
+			return res, err
+		}
+	}
+
+	if err := s.saveProfile(ctx, user, req.Name, req.Email, nil); err != nil {
+		return res, err
+	}
+
+	if roleChanged {
+		if err := s.changeRole(ctx, actorID, user, role); err != nil {
+			return res, err
+		}
+	}
+
+	user, err = s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return res, err
+	}
+
+	return s.toUserResponse(ctx, user), nil
+}
+
+// DeleteUser soft deletes the account and ends its sessions, the row is kept
+// so the account can be restored.
+func (s *userService) DeleteUser(ctx context.Context, actorID uuid.UUID, actorRole string, id uuid.UUID) error {
+	if id == actorID {
+		return domain.ErrCantManageOwnAccount
+	}
+
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.ensureCanManage(ctx, actorRole, user.Role.Name); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.SoftDelete(ctx, id); err != nil {
+		return err
+	}
+
+	return s.sessionService.RevokeUserSessions(ctx, id)
+}
+
+func (s *userService) RestoreUser(ctx context.Context, actorRole string, id uuid.UUID) (dto.UserResponse, error) {
+	var res dto.UserResponse
+
+	user, err := s.userRepo.FindByIDWithDeleted(ctx, id)
+	if err != nil {
+		return res, err
+	}
+
+	if !user.DeletedAt.Valid {
+		return res, domain.ErrUserNotDeleted
+	}
+
+	if err := s.ensureCanManage(ctx, actorRole, user.Role.Name); err != nil {
+		return res, err
+	}
+
+	if err := s.userRepo.Restore(ctx, id); err != nil {
+		return res, err
+	}
+
+	user.DeletedAt = sql.NullTime{}
+
+	return s.toUserResponse(ctx, user), nil
+}
+
+func (s *userService) buildExport(ctx context.Context, user entity.User) (dto.UserExport, error) {
+	export := dto.UserExport{
+		ExportedAt: s.time.Now(),
+		Profile: dto.ExportProfile{
+			ID:        user.ID,
+			Name:      user.Name,
+			Email:     user.Email,
+			RoleName:  user.Role.Name,
+			Locale:    user.Locale,
+			HasAvatar: user.AvatarKey.Valid,
+			CreatedAt: user.CreatedAt,
+			UpdatedAt: user.UpdatedAt,
+		},
+	}
+
+	if user.EmailVerifiedAt.Valid {
+		export.Profile.EmailVerifiedAt = &user.EmailVerifiedAt.Time
+	}
+
+	if user.PendingEmail.Valid {
+		export.Profile.PendingEmail = &user.PendingEmail.String
+	}
+
+	identities, err := s.userExportRepo.FindIdentities(ctx, user.ID)
+	if err != nil {
+		return export, err
+	}
+
+	export.Identities = make([]dto.ExportIdentity, 0, len(identities))
+	for _, identity := range identities {
+		export.Identities = append(export.Identities, dto.ExportIdentity{
+			Provider:  identity.Provider,
+			Subject:   identity.Subject,
+			Email:     identity.Email,
+			CreatedAt: identity.CreatedAt,
+		})
+	}
+
+	refreshTokens, err := s.userExportRepo.FindRefreshTokens(ctx, user.ID)
+	if err != nil {
+		return export, err
+	}
+
+	export.Sessions = make([]dto.ExportSession, 0, len(refreshTokens))
+	for _, refreshToken := range refreshTokens {
+		export.Sessions = append(export.Sessions, dto.ExportSession{
+			AMR:       strings.Fields(refreshToken.AMR),
+			ExpiresAt: refreshToken.ExpiresAt,
+			UsedAt:    nullTimePtr(refreshToken.UsedAt),
+			RevokedAt: nullTimePtr(refreshToken.RevokedAt),
+			CreatedAt: refreshToken.CreatedAt,
+		})
+	}
+
+	passkeys, err := s.userExportRepo.FindPasskeys(ctx, user.ID)
+	if err != nil {
+		return export, err
+	}
+
+	export.Passkeys = make([]dto.PasskeyResponse, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		export.Passkeys = append(export.Passkeys, dto.PasskeyResponse{
+			ID:         passkey.ID,
+			Name:       passkey.Name,
+			LastUsedAt: nullTimePtr(passkey.LastUsedAt),
+			CreatedAt:  passkey.CreatedAt,
+		})
+	}
+
+	personalAccessTokens, err := s.userExportRepo.FindPersonalAccessTokens(ctx, user.ID)
+	if err != nil {
+		return export, err
+	}
+
+	export.PersonalAccessTokens = make([]dto.PersonalAccessTokenResponse, 0, len(personalAccessTokens))
+	for _, personalAccessToken := range personalAccessTokens {
+		res := dto.PersonalAccessTokenResponse{
+			ID:         personalAccessToken.ID,
+			Name:       personalAccessToken.Name,
+			TokenHint:  personalAccessToken.TokenHint,
+			Scopes:     strings.Fields(personalAccessToken.Scopes),
+			ExpiresAt:  personalAccessToken.ExpiresAt,
+			LastUsedAt: nullTimePtr(personalAccessToken.LastUsedAt),
+			CreatedAt:  personalAccessToken.CreatedAt,
+		}
+
+		if personalAccessToken.LastUsedIP.Valid {
+			res.LastUsedIP = &personalAccessToken.LastUsedIP.String
+		}
+
+		export.PersonalAccessTokens = append(export.PersonalAccessTokens, res)
+	}
+
+	totp, err := s.userExportRepo.FindTotp(ctx, user.ID)
+	if err != nil && !errors.Is(err, domain.ErrMFAEnrollmentNotFound) {
+		return export, err
+	}
+
+	export.TwoFactor.TotpEnabled = totp.ConfirmedAt.Valid
+	export.TwoFactor.TotpConfirmedAt = nullTimePtr(totp.ConfirmedAt)
+
+	export.TwoFactor.RecoveryCodesRemaining, err = s.userExportRepo.CountRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return export, err
+	}
+
+	auditLogs, err := s.userExportRepo.FindAuditLogs(ctx, user.ID)
+	if err != nil {
+		return export, err
+	}
+
+	export.AuditLogs = make([]dto.ExportAuditLog, 0, len(auditLogs))
+	for _, auditLog := range auditLogs {
+		export.AuditLogs = append(export.AuditLogs, dto.ExportAuditLog{
+			ActorID:   nullUUIDPtr(auditLog.ActorID),
+			Action:    auditLog.Action,
+			SubjectID: nullUUIDPtr(auditLog.SubjectID),
+			Metadata:  auditLog.Metadata,
+			CreatedAt: auditLog.CreatedAt,
+		})
+	}
+
+	mails, err := s.userExportRepo.FindMails(ctx, user.Email)
+	if err != nil {
+		return export, err
+	}
+
+	export.Mails = make([]dto.ExportMail, 0, len(mails))
+	for _, mail := range mails {
+		export.Mails = append(export.Mails, dto.ExportMail{
+			Subject:   mail.Subject,
+			Status:    mail.Status,
+			SentAt:    nullTimePtr(mail.SentAt),
+			CreatedAt: mail.CreatedAt,
+		})
+	}
+
+	return export, nil
+}
+
+// exportAvatar adds the largest thumbnail, which is the closest thing to the
+// uploaded image that is kept.
+func (s *userService) exportAvatar(ctx context.Context, archive *zip.Writer, avatarKey string) error {
+	body, _, err := s.storage.Get(ctx, avatarThumbnailKey(avatarKey, avatarLarge))
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil
+		}
+
+		return err
+	}
+	defer body.Close()
+
+	file, err := archive.Create("avatar.jpg")
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(file, body)
+	return err
+}
+
+// purgeAvatar unlike deleteAvatar gives up on the first failure, the user is
+// only erased once none of their files are left behind
+func (s *userService) purgeAvatar(ctx context.Context, avatarKey string) error {
+	for _, size := range []int{avatarSmall, avatarMedium, avatarLarge} {
+		err := s.storage.Delete(ctx, avatarThumbnailKey(avatarKey, size))
+		if err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveProfile writes the name, email and locale that were sent. Only admins
+// set the email directly, a changed email loses its verification so a new
+// verification mail goes out.
+func (s *userService) saveProfile(
+	ctx context.Context,
+	user entity.User,
+	name *string,
+	email *string,
+	locale *string,
+) error {
+	if name == nil && email == nil && locale == nil {
+		return nil
+	}
+
+	profile := user
+	if name != nil {
+		profile.Name = *name
+	}
+
+	if email != nil {
+		profile.Email = *email
+	}
+
+	if locale != nil {
+		profile.Locale = *locale
+	}
+
+	if err := s.userRepo.UpdateProfile(ctx, &profile); err != nil {
+		return err
+	}
+
+	if profile.Email == user.Email {
+		return nil
+	}
+
+	profile.EmailVerifiedAt = sql.NullTime{}
+	if err := s.emailVerificationService.Send(ctx, profile); err != nil {
+		log.Warn(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": user.ID,
+		}, "[USER SERVICE][saveProfile] failed to send verification email")
+	}
+
+	return nil
+}
+
+// verifyPassword counts wrong guesses towards the same lockout as failed
+// logins, so a stolen access token can't become a way to guess the password
+func (s *userService) verifyPassword(
+	ctx context.Context,
+	user entity.User,
+	password string,
+	ipAddress string,
+) error {
+	if err := s.loginThrottleService.Check(ctx, user.Email, ipAddress); err != nil {
+		return err
+	}
+
+	if !s.bcrypt.Compare(password, user.Password) {
+		if err := s.loginThrottleService.RecordFailure(ctx, user.Email, ipAddress); err != nil {
+			return err
+		}
+
+		return domain.ErrCredentialsNotMatch
+	}
+
+	return s.loginThrottleService.RecordSuccess(ctx, user.Email)
+}
+
+// changeRole revokes the user's sessions afterwards, the role is carried in
+// the access token so it only takes effect once they sign in again.
+func (s *userService) changeRole(ctx context.Context, actorID uuid.UUID, user entity.User, role entity.Role) error {
+	metadata, err := json.Marshal(map[string]any{
+		"from_role_id": user.RoleID,
+		"from_role":    user.Role.Name,
+		"to_role_id":   role.ID,
+		"to_role":      role.Name,
+	})
+	if err != nil {
+		return err
+	}
+
+	auditID, err := s.uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	audit := entity.AuditLog{
+		ID:        auditID,
+		ActorID:   uuid.NullUUID{UUID: actorID, Valid: true},
+		Action:    entity.AuditActionUserRoleChanged,
+		SubjectID: uuid.NullUUID{UUID: user.ID, Valid: true},
+		Metadata:  metadata,
+	}
+
+	if err := s.userRepo.UpdateRole(ctx, user.ID, role.ID, &audit); err != nil {
+		return err
+	}
+
+	return s.sessionService.RevokeUserSessions(ctx, user.ID)
+}
+
+// audit is best effort, a sign in is not refused because its record could
+// not be written. The actor is only set once the user has signed in.
+func (s *userService) audit(
+	ctx context.Context,
+	action string,
+	actorID uuid.NullUUID,
+	userID uuid.UUID,
+	details map[string]any,
+) {
+	if err := s.createAuditLog(ctx, action, actorID, userID, details); err != nil {
+		log.Warn(log.LogInfo{
+			"error":   err.Error(),
+			"action":  action,
+			"user_id": userID,
+		}, "[USER SERVICE][audit] failed to write audit log")
+	}
+}
+
+func (s *userService) createAuditLog(
+	ctx context.Context,
+	action string,
+	actorID uuid.NullUUID,
+	userID uuid.UUID,
+	details map[string]any,
+) error {
+	metadata, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+
+	auditID, err := s.uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	return s.userRepo.CreateAuditLog(ctx, &entity.AuditLog{
+		ID:        auditID,
+		ActorID:   actorID,
+		Action:    action,
+		SubjectID: uuid.NullUUID{UUID: userID, Valid: true},
+		Metadata:  metadata,
+	})
+}
+
+// ensureCanManage only lets admins act on roles their own role covers, so an
+// admin can't delete a superadmin or promote someone above themselves.
+func (s *userService) ensureCanManage(ctx context.Context, actorRole string, roleName string) error {
+	allowed, err := s.roleService.HasAnyRole(ctx, actorRole, roleName)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return domain.ErrRoleCantAccessResource
+	}
+
+	return nil
+}
+
+// deleteAvatar is best effort, a leftover thumbnail is only wasted space
+func (s *userService) deleteAvatar(ctx context.Context, avatarKey string) {
+	for _, size := range []int{avatarSmall, avatarMedium, avatarLarge} {
+		if err := s.storage.Delete(ctx, avatarThumbnailKey(avatarKey, size)); err != nil {
+			log.Warn(log.LogInfo{
+				"error": err.Error(),
+				"key":   avatarKey,
+			}, "[USER SERVICE][deleteAvatar] failed to delete avatar thumbnail")
+		}
+	}
+}
+
+func (s *userService) toUserResponse(ctx context.Context, user entity.User) dto.UserResponse {
+	res := dto.UserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		RoleName:  user.Role.Name,
+		Locale:    user.Locale,
+		CreatedAt: user.CreatedAt,
+	}
+
+	if user.EmailVerifiedAt.Valid {
+		res.EmailVerifiedAt = &user.EmailVerifiedAt.Time
+	}
+
+	if user.PendingEmail.Valid {
+		res.PendingEmail = &user.PendingEmail.String
+	}
+
+	if user.AvatarKey.Valid {
+		res.Avatar = s.avatarResponse(ctx, user.AvatarKey.String)
+	}
+
+	if user.DeletedAt.Valid {
+		res.DeletedAt = &user.DeletedAt.Time
+	}
+
+	return res
+}
+
+func (s *userService) avatarResponse(ctx context.Context, avatarKey string) *dto.AvatarResponse {
+	urls := make(map[int]string, 3)
+	expiresAt := s.time.Add(avatarURLExpiry)
+
+	for _, size := range []int{avatarSmall, avatarMedium, avatarLarge} {
+		url, err := s.storage.SignedURL(ctx, avatarThumbnailKey(avatarKey, size), expiresAt)
+		if err != nil {
+			log.Warn(log.LogInfo{
+				"error": err.Error(),
+				"key":   avatarKey,
+			}, "[USER SERVICE][avatarResponse] failed to sign avatar url")
+			return nil
+		}
+
+		urls[size] = url
+	}
+
+	return &dto.AvatarResponse{
+		Small:  urls[avatarSmall],
+		Medium: urls[avatarMedium],
+		Large:  urls[avatarLarge],
+	}
+}
+
+func normalizeName(name *string) *string {
+	if name == nil {
+		return nil
+	}
+
+	trimmed := strings.TrimSpace(*name)
+	return &trimmed
+}
+
+func normalizeEmail(email *string) *string {
+	if email == nil {
+		return nil
+	}
+
+	normalized := strings.ToLower(strings.TrimSpace(*email))
+	return &normalized
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+
+	return &t.Time
+}
+
+func nullUUIDPtr(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+
+	return &id.UUID
+}
+
+func avatarThumbnailKey(avatarKey string, size int) string {
+	return fmt.Sprintf("%s/%d.jpg", avatarKey, size)
+}
@@ -0,0 +1,161 @@
+package rest
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/internal/middlewares"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers/http/response"
+	ratelimiter "github.com/kelompok1-swe-academya/caper-be/pkg/rate_limiter"
+)
+
+var (
+	// passkeyLoginRateLimit covers both steps of a login, so it allows twice
+	// the requests of a password login
+	passkeyLoginRateLimit = middlewares.RateLimitPolicy{
+		Name: "passkey_login",
+		Policy: ratelimiter.Policy{
+			Algorithm: ratelimiter.SlidingWindow,
+			Limit:     20,
+			Window:    15 * time.Minute,
+		},
+		Key:        middlewares.KeyByIP,
+		FailClosed: true,
+	}
+
+	// starting a registration checks the password or a second factor
+	passkeyRegisterRateLimit = middlewares.RateLimitPolicy{
+		Name: "passkey_register",
+		Policy: ratelimiter.Policy{
+			Algorithm: ratelimiter.SlidingWindow,
+			Limit:     5,
+			Window:    15 * time.Minute,
+		},
+		Key:        middlewares.KeyByUser,
+		FailClosed: true,
+	}
+)
+
+type webauthnController struct {
+	webauthnService contracts.WebauthnService
+}
+
+func InitWebauthnController(
+	router fiber.Router,
+	webauthnService contracts.WebauthnService,
+	middleware *middlewares.Middleware,
+) {
+	controller := webauthnController{
+		webauthnService: webauthnService,
+	}
+
+	passkeyRoute := router.Group("/auth/passkeys", middleware.RateLimit(passkeyLoginRateLimit))
+	passkeyRoute.Post("/login/options", controller.beginLogin)
+	passkeyRoute.Post("/login", controller.finishLogin)
+
+	meRoute := router.Group("/users/me/passkeys", middleware.RequireAuth())
+	meRoute.Get("/", controller.list)
+	meRoute.Post("/options", middleware.RateLimit(passkeyRegisterRateLimit), controller.beginRegistration)
+	meRoute.Post("/", controller.finishRegistration)
+	meRoute.Delete("/:id", controller.delete)
+}
+
+func (c *webauthnController) beginLogin(ctx *fiber.Ctx) error {
+	res, err := c.webauthnService.BeginLogin(ctx.Context())
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
+
+func (c *webauthnController) finishLogin(ctx *fiber.Ctx) error {
+	var req dto.PasskeyLoginRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	res, err := c.webauthnService.FinishLogin(ctx.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
+
+func (c *webauthnController) list(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.webauthnService.List(ctx.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
+
+func (c *webauthnController) beginRegistration(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	var req dto.ReauthRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	req.IPAddress = ctx.IP()
+
+	res, err := c.webauthnService.BeginRegistration(ctx.Context(), claims.UserID, req)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, res)
+}
+
+func (c *webauthnController) finishRegistration(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	var req dto.PasskeyRegisterRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return domain.ErrInvalidRequestBody
+	}
+
+	res, err := c.webauthnService.FinishRegistration(ctx.Context(), claims.UserID, req)
+	if err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusCreated, res)
+}
+
+func (c *webauthnController) delete(ctx *fiber.Ctx) error {
+	claims, err := middlewares.GetClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return domain.ErrPasskeyNotFound
+	}
+
+	if err := c.webauthnService.Delete(ctx.Context(), claims.UserID, id); err != nil {
+		return err
+	}
+
+	return response.SendResponse(ctx, fiber.StatusOK, "passkey has been deleted")
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+type webauthnRepository struct {
+	db *sqlx.DB
+}
+
+func NewWebauthnRepository(db *sqlx.DB) contracts.WebauthnRepository {
+	return &webauthnRepository{
+		db: db,
+	}
+}
+
+func (r *webauthnRepository) CreateCredential(ctx context.Context, credential *entity.WebauthnCredential) error {
+	query := `
+		INSERT INTO webauthn_credentials (
+			id, user_id, name, credential_id, public_key, attestation_type,
+			transports, aaguid, sign_count, backup_eligible, backup_state
+		)
+		VALUES (
+			:id, :user_id, :name, :credential_id, :public_key, :attestation_type,
+			:transports, :aaguid, :sign_count, :backup_eligible, :backup_state
+		)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, credential)
+	if err != nil {
+		if helpers.IsUniqueViolation(err) {
+			return domain.ErrPasskeyAlreadyRegistered
+		}
+
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": credential.UserID,
+		}, "[WEBAUTHN REPOSITORY][CreateCredential] failed to create webauthn credential")
+		return err
+	}
+
+	return nil
+}
+
+func (r *webauthnRepository) FindCredentialsByUserID(
+	ctx context.Context,
+	userID uuid.UUID,
+) ([]entity.WebauthnCredential, error) {
+	query := `
+		SELECT
+			id, user_id, name, credential_id, public_key, attestation_type, transports,
+			aaguid, sign_count, backup_eligible, backup_state, last_used_at, created_at
+		FROM webauthn_credentials
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	credentials := make([]entity.WebauthnCredential, 0)
+	err := r.db.SelectContext(ctx, &credentials, query, userID)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[WEBAUTHN REPOSITORY][FindCredentialsByUserID] failed to find webauthn credentials")
+		return nil, err
+	}
+
+	return credentials, nil
+}
+
+// UpdateSignCount only moves the counter forward, when two logins with the
+// same counter race only the first one is accepted
+func (r *webauthnRepository) UpdateSignCount(
+	ctx context.Context,
+	id uuid.UUID,
+	signCount int64,
+	backupState bool,
+	now time.Time,
+) error {
+	query := `
+		UPDATE webauthn_credentials
+		SET sign_count = $2, backup_state = $3, last_used_at = $4
+		WHERE id = $1 AND (sign_count < $2 OR $2 = 0)
+	`
+
+	res, err := r.db.ExecContext(ctx, query, id, signCount, backupState, now)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"id":    id,
+		}, "[WEBAUTHN REPOSITORY][UpdateSignCount] failed to update webauthn sign count")
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrPasskeyVerificationFailed
+	}
+
+	return nil
+}
+
+func (r *webauthnRepository) DeleteCredential(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	query := `DELETE FROM webauthn_credentials WHERE id = $1 AND user_id = $2`
+
+	res, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": userID,
+		}, "[WEBAUTHN REPOSITORY][DeleteCredential] failed to delete webauthn credential")
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return domain.ErrPasskeyNotFound
+	}
+
+	return nil
+}
+
+func (r *webauthnRepository) CreateSession(ctx context.Context, session *entity.WebauthnSession) error {
+	query := `
+		INSERT INTO webauthn_sessions (id, user_id, ceremony, session_data, expires_at)
+		VALUES (:id, :user_id, :ceremony, :session_data, :expires_at)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, session)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[WEBAUTHN REPOSITORY][CreateSession] failed to create webauthn session")
+		return err
+	}
+
+	return nil
+}
+
+// ConsumeSession deletes the session and returns it in a single statement so
+// a challenge can only be answered once
+func (r *webauthnRepository) ConsumeSession(
+	ctx context.Context,
+	id uuid.UUID,
+	ceremony string,
+	now time.Time,
+) (entity.WebauthnSession, error) {
+	query := `
+		DELETE FROM webauthn_sessions
+		WHERE id = $1 AND ceremony = $2 AND expires_at > $3
+		RETURNING id, user_id, ceremony, session_data, expires_at, created_at
+	`
+
+	var session entity.WebauthnSession
+	err := r.db.GetContext(ctx, &session, query, id, ceremony, now)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return session, domain.ErrInvalidPasskeyCeremony
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[WEBAUTHN REPOSITORY][ConsumeSession] failed to consume webauthn session")
+		return session, err
+	}
+
+	return session, nil
+}
+
+func (r *webauthnRepository) DeleteExpiredSessions(ctx context.Context) error {
+	query := `DELETE FROM webauthn_sessions WHERE expires_at <= NOW()`
+
+	if _, err := r.db.ExecContext(ctx, query); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[WEBAUTHN REPOSITORY][DeleteExpiredSessions] failed to delete expired webauthn sessions")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,368 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/mail"
+	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+	uuidPkg "github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/webauthn"
+)
+
+type webauthnService struct {
+	webauthnRepo   contracts.WebauthnRepository
+	userRepo       contracts.UserRepository
+	sessionService contracts.SessionService
+	mfaService     contracts.MFAService
+	mailService    contracts.MailService
+	validator      validator.ValidatorInterface
+	uuid           uuidPkg.UUIDInterface
+	webauthn       webauthn.WebauthnInterface
+	time           timePkg.TimeInterface
+}
+
+func NewWebauthnService(
+	webauthnRepo contracts.WebauthnRepository,
+	userRepo contracts.UserRepository,
+	sessionService contracts.SessionService,
+	mfaService contracts.MFAService,
+	mailService contracts.MailService,
+	validator validator.ValidatorInterface,
+	uuid uuidPkg.UUIDInterface,
+	webauthn webauthn.WebauthnInterface,
+	time timePkg.TimeInterface,
+) contracts.WebauthnService {
+	return &webauthnService{
+		webauthnRepo:   webauthnRepo,
+		userRepo:       userRepo,
+		sessionService: sessionService,
+		mfaService:     mfaService,
+		mailService:    mailService,
+		validator:      validator,
+		uuid:           uuid,
+		webauthn:       webauthn,
+		time:           time,
+	}
+}
+
+// BeginRegistration asks for the password or a second factor again. A passkey
+// signs in without either, so an access token alone must not be enough to add
+// one. The ceremony is bound to the user, which covers FinishRegistration.
+func (s *webauthnService) BeginRegistration(
+	ctx context.Context,
+	userID uuid.UUID,
+	req dto.ReauthRequest,
+) (dto.PasskeyOptionsResponse, error) {
+	var res dto.PasskeyOptionsResponse
+
+	if err := s.mfaService.Reauthenticate(ctx, userID, req); err != nil {
+		return res, err
+	}
+
+	user, passkeys, err := s.findUser(ctx, userID)
+	if err != nil {
+		return res, err
+	}
+
+	options, sessionData, err := s.webauthn.BeginRegistration(toWebauthnUser(user, passkeys))
+	if err != nil {
+		return res, err
+	}
+
+	return s.createSession(
+		ctx,
+		entity.WebauthnCeremonyRegistration,
+		uuid.NullUUID{UUID: userID, Valid: true},
+		options,
+		sessionData,
+	)
+}
+
+func (s *webauthnService) FinishRegistration(
+	ctx context.Context,
+	userID uuid.UUID,
+	req dto.PasskeyRegisterRequest,
+) (dto.PasskeyResponse, error) {
+	var res dto.PasskeyResponse
+
+	req.Name = strings.TrimSpace(req.Name)
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return res, valErr
+	}
+
+	session, err := s.webauthnRepo.ConsumeSession(ctx, req.CeremonyID, entity.WebauthnCeremonyRegistration, s.time.Now())
+	if err != nil {
+		return res, err
+	}
+
+	if session.UserID.UUID != userID {
+		return res, domain.ErrInvalidPasskeyCeremony
+	}
+
+	user, passkeys, err := s.findUser(ctx, userID)
+	if err != nil {
+		return res, err
+	}
+
+	credential, err := s.webauthn.FinishRegistration(toWebauthnUser(user, passkeys), session.SessionData, req.Credential)
+	if err != nil {
+		if errors.Is(err, webauthn.ErrVerificationFailed) {
+			return res, domain.ErrPasskeyVerificationFailed
+		}
+
+		return res, err
+	}
+
+	id, err := s.uuid.NewV7()
+	if err != nil {
+		return res, err
+	}
+
+	passkey := entity.WebauthnCredential{
+		ID:              id,
+		UserID:          userID,
+		Name:            req.Name,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      strings.Join(credential.Transports, " "),
+		AAGUID:          credential.AAGUID,
+		SignCount:       int64(credential.SignCount),
+		BackupEligible:  credential.BackupEligible,
+		BackupState:     credential.BackupState,
+		CreatedAt:       s.time.Now(),
+	}
+
+	if err := s.webauthnRepo.CreateCredential(ctx, &passkey); err != nil {
+		return res, err
+	}
+
+	s.notifyAdded(ctx, user, passkey)
+
+	return toPasskeyResponse(passkey), nil
+}
+
+func (s *webauthnService) BeginLogin(ctx context.Context) (dto.PasskeyOptionsResponse, error) {
+	options, sessionData, err := s.webauthn.BeginLogin()
+	if err != nil {
+		return dto.PasskeyOptionsResponse{}, err
+	}
+
+	return s.createSession(ctx, entity.WebauthnCeremonyLogin, uuid.NullUUID{}, options, sessionData)
+}
+
+// FinishLogin issues the session straight away, user verification is
+// required so the passkey already proves possession and a pin or biometric
+func (s *webauthnService) FinishLogin(ctx context.Context, req dto.PasskeyLoginRequest) (dto.LoginResponse, error) {
+	var res dto.LoginResponse
+
+	if valErr := s.validator.Validate(req); valErr != nil {
+		return res, valErr
+	}
+
+	session, err := s.webauthnRepo.ConsumeSession(ctx, req.CeremonyID, entity.WebauthnCeremonyLogin, s.time.Now())
+	if err != nil {
+		return res, err
+	}
+
+	var (
+		user     entity.User
+		passkeys []entity.WebauthnCredential
+		findErr  error
+	)
+
+	findWebauthnUser := func(userHandle []byte) (webauthn.User, error) {
+		userID, err := uuid.FromBytes(userHandle)
+		if err != nil {
+			return webauthn.User{}, err
+		}
+
+		user, passkeys, findErr = s.findUser(ctx, userID)
+		if findErr != nil {
+			return webauthn.User{}, findErr
+		}
+
+		return toWebauthnUser(user, passkeys), nil
+	}
+
+	credential, err := s.webauthn.FinishLogin(session.SessionData, req.Credential, findWebauthnUser)
+	if err != nil {
+		if findErr != nil && !errors.Is(findErr, domain.ErrUserNotFound) {
+			return res, findErr
+		}
+
+		if errors.Is(err, webauthn.ErrCredentialCloned) {
+			log.Warn(log.LogInfo{
+				"user_id": user.ID,
+			}, "[WEBAUTHN SERVICE][FinishLogin] passkey sign count went backwards, it may have been cloned")
+		}
+
+		if errors.Is(err, webauthn.ErrVerificationFailed) || errors.Is(err, webauthn.ErrCredentialCloned) {
+			return res, domain.ErrPasskeyVerificationFailed
+		}
+
+		return res, err
+	}
+
+	for _, passkey := range passkeys {
+		if !bytes.Equal(passkey.CredentialID, credential.ID) {
+			continue
+		}
+
+		err := s.webauthnRepo.UpdateSignCount(
+			ctx,
+			passkey.ID,
+			int64(credential.SignCount),
+			credential.BackupState,
+			s.time.Now(),
+		)
+		if err != nil {
+			return res, err
+		}
+
+		token, err := s.sessionService.Issue(ctx, user, []string{jwt.AMRHardwareKey, jwt.AMRMultiFactor})
+		if err != nil {
+			return res, err
+		}
+
+		res.TokenResponse = &token
+		return res, nil
+	}
+
+	return res, domain.ErrPasskeyVerificationFailed
+}
+
+func (s *webauthnService) List(ctx context.Context, userID uuid.UUID) ([]dto.PasskeyResponse, error) {
+	passkeys, err := s.webauthnRepo.FindCredentialsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.PasskeyResponse, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		res = append(res, toPasskeyResponse(passkey))
+	}
+
+	return res, nil
+}
+
+func (s *webauthnService) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	return s.webauthnRepo.DeleteCredential(ctx, userID, id)
+}
+
+// notifyAdded is best effort, the passkey is already stored
+func (s *webauthnService) notifyAdded(ctx context.Context, user entity.User, passkey entity.WebauthnCredential) {
+	err := s.mailService.Queue(ctx, user.Email, user.Locale, mail.TemplatePasskeyAdded, mail.PasskeyAddedData{
+		Name:        user.Name,
+		PasskeyName: passkey.Name,
+		Link:        fmt.Sprintf("%s/settings/security", strings.TrimRight(env.AppEnv.FrontendURL, "/")),
+	})
+	if err != nil {
+		log.Warn(log.LogInfo{
+			"error":   err.Error(),
+			"user_id": user.ID,
+		}, "[WEBAUTHN SERVICE][notifyAdded] failed to queue passkey added mail")
+	}
+}
+
+func (s *webauthnService) findUser(
+	ctx context.Context,
+	userID uuid.UUID,
+) (entity.User, []entity.WebauthnCredential, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return user, nil, err
+	}
+
+	passkeys, err := s.webauthnRepo.FindCredentialsByUserID(ctx, userID)
+	if err != nil {
+		return user, nil, err
+	}
+
+	return user, passkeys, nil
+}
+
+// toWebauthnUser uses the raw user id as the user handle so the handle kept on
+// the authenticator doesn't reveal anything about the user
+func toWebauthnUser(user entity.User, passkeys []entity.WebauthnCredential) webauthn.User {
+	credentials := make([]webauthn.Credential, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		credentials = append(credentials, webauthn.Credential{
+			ID:              passkey.CredentialID,
+			PublicKey:       passkey.PublicKey,
+			AttestationType: passkey.AttestationType,
+			Transports:      strings.Fields(passkey.Transports),
+			AAGUID:          passkey.AAGUID,
+			SignCount:       uint32(passkey.SignCount),
+			BackupEligible:  passkey.BackupEligible,
+			BackupState:     passkey.BackupState,
+		})
+	}
+
+	return webauthn.User{
+		ID:          user.ID[:],
+		Name:        user.Email,
+		DisplayName: user.Name,
+		Credentials: credentials,
+	}
+}
+
+func (s *webauthnService) createSession(
+	ctx context.Context,
+	ceremony string,
+	userID uuid.NullUUID,
+	options []byte,
+	sessionData string,
+) (dto.PasskeyOptionsResponse, error) {
+	var res dto.PasskeyOptionsResponse
+
+	id, err := s.uuid.NewV7()
+	if err != nil {
+		return res, err
+	}
+
+	session := entity.WebauthnSession{
+		ID:          id,
+		UserID:      userID,
+		Ceremony:    ceremony,
+		SessionData: sessionData,
+		ExpiresAt:   s.time.Add(webauthn.CeremonyTimeout),
+	}
+
+	if err := s.webauthnRepo.CreateSession(ctx, &session); err != nil {
+		return res, err
+	}
+
+	res.CeremonyID = id
+	res.Options = options
+
+	return res, nil
+}
+
+func toPasskeyResponse(passkey entity.WebauthnCredential) dto.PasskeyResponse {
+	res := dto.PasskeyResponse{
+		ID:        passkey.ID,
+		Name:      passkey.Name,
+		CreatedAt: passkey.CreatedAt,
+	}
+
+	if passkey.LastUsedAt.Valid {
+		res.LastUsedAt = &passkey.LastUsedAt.Time
+	}
+
+	return res
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kelompok1-swe-academya/caper-be/database/migrations"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+type migrationLogger struct{}
+
+func (l migrationLogger) Printf(format string, v ...interface{}) {
+	log.Info(nil, "[DB][Migration] "+strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+func (l migrationLogger) Verbose() bool {
+	return true
+}
+
+// NewMigration builds a migrator over the embedded database/migrations files.
+// Closing the returned migrator also closes db.
+func NewMigration(db *sqlx.DB) (*migrate.Migrate, error) {
+	source, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[DB][NewMigration] failed to read embedded migrations")
+		return nil, err
+	}
+
+	driver, err := pgx.WithInstance(db.DB, &pgx.Config{})
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[DB][NewMigration] failed to create migration driver")
+		return nil, err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "pgx", driver)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[DB][NewMigration] failed to create migrator")
+		return nil, err
+	}
+
+	m.Log = migrationLogger{}
+
+	return m, nil
+}
+
+// RunMigrations applies every pending migration on a dedicated connection so
+// the application pool is left untouched. Concurrent replicas are serialized
+// by the advisory lock the driver takes.
+func RunMigrations() error {
+	m, err := NewMigration(NewPgsqlConn())
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[DB][RunMigrations] failed to run migrations")
+		return err
+	}
+
+	return nil
+}
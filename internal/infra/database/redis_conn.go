@@ -0,0 +1,29 @@
+package database
+
+import (
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+)
+
+var (
+	redisOnce sync.Once
+	redisConn *redis.Client
+)
+
+// GetRedisConn returns the client shared by every redis backed store, so the
+// app keeps a single pool. It is created on first use, deployments that keep
+// the cache and rate limits in memory never configure it.
+func GetRedisConn() *redis.Client {
+	redisOnce.Do(func() {
+		redisConn = redis.NewClient(&redis.Options{
+			Addr:     env.AppEnv.RedisAddr,
+			Password: env.AppEnv.RedisPass,
+			DB:       env.AppEnv.RedisDB,
+		})
+	})
+
+	return redisConn
+}
@@ -1,12 +1,16 @@
 package env
 
 import (
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
 	"github.com/spf13/viper"
 )
 
+const configFile = "config/.env"
+
 type Env struct {
 	AppEnv       string        `mapstructure:"APP_ENV"`
 	AppPort      string        `mapstructure:"APP_PORT"`
@@ -18,6 +22,66 @@ type Env struct {
 	DBName       string        `mapstructure:"DB_NAME"`
 	JwtSecretKey string        `mapstructure:"JWT_SECRET_KEY"`
 	JwtExpTime   time.Duration `mapstructure:"JWT_EXP_TIME"`
+
+	DBMigrateOnBoot        bool          `mapstructure:"DB_MIGRATE_ON_BOOT"`
+	ShutdownTimeout        time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	ShutdownReadinessDelay time.Duration `mapstructure:"SHUTDOWN_READINESS_DELAY"`
+
+	RefreshTokenExpTime time.Duration `mapstructure:"REFRESH_TOKEN_EXP_TIME"`
+	RevokedTokenStore   string        `mapstructure:"REVOKED_TOKEN_STORE"`
+
+	RoleCacheTTL time.Duration `mapstructure:"ROLE_CACHE_TTL"`
+
+	AccountDeletionGracePeriod time.Duration `mapstructure:"ACCOUNT_DELETION_GRACE_PERIOD"`
+
+	TotpIssuer        string `mapstructure:"TOTP_ISSUER"`
+	TotpEncryptionKey string `mapstructure:"TOTP_ENCRYPTION_KEY"`
+
+	WebauthnRPID          string   `mapstructure:"WEBAUTHN_RP_ID"`
+	WebauthnRPDisplayName string   `mapstructure:"WEBAUTHN_RP_DISPLAY_NAME"`
+	WebauthnRPOrigins     []string `mapstructure:"WEBAUTHN_RP_ORIGINS"`
+
+	RateLimitStore string   `mapstructure:"RATE_LIMIT_STORE"`
+	ProxyHeader    string   `mapstructure:"PROXY_HEADER"`
+	TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"`
+
+	CacheDriver     string `mapstructure:"CACHE_DRIVER"`
+	CacheMemorySize int    `mapstructure:"CACHE_MEMORY_SIZE"`
+	RedisAddr       string `mapstructure:"REDIS_ADDR"`
+	RedisPass       string `mapstructure:"REDIS_PASS"`
+	RedisDB         int    `mapstructure:"REDIS_DB"`
+
+	FrontendURL string `mapstructure:"FRONTEND_URL"`
+
+	GoogleClientID     string `mapstructure:"GOOGLE_CLIENT_ID"`
+	GoogleClientSecret string `mapstructure:"GOOGLE_CLIENT_SECRET"`
+	GoogleRedirectURL  string `mapstructure:"GOOGLE_REDIRECT_URL"`
+	GoogleIssuerURL    string `mapstructure:"GOOGLE_ISSUER_URL"`
+
+	MailDriver          string        `mapstructure:"MAIL_DRIVER"`
+	MailFilePath        string        `mapstructure:"MAIL_FILE_PATH"`
+	MailOutboxRetention time.Duration `mapstructure:"MAIL_OUTBOX_RETENTION"`
+	GomailHost          string        `mapstructure:"GOMAIL_HOST"`
+	GomailPort          int           `mapstructure:"GOMAIL_PORT"`
+	GomailUsername      string        `mapstructure:"GOMAIL_USERNAME"`
+	GomailPassword      string        `mapstructure:"GOMAIL_PASSWORD"`
+	GomailFrom          string        `mapstructure:"GOMAIL_FROM"`
+
+	StorageDriver     string `mapstructure:"STORAGE_DRIVER"`
+	StorageLocalPath  string `mapstructure:"STORAGE_LOCAL_PATH"`
+	StoragePublicURL  string `mapstructure:"STORAGE_PUBLIC_URL"`
+	StorageSigningKey string `mapstructure:"STORAGE_SIGNING_KEY"`
+	S3Endpoint        string `mapstructure:"S3_ENDPOINT"`
+	S3Region          string `mapstructure:"S3_REGION"`
+	S3Bucket          string `mapstructure:"S3_BUCKET"`
+	S3AccessKey       string `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey       string `mapstructure:"S3_SECRET_KEY"`
+	S3UseSSL          bool   `mapstructure:"S3_USE_SSL"`
+	UploadMaxSize     int64  `mapstructure:"UPLOAD_MAX_SIZE"`
+
+	PasswordResetExpTime     time.Duration `mapstructure:"PASSWORD_RESET_EXP_TIME"`
+	EmailVerificationExpTime time.Duration `mapstructure:"EMAIL_VERIFICATION_EXP_TIME"`
+	MagicLinkExpTime         time.Duration `mapstructure:"MAGIC_LINK_EXP_TIME"`
 }
 
 var AppEnv = getEnv()
@@ -25,7 +89,7 @@ var AppEnv = getEnv()
 func getEnv() *Env {
 	env := &Env{}
 
-	viper.SetConfigFile("./config/.env")
+	viper.SetConfigFile(findConfigFile())
 
 	if err := viper.ReadInConfig(); err != nil {
 		log.Fatal(log.LogInfo{
@@ -50,3 +114,26 @@ func getEnv() *Env {
 
 	return env
 }
+
+// findConfigFile looks for the config from the working directory upwards, so
+// go test finds it from inside a package directory as well
+func findConfigFile() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return configFile
+	}
+
+	for {
+		path := filepath.Join(dir, configFile)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return configFile
+		}
+
+		dir = parent
+	}
+}
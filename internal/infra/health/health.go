@@ -0,0 +1,173 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK           = "ok"
+	StatusDegraded     = "degraded"
+	StatusUnavailable  = "unavailable"
+	StatusShuttingDown = "shutting_down"
+)
+
+type Check func(ctx context.Context) error
+
+type CheckResult struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Ready tells whether the instance should receive traffic, a degraded
+// report still does
+func (r Report) Ready() bool {
+	return r.Status == StatusOK || r.Status == StatusDegraded
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+type Health interface {
+	Register(name string, timeout time.Duration, check Check)
+	RegisterDegraded(name string, timeout time.Duration, check Check)
+	MarkShuttingDown()
+	Liveness() Report
+	Readiness(ctx context.Context) Report
+}
+
+type registeredCheck struct {
+	name    string
+	timeout time.Duration
+	check   Check
+	// degraded checks are reported but never fail readiness
+	degraded bool
+}
+
+type health struct {
+	mu           sync.RWMutex
+	checks       []registeredCheck
+	shuttingDown atomic.Bool
+}
+
+func NewHealth() Health {
+	return &health{}
+}
+
+func (h *health) Register(name string, timeout time.Duration, check Check) {
+	h.register(registeredCheck{
+		name:    name,
+		timeout: timeout,
+		check:   check,
+	})
+}
+
+// RegisterDegraded adds a check whose failure only marks the report degraded.
+// It suits dependencies every replica shares, failing readiness on them would
+// take all replicas out of rotation at once and help nobody.
+func (h *health) RegisterDegraded(name string, timeout time.Duration, check Check) {
+	h.register(registeredCheck{
+		name:     name,
+		timeout:  timeout,
+		check:    check,
+		degraded: true,
+	})
+}
+
+func (h *health) register(check registeredCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checks = append(h.checks, check)
+}
+
+// MarkShuttingDown makes every following readiness report fail so load
+// balancers stop routing to this instance while it drains.
+func (h *health) MarkShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+func (h *health) Liveness() Report {
+	return Report{
+		Status: StatusOK,
+	}
+}
+
+// Readiness runs every registered check concurrently, each bounded by its
+// own timeout. It is unavailable when a check fails and degraded when only
+// checks registered with RegisterDegraded do.
+func (h *health) Readiness(ctx context.Context) Report {
+	if h.shuttingDown.Load() {
+		return Report{
+			Status: StatusShuttingDown,
+		}
+	}
+
+	h.mu.RLock()
+	checks := h.checks
+	h.mu.RUnlock()
+
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]CheckResult, len(checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, registered := range checks {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			result := run(ctx, registered)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Checks[registered.name] = result
+			switch result.Status {
+			case StatusUnavailable:
+				report.Status = StatusUnavailable
+			case StatusDegraded:
+				if report.Status == StatusOK {
+					report.Status = StatusDegraded
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return report
+}
+
+func run(ctx context.Context, registered registeredCheck) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, registered.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := registered.check(ctx)
+
+	result := CheckResult{
+		Status:    StatusOK,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+
+	if err != nil {
+		result.Status = StatusUnavailable
+		if registered.degraded {
+			result.Status = StatusDegraded
+		}
+
+		result.Error = err.Error()
+	}
+
+	return result
+}
@@ -1,33 +1,130 @@
 package server
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/bytedance/sonic"
 	"github.com/gofiber/fiber/v2"
 	"github.com/jmoiron/sqlx"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	emailChangeCtr "github.com/kelompok1-swe-academya/caper-be/internal/app/email_change/interface/rest"
+	emailChangeRepo "github.com/kelompok1-swe-academya/caper-be/internal/app/email_change/repository"
+	emailChangeSvc "github.com/kelompok1-swe-academya/caper-be/internal/app/email_change/service"
+	emailVerificationCtr "github.com/kelompok1-swe-academya/caper-be/internal/app/email_verification/interface/rest"
+	emailVerificationRepo "github.com/kelompok1-swe-academya/caper-be/internal/app/email_verification/repository"
+	emailVerificationSvc "github.com/kelompok1-swe-academya/caper-be/internal/app/email_verification/service"
+	loginThrottleCtr "github.com/kelompok1-swe-academya/caper-be/internal/app/login_throttle/interface/rest"
+	loginThrottleRepo "github.com/kelompok1-swe-academya/caper-be/internal/app/login_throttle/repository"
+	loginThrottleSvc "github.com/kelompok1-swe-academya/caper-be/internal/app/login_throttle/service"
+	magicLinkCtr "github.com/kelompok1-swe-academya/caper-be/internal/app/magic_link/interface/rest"
+	magicLinkRepo "github.com/kelompok1-swe-academya/caper-be/internal/app/magic_link/repository"
+	magicLinkSvc "github.com/kelompok1-swe-academya/caper-be/internal/app/magic_link/service"
+	mailRepo "github.com/kelompok1-swe-academya/caper-be/internal/app/mail/repository"
+	mailSvc "github.com/kelompok1-swe-academya/caper-be/internal/app/mail/service"
+	mfaCtr "github.com/kelompok1-swe-academya/caper-be/internal/app/mfa/interface/rest"
+	mfaRepo "github.com/kelompok1-swe-academya/caper-be/internal/app/mfa/repository"
+	mfaSvc "github.com/kelompok1-swe-academya/caper-be/internal/app/mfa/service"
+	oauthCtr "github.com/kelompok1-swe-academya/caper-be/internal/app/oauth/interface/rest"
+	oauthRepo "github.com/kelompok1-swe-academya/caper-be/internal/app/oauth/repository"
+	oauthSvc "github.com/kelompok1-swe-academya/caper-be/internal/app/oauth/service"
+	passwordResetCtr "github.com/kelompok1-swe-academya/caper-be/internal/app/password_reset/interface/rest"
+	passwordResetRepo "github.com/kelompok1-swe-academya/caper-be/internal/app/password_reset/repository"
+	passwordResetSvc "github.com/kelompok1-swe-academya/caper-be/internal/app/password_reset/service"
+	personalAccessTokenCtr "github.com/kelompok1-swe-academya/caper-be/internal/app/personal_access_token/interface/rest"
+	personalAccessTokenRepo "github.com/kelompok1-swe-academya/caper-be/internal/app/personal_access_token/repository"
+	personalAccessTokenSvc "github.com/kelompok1-swe-academya/caper-be/internal/app/personal_access_token/service"
+	roleCtr "github.com/kelompok1-swe-academya/caper-be/internal/app/role/interface/rest"
+	roleRepo "github.com/kelompok1-swe-academya/caper-be/internal/app/role/repository"
+	roleSvc "github.com/kelompok1-swe-academya/caper-be/internal/app/role/service"
+	sessionCtr "github.com/kelompok1-swe-academya/caper-be/internal/app/session/interface/rest"
+	sessionRepo "github.com/kelompok1-swe-academya/caper-be/internal/app/session/repository"
+	sessionSvc "github.com/kelompok1-swe-academya/caper-be/internal/app/session/service"
+	uploadCtr "github.com/kelompok1-swe-academya/caper-be/internal/app/upload/interface/rest"
+	uploadSvc "github.com/kelompok1-swe-academya/caper-be/internal/app/upload/service"
+	userCtr "github.com/kelompok1-swe-academya/caper-be/internal/app/user/interface/rest"
+	userRepo "github.com/kelompok1-swe-academya/caper-be/internal/app/user/repository"
+	userSvc "github.com/kelompok1-swe-academya/caper-be/internal/app/user/service"
+	webauthnCtr "github.com/kelompok1-swe-academya/caper-be/internal/app/webauthn/interface/rest"
+	webauthnRepo "github.com/kelompok1-swe-academya/caper-be/internal/app/webauthn/repository"
+	webauthnSvc "github.com/kelompok1-swe-academya/caper-be/internal/app/webauthn/service"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/database"
 	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/health"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/worker"
 	"github.com/kelompok1-swe-academya/caper-be/internal/middlewares"
 	"github.com/kelompok1-swe-academya/caper-be/pkg/bcrypt"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/cache"
 	errorhandler "github.com/kelompok1-swe-academya/caper-be/pkg/helpers/http/error_handler"
 	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers/http/response"
+	imageprocessor "github.com/kelompok1-swe-academya/caper-be/pkg/image_processor"
 	"github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
 	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/mail"
+	ratelimiter "github.com/kelompok1-swe-academya/caper-be/pkg/rate_limiter"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/storage"
 	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/token"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/totp"
 	"github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
 	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/webauthn"
+)
+
+const (
+	revocationPurgeInterval = time.Hour
+	mailOutboxInterval      = 5 * time.Second
+)
+
+// apiRateLimit is the baseline for every v1 route, routes open to brute
+// force add a stricter policy of their own
+var apiRateLimit = middlewares.RateLimitPolicy{
+	Name: "api",
+	Policy: ratelimiter.Policy{
+		Algorithm: ratelimiter.TokenBucket,
+		Limit:     300,
+		Window:    time.Minute,
+	},
+	Key: middlewares.KeyByIP,
+}
+
+const (
+	livenessPath       = "/healthz"
+	readinessPath      = "/readyz"
+	healthCheckTimeout = 2 * time.Second
 )
 
 type HttpServer interface {
 	Start(part string)
+	Shutdown(timeout time.Duration) error
 	MountMiddlewares()
 	MountRoutes(db *sqlx.DB)
 	GetApp() *fiber.App
 }
 
 type httpServer struct {
-	app *fiber.App
+	app    *fiber.App
+	worker worker.Manager
+	health health.Health
+}
+
+// bodyLimit leaves room for the multipart envelope around the largest upload
+func bodyLimit() int {
+	const multipartOverhead = 1 << 20
+
+	return max(fiber.DefaultBodyLimit, int(env.AppEnv.UploadMaxSize)+multipartOverhead)
 }
 
 func NewHttpServer() HttpServer {
+	// without a trusted proxy list any client could set the header and pick
+	// its own ip, which defeats ip keyed rate limits
+	if env.AppEnv.ProxyHeader != "" && len(env.AppEnv.TrustedProxies) == 0 {
+		log.Fatal(log.LogInfo{
+			"proxy_header": env.AppEnv.ProxyHeader,
+		}, "[SERVER][NewHttpServer] PROXY_HEADER requires TRUSTED_PROXIES")
+	}
+
 	config := fiber.Config{
 		CaseSensitive: true,
 		AppName:       "Hackathon Fiber Starter",
@@ -35,12 +132,21 @@ func NewHttpServer() HttpServer {
 		JSONEncoder:   sonic.Marshal,
 		JSONDecoder:   sonic.Unmarshal,
 		ErrorHandler:  errorhandler.ErrorHandler,
+		BodyLimit:     bodyLimit(),
+
+		// behind the compose nginx every request would otherwise share the
+		// proxy's ip, which breaks ip keyed rate limits
+		ProxyHeader:             env.AppEnv.ProxyHeader,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          env.AppEnv.TrustedProxies,
 	}
 
 	app := fiber.New(config)
 
 	return &httpServer{
-		app: app,
+		app:    app,
+		worker: worker.NewManager(),
+		health: health.NewHealth(),
 	}
 }
 
@@ -62,30 +168,105 @@ func (s *httpServer) Start(port string) {
 	}
 }
 
+// Shutdown fails readiness first and keeps serving for the readiness delay so
+// load balancers notice and stop routing here. It then stops accepting
+// connections, waits for in-flight requests and stops background workers.
+// The whole shutdown shares one deadline, timeout from now, so the delay and
+// the drain are taken out of what the workers get. Workers are stopped even
+// when the drain fails, the database is closed right after.
+func (s *httpServer) Shutdown(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	s.health.MarkShuttingDown()
+
+	if delay := min(env.AppEnv.ShutdownReadinessDelay, timeout); delay > 0 {
+		log.Info(log.LogInfo{
+			"delay": delay.String(),
+		}, "[SERVER][Shutdown] waiting for load balancers to see the instance as not ready")
+		time.Sleep(delay)
+	}
+
+	// a deadline that already passed closes the remaining connections right
+	// away, a zero timeout would wait for them forever instead
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	drainErr := s.app.ShutdownWithContext(ctx)
+	if drainErr != nil {
+		log.Error(log.LogInfo{
+			"error": drainErr.Error(),
+		}, "[SERVER][Shutdown] failed to drain in-flight requests")
+	}
+
+	workerErr := s.worker.Stop(time.Until(deadline))
+	if workerErr != nil {
+		log.Error(log.LogInfo{
+			"error": workerErr.Error(),
+		}, "[SERVER][Shutdown] failed to stop background workers")
+	}
+
+	return errors.Join(drainErr, workerErr)
+}
+
 func (s *httpServer) MountMiddlewares() {
 	s.app.Use(middlewares.LoggerConfig())
 	s.app.Use(middlewares.Helmet())
 	s.app.Use(middlewares.Compress())
 	s.app.Use(middlewares.Cors())
 	if env.AppEnv.AppEnv != "development" {
-		s.app.Use(middlewares.ApiKey())
+		// browsers follow the oauth redirects and can't attach the api key
+		s.app.Use(middlewares.ApiKey(
+			livenessPath,
+			readinessPath,
+			oauthCtr.GoogleLoginPath,
+			oauthCtr.GoogleCallbackPath,
+			uploadCtr.FilesPath,
+		))
 	}
 	s.app.Use(middlewares.RecoverConfig())
 }
 
 func (s *httpServer) MountRoutes(db *sqlx.DB) {
-	_ = bcrypt.Bcrypt
-	_ = timePkg.Time
-	_ = uuid.UUID
-	_ = validator.Validator
+	bcrypt := bcrypt.Bcrypt
+	time := timePkg.Time
+	token := token.Token
+	uuid := uuid.UUID
+	validator := validator.Validator
 	jwt := jwt.Jwt
+	totp := totp.Totp
+	webauthn := webauthn.Webauthn
+	mailTemplate := mail.Template
+	mail := mail.Mail
+	storage := storage.Storage
+	imageProcessor := imageprocessor.ImageProcessor
+	rateLimiter := ratelimiter.RateLimiter
 
-	_ = middlewares.NewMiddleware(jwt)
+	s.health.Register("database", healthCheckTimeout, db.PingContext)
+	if env.AppEnv.CacheDriver == "redis" || env.AppEnv.RateLimitStore == "redis" {
+		// every replica shares redis, so an outage only degrades readiness
+		// instead of taking all of them out of rotation at once
+		s.health.RegisterDegraded("redis", healthCheckTimeout, func(ctx context.Context) error {
+			return database.GetRedisConn().Ping(ctx).Err()
+		})
+	}
 
 	s.app.Get("/", func(c *fiber.Ctx) error {
 		return response.SendResponse(c, fiber.StatusOK, "caper be is running")
 	})
 
+	s.app.Get(livenessPath, func(c *fiber.Ctx) error {
+		return response.SendResponse(c, fiber.StatusOK, s.health.Liveness())
+	})
+
+	s.app.Get(readinessPath, func(c *fiber.Ctx) error {
+		report := s.health.Readiness(c.Context())
+		if !report.Ready() {
+			return response.SendResponse(c, fiber.StatusServiceUnavailable, report)
+		}
+
+		return response.SendResponse(c, fiber.StatusOK, report)
+	})
+
 	api := s.app.Group("/api")
 	v1 := api.Group("/v1")
 
@@ -93,6 +274,193 @@ func (s *httpServer) MountRoutes(db *sqlx.DB) {
 		return response.SendResponse(c, fiber.StatusOK, "caper be is running")
 	})
 
+	userRepository := userRepo.NewUserRepository(db)
+	userExportRepository := userRepo.NewUserExportRepository(db)
+	refreshTokenRepository := sessionRepo.NewRefreshTokenRepository(db)
+	roleRepository := roleRepo.NewRoleRepository(db)
+	passwordResetRepository := passwordResetRepo.NewPasswordResetRepository(db)
+	emailVerificationRepository := emailVerificationRepo.NewEmailVerificationRepository(db)
+	emailChangeRepository := emailChangeRepo.NewEmailChangeRepository(db)
+	oauthRepository := oauthRepo.NewOAuthRepository(db)
+	mailOutboxRepository := mailRepo.NewMailOutboxRepository(db)
+	loginThrottleRepository := loginThrottleRepo.NewLoginThrottleRepository(db)
+	mfaRepository := mfaRepo.NewMFARepository(db)
+	magicLinkRepository := magicLinkRepo.NewMagicLinkRepository(db)
+	webauthnRepository := webauthnRepo.NewWebauthnRepository(db)
+	personalAccessTokenRepository := personalAccessTokenRepo.NewPersonalAccessTokenRepository(db)
+
+	var revokedTokenRepository contracts.RevokedTokenRepository
+	if env.AppEnv.RevokedTokenStore == "memory" {
+		revokedTokenRepository = sessionRepo.NewRevokedTokenMemoryRepository(time)
+	} else {
+		revokedTokenRepository = sessionRepo.NewRevokedTokenRepository(db)
+	}
+
+	roleService := roleSvc.NewRoleService(roleRepository, userRepository, validator, cache.Cache)
+
+	s.worker.Every("purge expired token revocations", revocationPurgeInterval, revokedTokenRepository.DeleteExpired)
+	s.worker.Every("purge expired oauth states", revocationPurgeInterval, oauthRepository.DeleteExpiredStates)
+	s.worker.Every("purge expired mfa challenges", revocationPurgeInterval, mfaRepository.DeleteExpiredChallenges)
+	s.worker.Every("purge expired magic links", revocationPurgeInterval, magicLinkRepository.DeleteExpired)
+	s.worker.Every("purge expired webauthn sessions", revocationPurgeInterval, webauthnRepository.DeleteExpiredSessions)
+	s.worker.Every(
+		"purge expired personal access tokens",
+		revocationPurgeInterval,
+		personalAccessTokenRepository.DeleteExpired,
+	)
+
+	mailService := mailSvc.NewMailService(mailOutboxRepository, mail, mailTemplate, uuid, time)
+	s.worker.Every("deliver mail outbox", mailOutboxInterval, mailService.ProcessOutbox)
+	s.worker.Every("purge finished mails", revocationPurgeInterval, mailService.PurgeFinished)
+
+	loginThrottleService := loginThrottleSvc.NewLoginThrottleService(
+		loginThrottleRepository,
+		userRepository,
+		mailService,
+		time,
+	)
+	s.worker.Every("purge expired login throttles", revocationPurgeInterval, loginThrottleService.DeleteExpired)
+
+	s.worker.Every("purge expired rate limits", revocationPurgeInterval, rateLimiter.DeleteExpired)
+
+	personalAccessTokenService := personalAccessTokenSvc.NewPersonalAccessTokenService(
+		personalAccessTokenRepository,
+		userRepository,
+		validator,
+		uuid,
+		token,
+		time,
+	)
+
+	middleware := middlewares.NewMiddleware(
+		jwt,
+		revokedTokenRepository,
+		personalAccessTokenService,
+		roleService,
+		userRepository,
+		rateLimiter,
+	)
+
+	sessionService := sessionSvc.NewSessionService(
+		refreshTokenRepository,
+		revokedTokenRepository,
+		userRepository,
+		validator,
+		uuid,
+		jwt,
+		token,
+		time,
+	)
+	mfaService := mfaSvc.NewMFAService(
+		mfaRepository,
+		userRepository,
+		sessionService,
+		loginThrottleService,
+		validator,
+		uuid,
+		bcrypt,
+		token,
+		totp,
+		time,
+	)
+	emailVerificationService := emailVerificationSvc.NewEmailVerificationService(
+		emailVerificationRepository,
+		userRepository,
+		validator,
+		uuid,
+		token,
+		mailService,
+		time,
+	)
+	emailChangeService := emailChangeSvc.NewEmailChangeService(
+		emailChangeRepository,
+		userRepository,
+		validator,
+		uuid,
+		token,
+		mailService,
+		time,
+	)
+	userService := userSvc.NewUserService(
+		userRepository,
+		userExportRepository,
+		roleRepository,
+		sessionService,
+		mfaService,
+		loginThrottleService,
+		emailVerificationService,
+		emailChangeService,
+		roleService,
+		validator,
+		uuid,
+		bcrypt,
+		storage,
+		imageProcessor,
+		time,
+	)
+	s.worker.Every("purge deleted users", revocationPurgeInterval, userService.PurgeDeletedUsers)
+	oauthService := oauthSvc.NewOAuthService(
+		oauthRepository,
+		userRepository,
+		sessionService,
+		mfaService,
+		validator,
+		uuid,
+		bcrypt,
+		token,
+		time,
+	)
+	magicLinkService := magicLinkSvc.NewMagicLinkService(
+		magicLinkRepository,
+		userRepository,
+		mfaService,
+		loginThrottleService,
+		validator,
+		uuid,
+		token,
+		mailService,
+		time,
+	)
+	webauthnService := webauthnSvc.NewWebauthnService(
+		webauthnRepository,
+		userRepository,
+		sessionService,
+		mfaService,
+		mailService,
+		validator,
+		uuid,
+		webauthn,
+		time,
+	)
+	uploadService := uploadSvc.NewUploadService(storage, uuid, time)
+	passwordResetService := passwordResetSvc.NewPasswordResetService(
+		passwordResetRepository,
+		userRepository,
+		sessionService,
+		validator,
+		uuid,
+		bcrypt,
+		token,
+		mailService,
+		time,
+	)
+
+	v1.Use(middleware.RateLimit(apiRateLimit))
+
+	userCtr.InitUserController(v1, userService, middleware)
+	sessionCtr.InitSessionController(v1, sessionService, middleware)
+	roleCtr.InitRoleController(v1, roleService, middleware)
+	passwordResetCtr.InitPasswordResetController(v1, passwordResetService, middleware)
+	emailVerificationCtr.InitEmailVerificationController(v1, emailVerificationService, middleware)
+	emailChangeCtr.InitEmailChangeController(v1, emailChangeService, middleware)
+	oauthCtr.InitOAuthController(v1, oauthService)
+	uploadCtr.InitUploadController(v1, uploadService, middleware)
+	loginThrottleCtr.InitLoginThrottleController(v1, loginThrottleService, middleware)
+	mfaCtr.InitMFAController(v1, mfaService, middleware)
+	magicLinkCtr.InitMagicLinkController(v1, magicLinkService, middleware)
+	webauthnCtr.InitWebauthnController(v1, webauthnService, middleware)
+	personalAccessTokenCtr.InitPersonalAccessTokenController(v1, personalAccessTokenService, middleware)
+
 	s.app.Use(func(c *fiber.Ctx) error {
 		return c.SendFile("./web/not-found.html")
 	})
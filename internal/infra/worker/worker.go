@@ -0,0 +1,93 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+type Job func(ctx context.Context) error
+
+type Manager interface {
+	Go(name string, job Job)
+	Every(name string, interval time.Duration, job Job)
+	Stop(timeout time.Duration) error
+}
+
+type manager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var ErrStopTimeout = errors.New("timed out waiting for workers to stop")
+
+func NewManager() Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &manager{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Go runs a long lived job until it returns or the manager is stopped. The
+// job must return once its context is cancelled.
+func (m *manager) Go(name string, job Job) {
+	m.wg.Add(1)
+
+	go func() {
+		defer m.wg.Done()
+
+		if err := job(m.ctx); err != nil && !errors.Is(err, context.Canceled) {
+			log.Error(log.LogInfo{
+				"error":  err.Error(),
+				"worker": name,
+			}, "[WORKER][Go] worker stopped with error")
+		}
+	}()
+}
+
+// Every runs job once per interval until the manager is stopped. A failed
+// run is logged and retried on the next tick.
+func (m *manager) Every(name string, interval time.Duration, job Job) {
+	m.Go(name, func(ctx context.Context) error {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				if err := job(ctx); err != nil {
+					log.Error(log.LogInfo{
+						"error":  err.Error(),
+						"worker": name,
+					}, "[WORKER][Every] scheduled job failed")
+				}
+			}
+		}
+	})
+}
+
+// Stop cancels every job and waits for them to return, giving up after timeout
+func (m *manager) Stop(timeout time.Duration) error {
+	m.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return ErrStopTimeout
+	}
+}
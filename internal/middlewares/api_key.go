@@ -8,8 +8,16 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func ApiKey() fiber.Handler {
+// ApiKey skips the given paths, e.g. health probes that can't send headers.
+// A path ending with a slash skips every path under it.
+func ApiKey(skipPaths ...string) fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
+		for _, path := range skipPaths {
+			if ctx.Path() == path || (strings.HasSuffix(path, "/") && strings.HasPrefix(ctx.Path(), path)) {
+				return ctx.Next()
+			}
+		}
+
 		apiKey := ctx.Get("x-api-key")
 		if apiKey == "" {
 			return domain.ErrNoAPIKey
@@ -6,10 +6,13 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
 	"github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
 )
 
-func (m *Middleware) RequireAuth() fiber.Handler {
+// RequireAuth accepts a jwt or a personal access token. Personal access tokens
+// must hold every given scope, routes that list none are closed to them.
+func (m *Middleware) RequireAuth(scopes ...string) fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
 		header := ctx.Get("Authorization")
 		if header == "" {
@@ -17,33 +20,29 @@ func (m *Middleware) RequireAuth() fiber.Handler {
 		}
 
 		headerSlice := strings.Split(header, " ")
-		if len(headerSlice) != 2 && headerSlice[0] != "Bearer" {
+		if len(headerSlice) != 2 || headerSlice[0] != "Bearer" {
 			return domain.ErrInvalidBearerToken
 		}
 
 		token := headerSlice[1]
-		var claims jwt.Claims
-		err := m.jwt.Decode(token, &claims)
-		if err != nil {
-			return domain.ErrInvalidBearerToken
-		}
 
-		notBefore, err := claims.GetNotBefore()
-		if err != nil {
-			return domain.ErrInvalidBearerToken
-		}
+		var (
+			claims jwt.Claims
+			err    error
+		)
 
-		if notBefore.After(time.Now()) {
-			return domain.ErrBearerTokenNotActive
+		if strings.HasPrefix(token, entity.PersonalAccessTokenPrefix) {
+			claims, err = m.personalAccessTokenService.Authenticate(ctx.Context(), token, ctx.IP())
+		} else {
+			claims, err = m.authenticateJwt(ctx, token)
 		}
 
-		expirationTime, err := claims.GetExpirationTime()
 		if err != nil {
-			return domain.ErrInvalidBearerToken
+			return err
 		}
 
-		if expirationTime.Before(time.Now()) {
-			return domain.ErrExpiredBearerToken
+		if !hasScopes(claims, scopes) {
+			return domain.ErrInsufficientTokenScope
 		}
 
 		ctx.Locals("claims", claims)
@@ -51,3 +50,54 @@ func (m *Middleware) RequireAuth() fiber.Handler {
 		return ctx.Next()
 	}
 }
+
+func (m *Middleware) authenticateJwt(ctx *fiber.Ctx, token string) (jwt.Claims, error) {
+	var claims jwt.Claims
+	err := m.jwt.Decode(token, &claims)
+	if err != nil {
+		return claims, domain.ErrInvalidBearerToken
+	}
+
+	notBefore, err := claims.GetNotBefore()
+	if err != nil {
+		return claims, domain.ErrInvalidBearerToken
+	}
+
+	if notBefore.After(time.Now()) {
+		return claims, domain.ErrBearerTokenNotActive
+	}
+
+	expirationTime, err := claims.GetExpirationTime()
+	if err != nil {
+		return claims, domain.ErrInvalidBearerToken
+	}
+
+	if expirationTime.Before(time.Now()) {
+		return claims, domain.ErrExpiredBearerToken
+	}
+
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		return claims, domain.ErrInvalidBearerToken
+	}
+
+	revoked, err := m.revokedTokenRepo.IsRevoked(ctx.Context(), claims.ID, claims.UserID, issuedAt.Time)
+	if err != nil {
+		return claims, err
+	}
+
+	if revoked {
+		return claims, domain.ErrRevokedBearerToken
+	}
+
+	return claims, nil
+}
+
+func GetClaims(ctx *fiber.Ctx) (jwt.Claims, error) {
+	claims, ok := ctx.Locals("claims").(jwt.Claims)
+	if !ok {
+		return claims, domain.ErrNoBearerToken
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,35 @@
+package middlewares
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+)
+
+// RequireVerifiedEmail must be mounted after RequireAuth. The user is looked
+// up on every request so a verification takes effect without a new token.
+func (m *Middleware) RequireVerifiedEmail() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		claims, err := GetClaims(ctx)
+		if err != nil {
+			return err
+		}
+
+		user, err := m.userRepo.FindByID(ctx.Context(), claims.UserID)
+		if err != nil {
+			if errors.Is(err, domain.ErrUserNotFound) {
+				return domain.ErrInvalidBearerToken
+			}
+
+			return err
+		}
+
+		if !user.EmailVerifiedAt.Valid {
+			return domain.ErrEmailNotVerified
+		}
+
+		return ctx.Next()
+	}
+}
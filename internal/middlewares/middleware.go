@@ -1,15 +1,34 @@
 package middlewares
 
-import "github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
+import (
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
+	ratelimiter "github.com/kelompok1-swe-academya/caper-be/pkg/rate_limiter"
+)
 
 type Middleware struct {
-	jwt jwt.JwtInterface
+	jwt                        jwt.JwtInterface
+	revokedTokenRepo           contracts.RevokedTokenRepository
+	personalAccessTokenService contracts.PersonalAccessTokenService
+	roleService                contracts.RoleService
+	userRepo                   contracts.UserRepository
+	rateLimiter                ratelimiter.RateLimiterInterface
 }
 
 func NewMiddleware(
 	jwt jwt.JwtInterface,
+	revokedTokenRepo contracts.RevokedTokenRepository,
+	personalAccessTokenService contracts.PersonalAccessTokenService,
+	roleService contracts.RoleService,
+	userRepo contracts.UserRepository,
+	rateLimiter ratelimiter.RateLimiterInterface,
 ) *Middleware {
 	return &Middleware{
-		jwt: jwt,
+		jwt:                        jwt,
+		revokedTokenRepo:           revokedTokenRepo,
+		personalAccessTokenService: personalAccessTokenService,
+		roleService:                roleService,
+		userRepo:                   userRepo,
+		rateLimiter:                rateLimiter,
 	}
 }
@@ -0,0 +1,103 @@
+package middlewares
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+	ratelimiter "github.com/kelompok1-swe-academya/caper-be/pkg/rate_limiter"
+)
+
+// RateLimitKey identifies who a request is counted against
+type RateLimitKey func(ctx *fiber.Ctx) string
+
+func KeyByIP(ctx *fiber.Ctx) string {
+	return "ip:" + ctx.IP()
+}
+
+// KeyByAPIKey hashes the key so it is never written to the limiter store,
+// requests without one fall back to their ip
+func KeyByAPIKey(ctx *fiber.Ctx) string {
+	apiKey := ctx.Get("x-api-key")
+	if apiKey == "" {
+		return KeyByIP(ctx)
+	}
+
+	sum := sha256.Sum256([]byte(apiKey))
+
+	return "key:" + hex.EncodeToString(sum[:8])
+}
+
+// KeyByUser must run after RequireAuth, anonymous requests fall back to
+// their ip
+func KeyByUser(ctx *fiber.Ctx) string {
+	claims, err := GetClaims(ctx)
+	if err != nil {
+		return KeyByIP(ctx)
+	}
+
+	return "user:" + claims.UserID.String()
+}
+
+type RateLimitPolicy struct {
+	// Name separates the counters of policies sharing a key
+	Name string
+	ratelimiter.Policy
+	Key RateLimitKey
+	// FailClosed rejects requests while the store is failing instead of
+	// letting them through, set on routes guarding credentials so an outage
+	// cannot lift their brute force limit
+	FailClosed bool
+}
+
+// RateLimit rejects requests over the policy with ErrTooManyRequests and
+// reports the quota in the RateLimit-* headers. A failing store lets the
+// request through rather than taking the route down with it, unless the
+// policy fails closed.
+func (m *Middleware) RateLimit(policy RateLimitPolicy) fiber.Handler {
+	key := policy.Key
+	if key == nil {
+		key = KeyByIP
+	}
+
+	rateLimitPolicy := strconv.Itoa(policy.Limit) + ";w=" + strconv.Itoa(seconds(policy.Window))
+
+	return func(ctx *fiber.Ctx) error {
+		res, err := m.rateLimiter.Allow(ctx.Context(), policy.Name+":"+key(ctx), policy.Policy)
+		if err != nil {
+			log.Warn(log.LogInfo{
+				"error":  err.Error(),
+				"policy": policy.Name,
+			}, "[MIDDLEWARE][RateLimit] failed to check rate limit")
+
+			if policy.FailClosed {
+				return domain.ErrRateLimitUnavailable
+			}
+
+			return ctx.Next()
+		}
+
+		ctx.Set("RateLimit-Policy", rateLimitPolicy)
+		ctx.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+		ctx.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		ctx.Set("RateLimit-Reset", strconv.Itoa(seconds(res.ResetAfter)))
+
+		if !res.Allowed {
+			ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds(res.RetryAfter)))
+			return domain.ErrTooManyRequests
+		}
+
+		return ctx.Next()
+	}
+}
+
+// seconds rounds up so clients never retry before the limit has reset
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
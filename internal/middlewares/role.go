@@ -0,0 +1,51 @@
+package middlewares
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+)
+
+// RequireRoles must be mounted after RequireAuth. It lets the request through
+// when the caller's role is, or inherits from, any of the given roles.
+func (m *Middleware) RequireRoles(roles ...string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		claims, err := GetClaims(ctx)
+		if err != nil {
+			return err
+		}
+
+		allowed, err := m.roleService.HasAnyRole(ctx.Context(), claims.RoleName, roles...)
+		if err != nil {
+			return err
+		}
+
+		if !allowed {
+			return domain.ErrRoleCantAccessResource
+		}
+
+		return ctx.Next()
+	}
+}
+
+// RequirePermission must be mounted after RequireAuth. It lets the request
+// through only when the caller's role grants every given permission.
+func (m *Middleware) RequirePermission(permissions ...string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		claims, err := GetClaims(ctx)
+		if err != nil {
+			return err
+		}
+
+		allowed, err := m.roleService.HasAllPermissions(ctx.Context(), claims.RoleName, permissions...)
+		if err != nil {
+			return err
+		}
+
+		if !allowed {
+			return domain.ErrRoleCantAccessResource
+		}
+
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,29 @@
+package middlewares
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
+)
+
+// hasScopes only restricts personal access tokens, a jwt is the user signed in
+// and may do everything their role allows
+func hasScopes(claims jwt.Claims, scopes []string) bool {
+	if claims.PersonalAccessTokenID == uuid.Nil {
+		return true
+	}
+
+	if len(scopes) == 0 {
+		return false
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(claims.Scopes, scope) {
+			return false
+		}
+	}
+
+	return true
+}
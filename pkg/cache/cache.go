@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/database"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+const defaultMemorySize = 10000
+
+var ErrCacheMiss = errors.New("cache miss")
+
+// Loader produces the value for a key that is not cached yet
+type Loader func(ctx context.Context) ([]byte, error)
+
+type CacheInterface interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(
+		ctx context.Context,
+		key string,
+		value []byte,
+		ttl time.Duration,
+		tags ...string,
+	) error
+	Delete(ctx context.Context, keys ...string) error
+	GetOrLoad(
+		ctx context.Context,
+		key string,
+		ttl time.Duration,
+		loader Loader,
+		tags ...string,
+	) ([]byte, error)
+	InvalidateTags(ctx context.Context, tags ...string) error
+	Ping(ctx context.Context) error
+}
+
+var Cache = getCache()
+
+// getCache picks the backend from CACHE_DRIVER: redis or memory (default)
+func getCache() CacheInterface {
+	if env.AppEnv.CacheDriver != "redis" {
+		size := env.AppEnv.CacheMemorySize
+		if size <= 0 {
+			size = defaultMemorySize
+		}
+
+		return NewMemoryCache(size)
+	}
+
+	return NewRedisCache(database.GetRedisConn())
+}
+
+// getOrLoad is shared by every backend. Concurrent misses on the same key
+// are collapsed into a single loader call, and a failing cache only costs a
+// trip to the loader instead of failing the request.
+func getOrLoad(
+	ctx context.Context,
+	c CacheInterface,
+	group *singleflight.Group,
+	key string,
+	ttl time.Duration,
+	loader Loader,
+	tags []string,
+) ([]byte, error) {
+	value, err := c.Get(ctx, key)
+	if err == nil {
+		return value, nil
+	}
+
+	if !errors.Is(err, ErrCacheMiss) {
+		log.Warn(log.LogInfo{
+			"error": err.Error(),
+			"key":   key,
+		}, "[CACHE][GetOrLoad] failed to read from cache")
+	}
+
+	result, err, _ := group.Do(key, func() (interface{}, error) {
+		value, err := loader(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.Set(ctx, key, value, ttl, tags...); err != nil {
+			log.Warn(log.LogInfo{
+				"error": err.Error(),
+				"key":   key,
+			}, "[CACHE][GetOrLoad] failed to write to cache")
+		}
+
+		return value, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]byte), nil
+}
@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+	tags      []string
+}
+
+// MemoryCache is a size bounded LRU kept in process, meant for tests and
+// development where running redis is not worth it
+type MemoryCache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	items map[string]*list.Element
+	tags  map[string]map[string]struct{}
+	group singleflight.Group
+}
+
+func NewMemoryCache(size int) *MemoryCache {
+	return &MemoryCache{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+		tags:  make(map[string]map[string]struct{}),
+	}
+}
+
+func (c *MemoryCache) Get(_ context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+
+	entry := element.Value.(*memoryEntry)
+	if !entry.expiresAt.IsZero() && !time.Now().Before(entry.expiresAt) {
+		c.remove(element)
+		return nil, ErrCacheMiss
+	}
+
+	c.order.MoveToFront(element)
+
+	return cloneBytes(entry.value), nil
+}
+
+// Set stores value until ttl elapses, a ttl of zero or less never expires
+func (c *MemoryCache) Set(
+	_ context.Context,
+	key string,
+	value []byte,
+	ttl time.Duration,
+	tags ...string,
+) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.remove(element)
+	}
+
+	entry := &memoryEntry{
+		key:   key,
+		value: cloneBytes(value),
+		tags:  tags,
+	}
+
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	c.items[key] = c.order.PushFront(entry)
+	for _, tag := range tags {
+		if c.tags[tag] == nil {
+			c.tags[tag] = make(map[string]struct{})
+		}
+
+		c.tags[tag][key] = struct{}{}
+	}
+
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+
+	return nil
+}
+
+func (c *MemoryCache) Delete(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := c.items[key]; ok {
+			c.remove(element)
+		}
+	}
+
+	return nil
+}
+
+func (c *MemoryCache) GetOrLoad(
+	ctx context.Context,
+	key string,
+	ttl time.Duration,
+	loader Loader,
+	tags ...string,
+) ([]byte, error) {
+	return getOrLoad(ctx, c, &c.group, key, ttl, loader, tags)
+}
+
+func (c *MemoryCache) InvalidateTags(_ context.Context, tags ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range c.tags[tag] {
+			if element, ok := c.items[key]; ok {
+				c.remove(element)
+			}
+		}
+
+		delete(c.tags, tag)
+	}
+
+	return nil
+}
+
+func (c *MemoryCache) Ping(_ context.Context) error {
+	return nil
+}
+
+// remove must be called with mu held
+func (c *MemoryCache) remove(element *list.Element) {
+	entry := element.Value.(*memoryEntry)
+
+	c.order.Remove(element)
+	delete(c.items, entry.key)
+
+	for _, tag := range entry.tags {
+		delete(c.tags[tag], entry.key)
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+}
+
+// cloneBytes keeps callers from mutating what is stored
+func cloneBytes(value []byte) []byte {
+	return append([]byte(nil), value...)
+}
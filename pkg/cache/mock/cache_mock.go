@@ -0,0 +1,149 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/cache/cache.go
+//
+// Generated by this command:
+//
+//	mockgen -source=pkg/cache/cache.go -destination=pkg/cache/mock/cache_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	cache "github.com/kelompok1-swe-academya/caper-be/pkg/cache"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCacheInterface is a mock of CacheInterface interface.
+type MockCacheInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCacheInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockCacheInterfaceMockRecorder is the mock recorder for MockCacheInterface.
+type MockCacheInterfaceMockRecorder struct {
+	mock *MockCacheInterface
+}
+
+// NewMockCacheInterface creates a new mock instance.
+func NewMockCacheInterface(ctrl *gomock.Controller) *MockCacheInterface {
+	mock := &MockCacheInterface{ctrl: ctrl}
+	mock.recorder = &MockCacheInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCacheInterface) EXPECT() *MockCacheInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockCacheInterface) Delete(ctx context.Context, keys ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Delete", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCacheInterfaceMockRecorder) Delete(ctx any, keys ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCacheInterface)(nil).Delete), varargs...)
+}
+
+// Get mocks base method.
+func (m *MockCacheInterface) Get(ctx context.Context, key string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCacheInterfaceMockRecorder) Get(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCacheInterface)(nil).Get), ctx, key)
+}
+
+// GetOrLoad mocks base method.
+func (m *MockCacheInterface) GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader cache.Loader, tags ...string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key, ttl, loader}
+	for _, a := range tags {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetOrLoad", varargs...)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrLoad indicates an expected call of GetOrLoad.
+func (mr *MockCacheInterfaceMockRecorder) GetOrLoad(ctx, key, ttl, loader any, tags ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key, ttl, loader}, tags...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrLoad", reflect.TypeOf((*MockCacheInterface)(nil).GetOrLoad), varargs...)
+}
+
+// InvalidateTags mocks base method.
+func (m *MockCacheInterface) InvalidateTags(ctx context.Context, tags ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range tags {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "InvalidateTags", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateTags indicates an expected call of InvalidateTags.
+func (mr *MockCacheInterfaceMockRecorder) InvalidateTags(ctx any, tags ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, tags...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateTags", reflect.TypeOf((*MockCacheInterface)(nil).InvalidateTags), varargs...)
+}
+
+// Ping mocks base method.
+func (m *MockCacheInterface) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockCacheInterfaceMockRecorder) Ping(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockCacheInterface)(nil).Ping), ctx)
+}
+
+// Set mocks base method.
+func (m *MockCacheInterface) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key, value, ttl}
+	for _, a := range tags {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Set", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockCacheInterfaceMockRecorder) Set(ctx, key, value, ttl any, tags ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key, value, ttl}, tags...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockCacheInterface)(nil).Set), varargs...)
+}
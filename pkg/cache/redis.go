@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	keyPrefix = "caper:cache:"
+	tagPrefix = "caper:tag:"
+)
+
+// invalidateTagScript deletes every key recorded under a tag together with
+// the tag itself in one step, so a key tagged while invalidating is not lost.
+// Deletes are batched because unpack is bounded by the lua stack size.
+var invalidateTagScript = redis.NewScript(`
+	local keys = redis.call('SMEMBERS', KEYS[1])
+	for i = 1, #keys, 500 do
+		redis.call('DEL', unpack(keys, i, math.min(i + 499, #keys)))
+	end
+	return redis.call('DEL', KEYS[1])
+`)
+
+type RedisCache struct {
+	client *redis.Client
+	group  singleflight.Group
+}
+
+func NewRedisCache(client *redis.Client) *RedisCache {
+	return &RedisCache{
+		client: client,
+	}
+}
+
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.client.Get(ctx, keyPrefix+key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrCacheMiss
+		}
+
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// Set stores value until ttl elapses, a ttl of zero or less never expires.
+// A tag set lives as long as its longest lived key.
+func (c *RedisCache) Set(
+	ctx context.Context,
+	key string,
+	value []byte,
+	ttl time.Duration,
+	tags ...string,
+) error {
+	if ttl < 0 {
+		ttl = 0
+	}
+
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, keyPrefix+key, value, ttl)
+
+		for _, tag := range tags {
+			pipe.SAdd(ctx, tagPrefix+tag, keyPrefix+key)
+
+			if ttl > 0 {
+				pipe.ExpireNX(ctx, tagPrefix+tag, ttl)
+				pipe.ExpireGT(ctx, tagPrefix+tag, ttl)
+			} else {
+				pipe.Persist(ctx, tagPrefix+tag)
+			}
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+func (c *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	prefixed := make([]string, 0, len(keys))
+	for _, key := range keys {
+		prefixed = append(prefixed, keyPrefix+key)
+	}
+
+	return c.client.Del(ctx, prefixed...).Err()
+}
+
+func (c *RedisCache) GetOrLoad(
+	ctx context.Context,
+	key string,
+	ttl time.Duration,
+	loader Loader,
+	tags ...string,
+) ([]byte, error) {
+	return getOrLoad(ctx, c, &c.group, key, ttl, loader, tags)
+}
+
+func (c *RedisCache) InvalidateTags(ctx context.Context, tags ...string) error {
+	for _, tag := range tags {
+		err := invalidateTagScript.Run(ctx, c.client, []string{tagPrefix + tag}).Err()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *RedisCache) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
@@ -5,15 +5,17 @@ import (
 )
 
 type Flag struct {
+	Entity string
 }
 
 var FlagVars = getFlags()
 
 func getFlags() *Flag {
+	flags := &Flag{}
 
-	flag.Parse()
+	flag.StringVar(&flags.Entity, "entity", "all", "comma separated entities to seed, or all")
 
-	flag := &Flag{}
+	flag.Parse()
 
-	return flag
+	return flags
 }
@@ -2,13 +2,19 @@ package helpers
 
 import (
 	"bufio"
+	"bytes"
 	"database/sql/driver"
+	"errors"
 	"fmt"
+	"io"
 	"math/rand"
+	"net/http"
 	"os"
 	"reflect"
 	"strings"
 
+	"github.com/jackc/pgx/v5/pgconn"
+
 	"github.com/kelompok1-swe-academya/caper-be/domain"
 	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
 )
@@ -66,6 +72,31 @@ func CheckRowsAffected(rows int64) error {
 	return nil
 }
 
+// IsUniqueViolation reports whether err is a postgres unique_violation (23505)
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// SniffContentType detects the content type from the first 512 bytes of r.
+// The returned reader still yields the whole content.
+func SniffContentType(r io.Reader) (string, io.Reader, error) {
+	head := make([]byte, 512)
+
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", nil, err
+	}
+	head = head[:n]
+
+	if n == 0 {
+		return "", bytes.NewReader(head), nil
+	}
+
+	return http.DetectContentType(head), io.MultiReader(bytes.NewReader(head), r), nil
+}
+
 func GenerateRandomString(lenght int) string {
 	alphaNumRunes := []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890")
 	randomRune := make([]rune, lenght)
@@ -0,0 +1,88 @@
+package imageprocessor
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+
+	// registers the formats image.Decode understands
+	_ "image/png"
+
+	"github.com/disintegration/imaging"
+	_ "golang.org/x/image/webp"
+
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+const (
+	maxPixels   = 40_000_000
+	jpegQuality = 85
+)
+
+var (
+	ErrInvalidImage  = errors.New("invalid image")
+	ErrImageTooLarge = errors.New("image dimensions are too large")
+)
+
+type ImageProcessorInterface interface {
+	Thumbnails(r io.Reader, sizes ...int) (map[int][]byte, error)
+}
+
+type ImageProcessorStruct struct{}
+
+var ImageProcessor = getImageProcessor()
+
+func getImageProcessor() ImageProcessorInterface {
+	return &ImageProcessorStruct{}
+}
+
+// Thumbnails crops the image to squares of the given sizes and encodes them
+// as JPEG. Re-encoding drops every metadata block, EXIF included, after the
+// EXIF orientation has been applied to the pixels.
+func (p *ImageProcessorStruct) Thumbnails(r io.Reader, sizes ...int) (map[int][]byte, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// check the header before decoding so a small file declaring huge
+	// dimensions can't exhaust memory
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	if config.Width*config.Height > maxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	// JPEG has no alpha channel, transparent areas become white
+	background := imaging.New(img.Bounds().Dx(), img.Bounds().Dy(), color.White)
+	img = imaging.Overlay(background, img, image.Pt(0, 0), 1)
+
+	thumbnails := make(map[int][]byte, len(sizes))
+	for _, size := range sizes {
+		var buf bytes.Buffer
+
+		thumbnail := imaging.Fill(img, size, size, imaging.Center, imaging.Lanczos)
+		if err := jpeg.Encode(&buf, thumbnail, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			log.Error(log.LogInfo{
+				"error": err.Error(),
+				"size":  size,
+			}, "[IMAGE PROCESSOR][Thumbnails] failed to encode thumbnail")
+			return nil, err
+		}
+
+		thumbnails[size] = buf.Bytes()
+	}
+
+	return thumbnails, nil
+}
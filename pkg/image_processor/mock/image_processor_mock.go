@@ -0,0 +1,61 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/image_processor/image_processor.go
+//
+// Generated by this command:
+//
+//	mockgen -source=pkg/image_processor/image_processor.go -destination=pkg/image_processor/mock/image_processor_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	io "io"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockImageProcessorInterface is a mock of ImageProcessorInterface interface.
+type MockImageProcessorInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockImageProcessorInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockImageProcessorInterfaceMockRecorder is the mock recorder for MockImageProcessorInterface.
+type MockImageProcessorInterfaceMockRecorder struct {
+	mock *MockImageProcessorInterface
+}
+
+// NewMockImageProcessorInterface creates a new mock instance.
+func NewMockImageProcessorInterface(ctrl *gomock.Controller) *MockImageProcessorInterface {
+	mock := &MockImageProcessorInterface{ctrl: ctrl}
+	mock.recorder = &MockImageProcessorInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImageProcessorInterface) EXPECT() *MockImageProcessorInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Thumbnails mocks base method.
+func (m *MockImageProcessorInterface) Thumbnails(r io.Reader, sizes ...int) (map[int][]byte, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{r}
+	for _, a := range sizes {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Thumbnails", varargs...)
+	ret0, _ := ret[0].(map[int][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Thumbnails indicates an expected call of Thumbnails.
+func (mr *MockImageProcessorInterfaceMockRecorder) Thumbnails(r any, sizes ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{r}, sizes...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Thumbnails", reflect.TypeOf((*MockImageProcessorInterface)(nil).Thumbnails), varargs...)
+}
@@ -8,8 +8,20 @@ import (
 	"github.com/google/uuid"
 )
 
+// Authentication method references for the amr claim, following RFC 8176
+// where it defines a value
+const (
+	AMRPassword     = "pwd"
+	AMROTP          = "otp"
+	AMRRecoveryCode = "rec"
+	AMRFederated    = "fed"
+	AMRMagicLink    = "link"
+	AMRHardwareKey  = "hwk"
+	AMRMultiFactor  = "mfa"
+)
+
 type JwtInterface interface {
-	Create(userID uuid.UUID, roleName string) (string, error)
+	Create(userID uuid.UUID, roleName string, amr []string) (string, error)
 	Decode(tokenString string, claims *Claims) error
 }
 
@@ -17,6 +29,11 @@ type Claims struct {
 	jwt.RegisteredClaims
 	UserID   uuid.UUID `json:"user_id"`
 	RoleName string    `json:"role_name"`
+	AMR      []string  `json:"amr,omitempty"`
+	// PersonalAccessTokenID and Scopes are only set when the request was
+	// authenticated with a personal access token, they never go into a jwt
+	PersonalAccessTokenID uuid.UUID `json:"-"`
+	Scopes                []string  `json:"-"`
 }
 
 type JwtStruct struct {
@@ -34,7 +51,7 @@ func getJwt() JwtInterface {
 	}
 }
 
-func (j *JwtStruct) Create(userID uuid.UUID, roleName string) (string, error) {
+func (j *JwtStruct) Create(userID uuid.UUID, roleName string, amr []string) (string, error) {
 	claims := Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    "hackathon-fiber-starter",
@@ -47,6 +64,7 @@ func (j *JwtStruct) Create(userID uuid.UUID, roleName string) (string, error) {
 		},
 		UserID: userID,
 		RoleName: roleName,
+		AMR: amr,
 	}
 
 	unsignedJWT := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -12,8 +12,8 @@ package mock
 import (
 	reflect "reflect"
 
-	jwt "github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
 	uuid "github.com/google/uuid"
+	jwt "github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -42,18 +42,18 @@ func (m *MockJwtInterface) EXPECT() *MockJwtInterfaceMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockJwtInterface) Create(userID uuid.UUID) (string, error) {
+func (m *MockJwtInterface) Create(userID uuid.UUID, roleName string, amr []string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", userID)
+	ret := m.ctrl.Call(m, "Create", userID, roleName, amr)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockJwtInterfaceMockRecorder) Create(userID any) *gomock.Call {
+func (mr *MockJwtInterfaceMockRecorder) Create(userID, roleName, amr any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockJwtInterface)(nil).Create), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockJwtInterface)(nil).Create), userID, roleName, amr)
 }
 
 // Decode mocks base method.
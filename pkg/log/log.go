@@ -13,6 +13,8 @@ type LogInfo map[string]interface{}
 
 var logger zerolog.Logger
 
+var fileWriter *lumberjack.Logger
+
 func GetLogger() *zerolog.Logger {
 	return &logger
 }
@@ -21,7 +23,7 @@ func init() {
 	consoleWriter := zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339}
 
 	logFileName := fmt.Sprintf("./data/logs/app-%s.log", time.Now().Format("2006-01-02"))
-	fileWriter := &lumberjack.Logger{
+	fileWriter = &lumberjack.Logger{
 		Filename:  logFileName,
 		LocalTime: true,
 		Compress:  true,
@@ -32,6 +34,11 @@ func init() {
 	logger = zerolog.New(multi).With().Timestamp().Logger()
 }
 
+// Close flushes and closes the log file, it should be the last thing called on shutdown
+func Close() error {
+	return fileWriter.Close()
+}
+
 func UpdateContext(key, value string) {
 	logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
 		return c.Str(key, value)
@@ -0,0 +1,311 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/gomail.v2"
+
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+type Message struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+type MailInterface interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+var Mail = getMail()
+
+// getMail picks the sender from MAIL_DRIVER: smtp (default), file or memory
+func getMail() MailInterface {
+	switch env.AppEnv.MailDriver {
+	case "file":
+		return NewFileMailer(env.AppEnv.MailFilePath)
+	case "memory":
+		return NewMemoryMailer()
+	default:
+		return NewSMTPMailer(
+			env.AppEnv.GomailHost,
+			env.AppEnv.GomailPort,
+			env.AppEnv.GomailUsername,
+			env.AppEnv.GomailPassword,
+			env.AppEnv.GomailFrom,
+		)
+	}
+}
+
+// smtpTimeout bounds a whole delivery when the context has no earlier
+// deadline, a hung server must not hold a send past its outbox lease
+const smtpTimeout = 30 * time.Second
+
+type SMTPMailer struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+}
+
+func NewSMTPMailer(
+	host string,
+	port int,
+	username string,
+	password string,
+	from string,
+) *SMTPMailer {
+	if from == "" {
+		from = username
+	}
+
+	return &SMTPMailer{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	message := gomail.NewMessage()
+	message.SetHeader("From", m.from)
+	message.SetHeader("To", msg.To...)
+	message.SetHeader("Subject", msg.Subject)
+	message.SetBody("text/plain", msg.Text)
+	if msg.HTML != "" {
+		message.AddAlternative("text/html", msg.HTML)
+	}
+
+	if err := m.send(ctx, msg.To, message); err != nil {
+		log.Error(log.LogInfo{
+			"error":   err.Error(),
+			"subject": msg.Subject,
+		}, "[MAIL][Send] failed to send mail")
+
+		return err
+	}
+
+	return nil
+}
+
+// send runs the smtp session itself, gomail's dialer has no deadline once it
+// is connected. Every read and write shares the context deadline and
+// cancelling the context closes the connection.
+func (m *SMTPMailer) send(ctx context.Context, to []string, message *gomail.Message) error {
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	rawConn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(m.host, strconv.Itoa(m.port)))
+	if err != nil {
+		return err
+	}
+	defer rawConn.Close()
+
+	stop := context.AfterFunc(ctx, func() {
+		rawConn.Close()
+	})
+	defer stop()
+
+	deadline, _ := ctx.Deadline()
+	if err := rawConn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName: m.host,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	// 465 speaks tls from the first byte, other ports upgrade with STARTTLS
+	implicitTLS := m.port == 465
+
+	conn := rawConn
+	if implicitTLS {
+		conn = tls.Client(rawConn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if !implicitTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		}
+	}
+
+	if m.username != "" {
+		if ok, mechanisms := client.Extension("AUTH"); ok {
+			if err := client.Auth(m.auth(mechanisms)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := client.Mail(m.from); err != nil {
+		return err
+	}
+
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := message.WriteTo(writer); err != nil {
+		writer.Close()
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// auth picks the mechanism the same way gomail does
+func (m *SMTPMailer) auth(mechanisms string) smtp.Auth {
+	switch {
+	case strings.Contains(mechanisms, "CRAM-MD5"):
+		return smtp.CRAMMD5Auth(m.username, m.password)
+	case strings.Contains(mechanisms, "LOGIN") && !strings.Contains(mechanisms, "PLAIN"):
+		return &loginAuth{
+			username: m.username,
+			password: m.password,
+		}
+	default:
+		return smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+}
+
+// loginAuth implements the LOGIN mechanism net/smtp leaves out, it refuses to
+// send the password over an unencrypted connection
+type loginAuth struct {
+	username string
+	password string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS {
+		return "", nil, errors.New("mail: unencrypted connection")
+	}
+
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(string(fromServer))) {
+	case "username:":
+		return []byte(a.username), nil
+	case "password:":
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("mail: unexpected server challenge %q", fromServer)
+	}
+}
+
+// MemoryMailer keeps sent messages in memory so tests can assert on them
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(_ context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, msg)
+
+	return nil
+}
+
+func (m *MemoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	messages := make([]Message, len(m.messages))
+	copy(messages, m.messages)
+
+	return messages
+}
+
+// FileMailer writes every message as a text file to dir, handy for local
+// development without an SMTP server
+type FileMailer struct {
+	dir string
+}
+
+func NewFileMailer(dir string) *FileMailer {
+	return &FileMailer{
+		dir: dir,
+	}
+}
+
+func (m *FileMailer) Send(_ context.Context, msg Message) error {
+	if err := os.MkdirAll(m.dir, 0o750); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[MAIL][Send] failed to create mail directory")
+
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.txt", time.Now().Format("20060102-150405.000000000"), strings.Join(msg.To, "_"))
+	content := fmt.Sprintf(
+		"To: %s\nSubject: %s\n\n%s\n\n--- html ---\n%s\n",
+		strings.Join(msg.To, ", "),
+		msg.Subject,
+		msg.Text,
+		msg.HTML,
+	)
+
+	if err := os.WriteFile(filepath.Join(m.dir, filepath.Base(name)), []byte(content), 0o600); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[MAIL][Send] failed to write mail file")
+
+		return err
+	}
+
+	return nil
+}
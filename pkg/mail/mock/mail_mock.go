@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/mail/mail.go
+//
+// Generated by this command:
+//
+//	mockgen -source=pkg/mail/mail.go -destination=pkg/mail/mock/mail_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	mail "github.com/kelompok1-swe-academya/caper-be/pkg/mail"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMailInterface is a mock of MailInterface interface.
+type MockMailInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockMailInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockMailInterfaceMockRecorder is the mock recorder for MockMailInterface.
+type MockMailInterfaceMockRecorder struct {
+	mock *MockMailInterface
+}
+
+// NewMockMailInterface creates a new mock instance.
+func NewMockMailInterface(ctrl *gomock.Controller) *MockMailInterface {
+	mock := &MockMailInterface{ctrl: ctrl}
+	mock.recorder = &MockMailInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMailInterface) EXPECT() *MockMailInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockMailInterface) Send(ctx context.Context, msg mail.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockMailInterfaceMockRecorder) Send(ctx, msg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockMailInterface)(nil).Send), ctx, msg)
+}
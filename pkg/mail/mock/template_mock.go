@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/mail/template.go
+//
+// Generated by this command:
+//
+//	mockgen -source=pkg/mail/template.go -destination=pkg/mail/mock/template_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	mail "github.com/kelompok1-swe-academya/caper-be/pkg/mail"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTemplateInterface is a mock of TemplateInterface interface.
+type MockTemplateInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTemplateInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockTemplateInterfaceMockRecorder is the mock recorder for MockTemplateInterface.
+type MockTemplateInterfaceMockRecorder struct {
+	mock *MockTemplateInterface
+}
+
+// NewMockTemplateInterface creates a new mock instance.
+func NewMockTemplateInterface(ctrl *gomock.Controller) *MockTemplateInterface {
+	mock := &MockTemplateInterface{ctrl: ctrl}
+	mock.recorder = &MockTemplateInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTemplateInterface) EXPECT() *MockTemplateInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Render mocks base method.
+func (m *MockTemplateInterface) Render(locale, name string, data any) (mail.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Render", locale, name, data)
+	ret0, _ := ret[0].(mail.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Render indicates an expected call of Render.
+func (mr *MockTemplateInterfaceMockRecorder) Render(locale, name, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Render", reflect.TypeOf((*MockTemplateInterface)(nil).Render), locale, name, data)
+}
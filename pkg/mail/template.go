@@ -0,0 +1,170 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	htmlTemplate "html/template"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	textTemplate "text/template"
+
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+const DefaultLocale = "en"
+
+// Locales are the translations under templates, DefaultLocale comes first so
+// content negotiation falls back to it
+var Locales = []string{DefaultLocale, "id"}
+
+// NormalizeLocale returns locale when there are templates for it and
+// DefaultLocale otherwise
+func NormalizeLocale(locale string) string {
+	if slices.Contains(Locales, locale) {
+		return locale
+	}
+
+	return DefaultLocale
+}
+
+const (
+	TemplatePasswordReset     = "password_reset"
+	TemplateEmailVerification = "email_verification"
+	TemplateAccountLocked     = "account_locked"
+	TemplateMagicLink         = "magic_link"
+	TemplateEmailChange       = "email_change"
+	TemplatePasskeyAdded      = "passkey_added"
+)
+
+// LinkData is the data of templates that send the user a link to follow
+type LinkData struct {
+	Name      string
+	Link      string
+	ExpiresIn string
+}
+
+// AccountLockedData is the data of the account locked template, Link points
+// to the password reset page
+type AccountLockedData struct {
+	Name      string
+	LockedFor string
+	Link      string
+}
+
+// PasskeyAddedData is the data of the passkey added template, Link points to
+// the page where passkeys can be removed
+type PasskeyAddedData struct {
+	Name        string
+	PasskeyName string
+	Link        string
+}
+
+//go:embed templates
+var templateFS embed.FS
+
+// TemplateInterface renders the templates/<locale>/<name>.txt.tmpl and
+// .html.tmpl pair. The text template defines the subject block.
+type TemplateInterface interface {
+	Render(locale string, name string, data any) (Message, error)
+}
+
+type mailTemplate struct {
+	text *textTemplate.Template
+	html *htmlTemplate.Template
+}
+
+type TemplateStruct struct {
+	templates map[string]mailTemplate
+}
+
+var Template = getTemplate()
+
+func getTemplate() TemplateInterface {
+	templates, err := parseTemplates()
+	if err != nil {
+		log.Fatal(log.LogInfo{
+			"error": err.Error(),
+		}, "[MAIL][getTemplate] failed to parse mail templates")
+	}
+
+	return &TemplateStruct{
+		templates: templates,
+	}
+}
+
+// Render falls back to DefaultLocale when the template isn't translated
+func (t *TemplateStruct) Render(locale string, name string, data any) (Message, error) {
+	var msg Message
+
+	tmpl, ok := t.templates[path.Join(locale, name)]
+	if !ok {
+		tmpl, ok = t.templates[path.Join(DefaultLocale, name)]
+	}
+
+	if !ok {
+		log.Error(log.LogInfo{
+			"locale":   locale,
+			"template": name,
+		}, "[MAIL][Render] mail template not found")
+		return msg, fs.ErrNotExist
+	}
+
+	var subject, text, html bytes.Buffer
+
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return msg, err
+	}
+
+	if err := tmpl.text.Execute(&text, data); err != nil {
+		return msg, err
+	}
+
+	if tmpl.html != nil {
+		if err := tmpl.html.ExecuteTemplate(&html, "layout", data); err != nil {
+			return msg, err
+		}
+	}
+
+	msg.Subject = strings.TrimSpace(subject.String())
+	msg.Text = text.String()
+	msg.HTML = html.String()
+
+	return msg, nil
+}
+
+func parseTemplates() (map[string]mailTemplate, error) {
+	templates := make(map[string]mailTemplate)
+
+	textPaths, err := fs.Glob(templateFS, "templates/*/*.txt.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, textPath := range textPaths {
+		locale := path.Base(path.Dir(textPath))
+		name := strings.TrimSuffix(path.Base(textPath), ".txt.tmpl")
+
+		text, err := textTemplate.ParseFS(templateFS, textPath)
+		if err != nil {
+			return nil, err
+		}
+
+		tmpl := mailTemplate{
+			text: text,
+		}
+
+		htmlPath := path.Join("templates", locale, name+".html.tmpl")
+		if _, err := fs.Stat(templateFS, htmlPath); err == nil {
+			tmpl.html, err = htmlTemplate.ParseFS(templateFS, "templates/layout.html.tmpl", htmlPath)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		templates[path.Join(locale, name)] = tmpl
+	}
+
+	return templates, nil
+}
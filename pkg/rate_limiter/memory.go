@@ -0,0 +1,122 @@
+package ratelimiter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+)
+
+// memoryState holds whatever the key's algorithm needs, windows are counted
+// in prev and curr and buckets in tokens
+type memoryState struct {
+	window    int64
+	prev      int64
+	curr      int64
+	tokens    float64
+	updatedAt time.Time
+	expiresAt time.Time
+}
+
+type MemoryRateLimiter struct {
+	mu     sync.Mutex
+	time   timePkg.TimeInterface
+	states map[string]*memoryState
+}
+
+func NewMemoryRateLimiter(timeProvider timePkg.TimeInterface) *MemoryRateLimiter {
+	return &MemoryRateLimiter{
+		time:   timeProvider,
+		states: make(map[string]*memoryState),
+	}
+}
+
+func (l *MemoryRateLimiter) Allow(_ context.Context, key string, policy Policy) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.time.Now()
+
+	state, ok := l.states[key]
+	if !ok || !now.Before(state.expiresAt) {
+		state = &memoryState{
+			tokens:    float64(policy.Limit),
+			updatedAt: now,
+		}
+		l.states[key] = state
+	}
+
+	switch policy.Algorithm {
+	case SlidingWindow:
+		return l.slidingWindow(state, policy, now), nil
+	case TokenBucket:
+		return l.tokenBucket(state, policy, now), nil
+	default:
+		return l.fixedWindow(state, policy, now), nil
+	}
+}
+
+func (l *MemoryRateLimiter) fixedWindow(state *memoryState, policy Policy, now time.Time) Result {
+	if state.curr == 0 {
+		state.expiresAt = now.Add(policy.Window)
+	}
+
+	state.curr++
+
+	return fixedWindowResult(policy, state.curr, state.expiresAt.Sub(now))
+}
+
+func (l *MemoryRateLimiter) slidingWindow(state *memoryState, policy Policy, now time.Time) Result {
+	window := policy.Window.Milliseconds()
+	current := now.UnixMilli() / window
+	elapsed := now.UnixMilli() - current*window
+
+	switch {
+	case current == state.window+1:
+		state.prev, state.curr = state.curr, 0
+	case current != state.window:
+		state.prev, state.curr = 0, 0
+	}
+
+	state.window = current
+	state.expiresAt = time.UnixMilli((current + 2) * window)
+
+	allowed := slidingWindowEstimate(window, elapsed, state.prev, state.curr) < int64(policy.Limit)
+	if allowed {
+		state.curr++
+	}
+
+	return slidingWindowResult(policy, allowed, elapsed, state.prev, state.curr)
+}
+
+func (l *MemoryRateLimiter) tokenBucket(state *memoryState, policy Policy, now time.Time) Result {
+	rate := float64(policy.Limit) / float64(policy.Window.Milliseconds())
+	refill := float64(now.Sub(state.updatedAt)) / float64(time.Millisecond) * rate
+
+	state.tokens = min(float64(policy.Limit), state.tokens+refill)
+	state.updatedAt = now
+	state.expiresAt = now.Add(policy.Window)
+
+	allowed := state.tokens >= 1
+	if allowed {
+		state.tokens--
+	}
+
+	return tokenBucketResult(policy, allowed, state.tokens)
+}
+
+// DeleteExpired drops keys that would start from scratch on their next request
+func (l *MemoryRateLimiter) DeleteExpired(_ context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.time.Now()
+	for key, state := range l.states {
+		if !now.Before(state.expiresAt) {
+			delete(l.states, key)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/rate_limiter/rate_limiter.go
+//
+// Generated by this command:
+//
+//	mockgen -source=pkg/rate_limiter/rate_limiter.go -destination=pkg/rate_limiter/mock/rate_limiter_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	ratelimiter "github.com/kelompok1-swe-academya/caper-be/pkg/rate_limiter"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRateLimiterInterface is a mock of RateLimiterInterface interface.
+type MockRateLimiterInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateLimiterInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockRateLimiterInterfaceMockRecorder is the mock recorder for MockRateLimiterInterface.
+type MockRateLimiterInterfaceMockRecorder struct {
+	mock *MockRateLimiterInterface
+}
+
+// NewMockRateLimiterInterface creates a new mock instance.
+func NewMockRateLimiterInterface(ctrl *gomock.Controller) *MockRateLimiterInterface {
+	mock := &MockRateLimiterInterface{ctrl: ctrl}
+	mock.recorder = &MockRateLimiterInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateLimiterInterface) EXPECT() *MockRateLimiterInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Allow mocks base method.
+func (m *MockRateLimiterInterface) Allow(ctx context.Context, key string, policy ratelimiter.Policy) (ratelimiter.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Allow", ctx, key, policy)
+	ret0, _ := ret[0].(ratelimiter.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Allow indicates an expected call of Allow.
+func (mr *MockRateLimiterInterfaceMockRecorder) Allow(ctx, key, policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Allow", reflect.TypeOf((*MockRateLimiterInterface)(nil).Allow), ctx, key, policy)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockRateLimiterInterface) DeleteExpired(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockRateLimiterInterfaceMockRecorder) DeleteExpired(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockRateLimiterInterface)(nil).DeleteExpired), ctx)
+}
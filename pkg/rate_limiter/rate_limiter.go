@@ -0,0 +1,129 @@
+package ratelimiter
+
+import (
+	"context"
+	"time"
+
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/database"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+)
+
+type Algorithm string
+
+const (
+	// FixedWindow counts requests in consecutive windows, cheap but lets a
+	// burst of twice the limit through around a window boundary
+	FixedWindow Algorithm = "fixed_window"
+	// SlidingWindow weighs the previous window by how much of it still
+	// overlaps the last Window, smoothing out the boundary burst
+	SlidingWindow Algorithm = "sliding_window"
+	// TokenBucket allows bursts up to Limit and refills Limit tokens per Window
+	TokenBucket Algorithm = "token_bucket"
+)
+
+type Policy struct {
+	Algorithm Algorithm
+	Limit     int
+	Window    time.Duration
+}
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
+
+type RateLimiterInterface interface {
+	Allow(ctx context.Context, key string, policy Policy) (Result, error)
+	DeleteExpired(ctx context.Context) error
+}
+
+var RateLimiter = getRateLimiter()
+
+// getRateLimiter picks the store from RATE_LIMIT_STORE: memory (default) or
+// redis. Replicas only share their limits through redis.
+func getRateLimiter() RateLimiterInterface {
+	if env.AppEnv.RateLimitStore != "redis" {
+		return NewMemoryRateLimiter(timePkg.Time)
+	}
+
+	return NewRedisRateLimiter(database.GetRedisConn())
+}
+
+func fixedWindowResult(policy Policy, count int64, resetAfter time.Duration) Result {
+	res := Result{
+		Allowed:    count <= int64(policy.Limit),
+		Limit:      policy.Limit,
+		Remaining:  max(policy.Limit-int(count), 0),
+		ResetAfter: resetAfter,
+	}
+
+	if !res.Allowed {
+		res.RetryAfter = resetAfter
+	}
+
+	return res
+}
+
+// slidingWindowEstimate is the number of requests seen in the last window,
+// counting the previous window in proportion to its remaining overlap. It is
+// computed in whole milliseconds to match the redis script exactly.
+func slidingWindowEstimate(window int64, elapsed int64, prev int64, curr int64) int64 {
+	return prev*(window-elapsed)/window + curr
+}
+
+// slidingWindowResult describes the state after a request was counted, or
+// rejected when allowed is false
+func slidingWindowResult(policy Policy, allowed bool, elapsed int64, prev int64, curr int64) Result {
+	window := policy.Window.Milliseconds()
+	estimate := slidingWindowEstimate(window, elapsed, prev, curr)
+
+	res := Result{
+		Allowed:    allowed,
+		Limit:      policy.Limit,
+		Remaining:  max(policy.Limit-int(estimate), 0),
+		ResetAfter: time.Duration(window-elapsed) * time.Millisecond,
+	}
+
+	if allowed {
+		return res
+	}
+
+	// the request fits once the previous window's share drops below what is
+	// left of the limit. A full current window becomes the previous one and
+	// still counts in full when the next window starts, so it has to slide
+	// out by at least a millisecond there too.
+	free := int64(policy.Limit) - curr
+	if free <= 0 {
+		at := window - int64(policy.Limit)*window/curr + 1
+		res.RetryAfter = res.ResetAfter + time.Duration(at)*time.Millisecond
+		return res
+	}
+
+	at := window - free*window/prev + 1
+	res.RetryAfter = time.Duration(max(at-elapsed, 1)) * time.Millisecond
+
+	return res
+}
+
+// tokenBucketResult describes the bucket after a request tried to take a
+// token, rate is in tokens per millisecond
+func tokenBucketResult(policy Policy, allowed bool, tokens float64) Result {
+	rate := float64(policy.Limit) / float64(policy.Window.Milliseconds())
+
+	res := Result{
+		Allowed:    allowed,
+		Limit:      policy.Limit,
+		Remaining:  int(tokens),
+		ResetAfter: time.Duration((float64(policy.Limit)-tokens)/rate) * time.Millisecond,
+	}
+
+	if !allowed {
+		res.RetryAfter = time.Duration((1-tokens)/rate)*time.Millisecond + time.Millisecond
+	}
+
+	return res
+}
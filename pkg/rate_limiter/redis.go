@@ -0,0 +1,109 @@
+package ratelimiter
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "caper:ratelimit:"
+
+// the scripts read the clock from redis so replicas with a skewed clock
+// still agree on the windows
+
+var fixedWindowScript = redis.NewScript(`
+	local count = redis.call('INCR', KEYS[1])
+	if count == 1 then
+		redis.call('PEXPIRE', KEYS[1], ARGV[1])
+	end
+	return {count, redis.call('PTTL', KEYS[1])}
+`)
+
+var slidingWindowScript = redis.NewScript(`
+	local time = redis.call('TIME')
+	local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+	local limit = tonumber(ARGV[1])
+	local window = tonumber(ARGV[2])
+	local current = math.floor(now / window)
+	local elapsed = now - current * window
+	local currKey = KEYS[1] .. ':' .. current
+	local prev = tonumber(redis.call('GET', KEYS[1] .. ':' .. (current - 1)) or '0')
+	local curr = tonumber(redis.call('GET', currKey) or '0')
+	local allowed = 0
+	if math.floor(prev * (window - elapsed) / window) + curr < limit then
+		curr = redis.call('INCR', currKey)
+		redis.call('PEXPIRE', currKey, window * 2)
+		allowed = 1
+	end
+	return {allowed, elapsed, prev, curr}
+`)
+
+var tokenBucketScript = redis.NewScript(`
+	local time = redis.call('TIME')
+	local now = tonumber(time[1]) * 1000 + tonumber(time[2]) / 1000
+	local limit = tonumber(ARGV[1])
+	local window = tonumber(ARGV[2])
+	local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated_at')
+	local tokens = tonumber(state[1]) or limit
+	local updatedAt = tonumber(state[2]) or now
+	tokens = math.min(limit, tokens + math.max(0, now - updatedAt) * limit / window)
+	local allowed = 0
+	if tokens >= 1 then
+		tokens = tokens - 1
+		allowed = 1
+	end
+	redis.call('HSET', KEYS[1], 'tokens', string.format('%.6f', tokens), 'updated_at', string.format('%.3f', now))
+	redis.call('PEXPIRE', KEYS[1], window)
+	return {allowed, string.format('%.6f', tokens)}
+`)
+
+type RedisRateLimiter struct {
+	client *redis.Client
+}
+
+func NewRedisRateLimiter(client *redis.Client) *RedisRateLimiter {
+	return &RedisRateLimiter{
+		client: client,
+	}
+}
+
+func (l *RedisRateLimiter) Allow(ctx context.Context, key string, policy Policy) (Result, error) {
+	key = keyPrefix + key
+	window := policy.Window.Milliseconds()
+
+	switch policy.Algorithm {
+	case SlidingWindow:
+		values, err := slidingWindowScript.Run(ctx, l.client, []string{key}, policy.Limit, window).Int64Slice()
+		if err != nil {
+			return Result{}, err
+		}
+
+		return slidingWindowResult(policy, values[0] == 1, values[1], values[2], values[3]), nil
+	case TokenBucket:
+		values, err := tokenBucketScript.Run(ctx, l.client, []string{key}, policy.Limit, window).Slice()
+		if err != nil {
+			return Result{}, err
+		}
+
+		tokens, err := strconv.ParseFloat(values[1].(string), 64)
+		if err != nil {
+			return Result{}, err
+		}
+
+		return tokenBucketResult(policy, values[0].(int64) == 1, tokens), nil
+	default:
+		values, err := fixedWindowScript.Run(ctx, l.client, []string{key}, window).Int64Slice()
+		if err != nil {
+			return Result{}, err
+		}
+
+		return fixedWindowResult(policy, values[0], time.Duration(values[1])*time.Millisecond), nil
+	}
+}
+
+// DeleteExpired is a no-op, every key carries its own expiry in redis
+func (l *RedisRateLimiter) DeleteExpired(_ context.Context) error {
+	return nil
+}
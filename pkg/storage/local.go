@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+// LocalStorage keeps objects under a directory on disk. Signed urls point to
+// publicURL and carry an expiry and an HMAC that Verify checks before the
+// file is served.
+type LocalStorage struct {
+	root       string
+	publicURL  string
+	signingKey []byte
+}
+
+func NewLocalStorage(root string, publicURL string, signingKey string) *LocalStorage {
+	return &LocalStorage{
+		root:       root,
+		publicURL:  strings.TrimRight(publicURL, "/"),
+		signingKey: []byte(signingKey),
+	}
+}
+
+func (s *LocalStorage) Put(
+	ctx context.Context,
+	key string,
+	body io.Reader,
+	size int64,
+	contentType string,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	filePath := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o750); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"key":   key,
+		}, "[STORAGE][Put] failed to create object directory")
+		return err
+	}
+
+	// write to a temporary file first so readers never see a partial object
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"key":   key,
+		}, "[STORAGE][Put] failed to create temporary file")
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // already renamed on success
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"key":   key,
+		}, "[STORAGE][Put] failed to write object")
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filePath)
+}
+
+func (s *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	info := ObjectInfo{
+		Key: key,
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, info, err
+	}
+
+	file, err := os.Open(s.path(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, info, ErrObjectNotFound
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"key":   key,
+		}, "[STORAGE][Get] failed to open object")
+		return nil, info, err
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, info, err
+	}
+
+	info.Size = stat.Size()
+	info.ContentType = mime.TypeByExtension(path.Ext(key))
+	if info.ContentType == "" {
+		info.ContentType = "application/octet-stream"
+	}
+
+	return file, info, nil
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := os.Remove(s.path(key))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"key":   key,
+		}, "[STORAGE][Delete] failed to delete object")
+		return err
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) SignedURL(_ context.Context, key string, expiresAt time.Time) (string, error) {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(cleanKey(key), expires))
+
+	return fmt.Sprintf("%s/%s?%s", s.publicURL, cleanKey(key), query.Encode()), nil
+}
+
+// Verify checks a signature produced by SignedURL and that it hasn't expired
+func (s *LocalStorage) Verify(key string, expires string, signature string) bool {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(s.sign(cleanKey(key), expires)))
+}
+
+func (s *LocalStorage) sign(key string, expires string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(key + "\n" + expires))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *LocalStorage) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(cleanKey(key)))
+}
+
+// cleanKey resolves the key against a virtual root so ".." can't escape the
+// storage directory.
+func cleanKey(key string) string {
+	return strings.TrimPrefix(path.Clean("/"+key), "/")
+}
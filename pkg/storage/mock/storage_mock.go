@@ -0,0 +1,141 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/storage/storage.go
+//
+// Generated by this command:
+//
+//	mockgen -source=pkg/storage/storage.go -destination=pkg/storage/mock/storage_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+	time "time"
+
+	storage "github.com/kelompok1-swe-academya/caper-be/pkg/storage"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStorageInterface is a mock of StorageInterface interface.
+type MockStorageInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockStorageInterfaceMockRecorder is the mock recorder for MockStorageInterface.
+type MockStorageInterfaceMockRecorder struct {
+	mock *MockStorageInterface
+}
+
+// NewMockStorageInterface creates a new mock instance.
+func NewMockStorageInterface(ctrl *gomock.Controller) *MockStorageInterface {
+	mock := &MockStorageInterface{ctrl: ctrl}
+	mock.recorder = &MockStorageInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStorageInterface) EXPECT() *MockStorageInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockStorageInterface) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockStorageInterfaceMockRecorder) Delete(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStorageInterface)(nil).Delete), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockStorageInterface) Get(ctx context.Context, key string) (io.ReadCloser, storage.ObjectInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(storage.ObjectInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockStorageInterfaceMockRecorder) Get(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockStorageInterface)(nil).Get), ctx, key)
+}
+
+// Put mocks base method.
+func (m *MockStorageInterface) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, key, body, size, contentType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockStorageInterfaceMockRecorder) Put(ctx, key, body, size, contentType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockStorageInterface)(nil).Put), ctx, key, body, size, contentType)
+}
+
+// SignedURL mocks base method.
+func (m *MockStorageInterface) SignedURL(ctx context.Context, key string, expiresAt time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignedURL", ctx, key, expiresAt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignedURL indicates an expected call of SignedURL.
+func (mr *MockStorageInterfaceMockRecorder) SignedURL(ctx, key, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignedURL", reflect.TypeOf((*MockStorageInterface)(nil).SignedURL), ctx, key, expiresAt)
+}
+
+// MockSignatureVerifier is a mock of SignatureVerifier interface.
+type MockSignatureVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockSignatureVerifierMockRecorder
+	isgomock struct{}
+}
+
+// MockSignatureVerifierMockRecorder is the mock recorder for MockSignatureVerifier.
+type MockSignatureVerifierMockRecorder struct {
+	mock *MockSignatureVerifier
+}
+
+// NewMockSignatureVerifier creates a new mock instance.
+func NewMockSignatureVerifier(ctrl *gomock.Controller) *MockSignatureVerifier {
+	mock := &MockSignatureVerifier{ctrl: ctrl}
+	mock.recorder = &MockSignatureVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSignatureVerifier) EXPECT() *MockSignatureVerifierMockRecorder {
+	return m.recorder
+}
+
+// Verify mocks base method.
+func (m *MockSignatureVerifier) Verify(key, expires, signature string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", key, expires, signature)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockSignatureVerifierMockRecorder) Verify(key, expires, signature any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockSignatureVerifier)(nil).Verify), key, expires, signature)
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+// S3Storage works with any S3 compatible service, e.g. AWS S3, MinIO or R2
+type S3Storage struct {
+	client *minio.Client
+	bucket string
+}
+
+func NewS3Storage(
+	endpoint string,
+	region string,
+	bucket string,
+	accessKey string,
+	secretKey string,
+	useSSL bool,
+) (*S3Storage, error) {
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Region: region,
+		Secure: useSSL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3Storage{
+		client: client,
+		bucket: bucket,
+	}, nil
+}
+
+func (s *S3Storage) Put(
+	ctx context.Context,
+	key string,
+	body io.Reader,
+	size int64,
+	contentType string,
+) error {
+	_, err := s.client.PutObject(ctx, s.bucket, cleanKey(key), body, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"key":   key,
+		}, "[STORAGE][Put] failed to put s3 object")
+		return err
+	}
+
+	return nil
+}
+
+func (s *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	info := ObjectInfo{
+		Key: key,
+	}
+
+	object, err := s.client.GetObject(ctx, s.bucket, cleanKey(key), minio.GetObjectOptions{})
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"key":   key,
+		}, "[STORAGE][Get] failed to get s3 object")
+		return nil, info, err
+	}
+
+	// GetObject is lazy, Stat is the first call that reaches the server
+	stat, err := object.Stat()
+	if err != nil {
+		object.Close()
+
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, info, ErrObjectNotFound
+		}
+
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"key":   key,
+		}, "[STORAGE][Get] failed to stat s3 object")
+		return nil, info, err
+	}
+
+	info.Size = stat.Size
+	info.ContentType = stat.ContentType
+
+	return object, info, nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	err := s.client.RemoveObject(ctx, s.bucket, cleanKey(key), minio.RemoveObjectOptions{})
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"key":   key,
+		}, "[STORAGE][Delete] failed to delete s3 object")
+		return err
+	}
+
+	return nil
+}
+
+// SignedURL presigns for the time left until expiresAt, s3 counts it from the
+// moment of signing
+func (s *S3Storage) SignedURL(ctx context.Context, key string, expiresAt time.Time) (string, error) {
+	signed, err := s.client.PresignedGetObject(ctx, s.bucket, cleanKey(key), time.Until(expiresAt), nil)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+			"key":   key,
+		}, "[STORAGE][SignedURL] failed to presign s3 object url")
+		return "", err
+	}
+
+	return signed.String(), nil
+}
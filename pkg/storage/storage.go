@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+var ErrObjectNotFound = errors.New("object not found")
+
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+}
+
+type StorageInterface interface {
+	Put(
+		ctx context.Context,
+		key string,
+		body io.Reader,
+		size int64,
+		contentType string,
+	) error
+	Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+	SignedURL(ctx context.Context, key string, expiresAt time.Time) (string, error)
+}
+
+// SignatureVerifier is implemented by backends that serve their signed urls
+// through this application instead of the storage service
+type SignatureVerifier interface {
+	Verify(key string, expires string, signature string) bool
+}
+
+var Storage = getStorage()
+
+// getStorage picks the backend from STORAGE_DRIVER: local (default) or s3
+func getStorage() StorageInterface {
+	if env.AppEnv.StorageDriver != "s3" {
+		// an empty key would sign urls anyone can forge
+		if env.AppEnv.StorageSigningKey == "" {
+			log.Fatal(log.LogInfo{
+				"storage_driver": "local",
+			}, "[STORAGE][getStorage] local storage requires STORAGE_SIGNING_KEY")
+		}
+
+		return NewLocalStorage(
+			env.AppEnv.StorageLocalPath,
+			env.AppEnv.StoragePublicURL,
+			env.AppEnv.StorageSigningKey,
+		)
+	}
+
+	storage, err := NewS3Storage(
+		env.AppEnv.S3Endpoint,
+		env.AppEnv.S3Region,
+		env.AppEnv.S3Bucket,
+		env.AppEnv.S3AccessKey,
+		env.AppEnv.S3SecretKey,
+		env.AppEnv.S3UseSSL,
+	)
+	if err != nil {
+		log.Fatal(log.LogInfo{
+			"error": err.Error(),
+		}, "[STORAGE][getStorage] failed to create s3 storage")
+	}
+
+	return storage
+}
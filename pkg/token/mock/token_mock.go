@@ -0,0 +1,69 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/token/token.go
+//
+// Generated by this command:
+//
+//	mockgen -source=pkg/token/token.go -destination=pkg/token/mock/token_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTokenInterface is a mock of TokenInterface interface.
+type MockTokenInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockTokenInterfaceMockRecorder is the mock recorder for MockTokenInterface.
+type MockTokenInterfaceMockRecorder struct {
+	mock *MockTokenInterface
+}
+
+// NewMockTokenInterface creates a new mock instance.
+func NewMockTokenInterface(ctrl *gomock.Controller) *MockTokenInterface {
+	mock := &MockTokenInterface{ctrl: ctrl}
+	mock.recorder = &MockTokenInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenInterface) EXPECT() *MockTokenInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Generate mocks base method.
+func (m *MockTokenInterface) Generate(size int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Generate", size)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Generate indicates an expected call of Generate.
+func (mr *MockTokenInterfaceMockRecorder) Generate(size any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockTokenInterface)(nil).Generate), size)
+}
+
+// Hash mocks base method.
+func (m *MockTokenInterface) Hash(plain string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Hash", plain)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Hash indicates an expected call of Hash.
+func (mr *MockTokenInterfaceMockRecorder) Hash(plain any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Hash", reflect.TypeOf((*MockTokenInterface)(nil).Hash), plain)
+}
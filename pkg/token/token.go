@@ -0,0 +1,44 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+type TokenInterface interface {
+	Generate(size int) (string, error)
+	Hash(plain string) string
+}
+
+type TokenStruct struct{}
+
+var Token = getToken()
+
+func getToken() TokenInterface {
+	return &TokenStruct{}
+}
+
+// Generate returns a url-safe string built from size bytes of crypto/rand
+func (t *TokenStruct) Generate(size int) (string, error) {
+	bytes := make([]byte, size)
+	if _, err := rand.Read(bytes); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[TOKEN][Generate] failed to read random bytes")
+
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// Hash returns the hex encoded sha256 digest of plain, suitable for storing opaque tokens
+func (t *TokenStruct) Hash(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+
+	return hex.EncodeToString(sum[:])
+}
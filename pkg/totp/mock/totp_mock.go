@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/totp/totp.go
+//
+// Generated by this command:
+//
+//	mockgen -source=pkg/totp/totp.go -destination=pkg/totp/mock/totp_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTotpInterface is a mock of TotpInterface interface.
+type MockTotpInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTotpInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockTotpInterfaceMockRecorder is the mock recorder for MockTotpInterface.
+type MockTotpInterfaceMockRecorder struct {
+	mock *MockTotpInterface
+}
+
+// NewMockTotpInterface creates a new mock instance.
+func NewMockTotpInterface(ctrl *gomock.Controller) *MockTotpInterface {
+	mock := &MockTotpInterface{ctrl: ctrl}
+	mock.recorder = &MockTotpInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTotpInterface) EXPECT() *MockTotpInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Generate mocks base method.
+func (m *MockTotpInterface) Generate(accountName string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Generate", accountName)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Generate indicates an expected call of Generate.
+func (mr *MockTotpInterfaceMockRecorder) Generate(accountName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockTotpInterface)(nil).Generate), accountName)
+}
+
+// Open mocks base method.
+func (m *MockTotpInterface) Open(sealed string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", sealed)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockTotpInterfaceMockRecorder) Open(sealed any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockTotpInterface)(nil).Open), sealed)
+}
+
+// Seal mocks base method.
+func (m *MockTotpInterface) Seal(secret string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Seal", secret)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Seal indicates an expected call of Seal.
+func (mr *MockTotpInterfaceMockRecorder) Seal(secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Seal", reflect.TypeOf((*MockTotpInterface)(nil).Seal), secret)
+}
+
+// Validate mocks base method.
+func (m *MockTotpInterface) Validate(code, secret string, at time.Time) (int64, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Validate", code, secret, at)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Validate indicates an expected call of Validate.
+func (mr *MockTotpInterfaceMockRecorder) Validate(code, secret, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockTotpInterface)(nil).Validate), code, secret, at)
+}
@@ -0,0 +1,157 @@
+package totp
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/pquerna/otp"
+	pquernaTotp "github.com/pquerna/otp/totp"
+
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+const (
+	period = 30
+	digits = otp.DigitsSix
+	// skew accepts the codes of the neighbouring periods to allow for clock
+	// drift between the server and the authenticator
+	skew = 1
+)
+
+var ErrInvalidSealedSecret = errors.New("invalid sealed totp secret")
+
+type TotpInterface interface {
+	Generate(accountName string) (secret string, uri string, err error)
+	Validate(code string, secret string, at time.Time) (step int64, ok bool)
+	Seal(secret string) (string, error)
+	Open(sealed string) (string, error)
+}
+
+type TotpStruct struct {
+	issuer string
+	aead   cipher.AEAD
+}
+
+var Totp = getTotp()
+
+func getTotp() TotpInterface {
+	// an empty key still derives an AES key, secrets would be sealed under a
+	// key anyone can compute
+	if env.AppEnv.TotpEncryptionKey == "" {
+		log.Fatal(log.LogInfo{
+			"totp_issuer": env.AppEnv.TotpIssuer,
+		}, "[TOTP][getTotp] TOTP_ENCRYPTION_KEY is required")
+	}
+
+	totp, err := NewTotp(env.AppEnv.TotpIssuer, env.AppEnv.TotpEncryptionKey)
+	if err != nil {
+		log.Fatal(log.LogInfo{
+			"error": err.Error(),
+		}, "[TOTP][getTotp] failed to create totp")
+	}
+
+	return totp
+}
+
+// NewTotp seals secrets with AES-GCM under the sha256 digest of
+// encryptionKey, changing the key makes every enrolled secret unreadable
+func NewTotp(issuer string, encryptionKey string) (*TotpStruct, error) {
+	key := sha256.Sum256([]byte(encryptionKey))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TotpStruct{
+		issuer: issuer,
+		aead:   aead,
+	}, nil
+}
+
+// Generate returns a new base32 secret and the otpauth uri authenticator
+// apps read from a qr code
+func (t *TotpStruct) Generate(accountName string) (string, string, error) {
+	key, err := pquernaTotp.Generate(pquernaTotp.GenerateOpts{
+		Issuer:      t.issuer,
+		AccountName: accountName,
+		Period:      period,
+		Digits:      digits,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[TOTP][Generate] failed to generate totp secret")
+		return "", "", err
+	}
+
+	return key.Secret(), key.URL(), nil
+}
+
+// Validate returns the time step the code belongs to so callers can reject
+// a code that was already used within its period
+func (t *TotpStruct) Validate(code string, secret string, at time.Time) (int64, bool) {
+	current := at.Unix() / period
+
+	for offset := int64(-skew); offset <= skew; offset++ {
+		step := current + offset
+
+		expected, err := pquernaTotp.GenerateCodeCustom(secret, time.Unix(step*period, 0), pquernaTotp.ValidateOpts{
+			Period:    period,
+			Digits:    digits,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err != nil {
+			return 0, false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+func (t *TotpStruct) Seal(secret string) (string, error) {
+	nonce := make([]byte, t.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[TOTP][Seal] failed to read random bytes")
+		return "", err
+	}
+
+	sealed := t.aead.Seal(nonce, nonce, []byte(secret), nil)
+
+	return base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func (t *TotpStruct) Open(sealed string) (string, error) {
+	data, err := base64.RawStdEncoding.DecodeString(sealed)
+	if err != nil || len(data) < t.aead.NonceSize() {
+		return "", ErrInvalidSealedSecret
+	}
+
+	nonce, ciphertext := data[:t.aead.NonceSize()], data[t.aead.NonceSize():]
+
+	secret, err := t.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrInvalidSealedSecret
+	}
+
+	return string(secret), nil
+}
@@ -0,0 +1,104 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/webauthn/webauthn.go
+//
+// Generated by this command:
+//
+//	mockgen -source=pkg/webauthn/webauthn.go -destination=pkg/webauthn/mock/webauthn_mock.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	json "encoding/json"
+	reflect "reflect"
+
+	webauthn "github.com/kelompok1-swe-academya/caper-be/pkg/webauthn"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWebauthnInterface is a mock of WebauthnInterface interface.
+type MockWebauthnInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebauthnInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockWebauthnInterfaceMockRecorder is the mock recorder for MockWebauthnInterface.
+type MockWebauthnInterfaceMockRecorder struct {
+	mock *MockWebauthnInterface
+}
+
+// NewMockWebauthnInterface creates a new mock instance.
+func NewMockWebauthnInterface(ctrl *gomock.Controller) *MockWebauthnInterface {
+	mock := &MockWebauthnInterface{ctrl: ctrl}
+	mock.recorder = &MockWebauthnInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebauthnInterface) EXPECT() *MockWebauthnInterfaceMockRecorder {
+	return m.recorder
+}
+
+// BeginLogin mocks base method.
+func (m *MockWebauthnInterface) BeginLogin() (json.RawMessage, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginLogin")
+	ret0, _ := ret[0].(json.RawMessage)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// BeginLogin indicates an expected call of BeginLogin.
+func (mr *MockWebauthnInterfaceMockRecorder) BeginLogin() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginLogin", reflect.TypeOf((*MockWebauthnInterface)(nil).BeginLogin))
+}
+
+// BeginRegistration mocks base method.
+func (m *MockWebauthnInterface) BeginRegistration(user webauthn.User) (json.RawMessage, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginRegistration", user)
+	ret0, _ := ret[0].(json.RawMessage)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// BeginRegistration indicates an expected call of BeginRegistration.
+func (mr *MockWebauthnInterfaceMockRecorder) BeginRegistration(user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginRegistration", reflect.TypeOf((*MockWebauthnInterface)(nil).BeginRegistration), user)
+}
+
+// FinishLogin mocks base method.
+func (m *MockWebauthnInterface) FinishLogin(session string, response []byte, findUser func([]byte) (webauthn.User, error)) (webauthn.Credential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishLogin", session, response, findUser)
+	ret0, _ := ret[0].(webauthn.Credential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishLogin indicates an expected call of FinishLogin.
+func (mr *MockWebauthnInterfaceMockRecorder) FinishLogin(session, response, findUser any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishLogin", reflect.TypeOf((*MockWebauthnInterface)(nil).FinishLogin), session, response, findUser)
+}
+
+// FinishRegistration mocks base method.
+func (m *MockWebauthnInterface) FinishRegistration(user webauthn.User, session string, response []byte) (webauthn.Credential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishRegistration", user, session, response)
+	ret0, _ := ret[0].(webauthn.Credential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishRegistration indicates an expected call of FinishRegistration.
+func (mr *MockWebauthnInterfaceMockRecorder) FinishRegistration(user, session, response any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishRegistration", reflect.TypeOf((*MockWebauthnInterface)(nil).FinishRegistration), user, session, response)
+}
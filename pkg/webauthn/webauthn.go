@@ -0,0 +1,299 @@
+package webauthn
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	goWebauthn "github.com/go-webauthn/webauthn/webauthn"
+
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/log"
+)
+
+// CeremonyTimeout is how long the client has to answer the options of a
+// registration or login ceremony
+const CeremonyTimeout = 5 * time.Minute
+
+var (
+	ErrVerificationFailed = errors.New("webauthn verification failed")
+	ErrCredentialCloned   = errors.New("webauthn sign count did not increase")
+)
+
+// User is the account a ceremony runs for. ID is the user handle stored on
+// the authenticator, so it must never contain personal data.
+type User struct {
+	ID          []byte
+	Name        string
+	DisplayName string
+	Credentials []Credential
+}
+
+type Credential struct {
+	ID              []byte
+	PublicKey       []byte
+	AttestationType string
+	Transports      []string
+	AAGUID          []byte
+	SignCount       uint32
+	BackupEligible  bool
+	BackupState     bool
+}
+
+type WebauthnInterface interface {
+	// BeginRegistration returns the options for navigator.credentials.create
+	// and the session to keep until the ceremony finishes
+	BeginRegistration(user User) (options json.RawMessage, session string, err error)
+	FinishRegistration(user User, session string, response []byte) (Credential, error)
+	// BeginLogin starts a discoverable login, the authenticator picks the
+	// account so the user doesn't have to type anything
+	BeginLogin() (options json.RawMessage, session string, err error)
+	// FinishLogin looks the account up by the user handle the authenticator
+	// returned and gives back the credential with its new sign count
+	FinishLogin(session string, response []byte, findUser func(userHandle []byte) (User, error)) (Credential, error)
+}
+
+type WebauthnStruct struct {
+	webauthn *goWebauthn.WebAuthn
+}
+
+var Webauthn = getWebauthn()
+
+func getWebauthn() WebauthnInterface {
+	webauthn, err := NewWebauthn(
+		env.AppEnv.WebauthnRPID,
+		env.AppEnv.WebauthnRPDisplayName,
+		env.AppEnv.WebauthnRPOrigins,
+	)
+	if err != nil {
+		log.Fatal(log.LogInfo{
+			"error": err.Error(),
+		}, "[WEBAUTHN][getWebauthn] failed to create webauthn")
+	}
+
+	return webauthn
+}
+
+func NewWebauthn(rpID string, rpDisplayName string, rpOrigins []string) (*WebauthnStruct, error) {
+	timeout := goWebauthn.TimeoutConfig{
+		Enforce:    true,
+		Timeout:    CeremonyTimeout,
+		TimeoutUVD: CeremonyTimeout,
+	}
+
+	webauthn, err := goWebauthn.New(&goWebauthn.Config{
+		RPID:          rpID,
+		RPDisplayName: rpDisplayName,
+		RPOrigins:     rpOrigins,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementRequired,
+			UserVerification: protocol.VerificationRequired,
+		},
+		Timeouts: goWebauthn.TimeoutsConfig{
+			Login:        timeout,
+			Registration: timeout,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebauthnStruct{
+		webauthn: webauthn,
+	}, nil
+}
+
+func (w *WebauthnStruct) BeginRegistration(user User) (json.RawMessage, string, error) {
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(user.Credentials))
+	for _, credential := range user.Credentials {
+		exclusions = append(exclusions, toLibraryCredential(credential).Descriptor())
+	}
+
+	creation, session, err := w.webauthn.BeginRegistration(
+		webauthnUser{user},
+		goWebauthn.WithExclusions(exclusions),
+		goWebauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+	)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[WEBAUTHN][BeginRegistration] failed to begin registration")
+		return nil, "", err
+	}
+
+	return marshalCeremony(creation, session)
+}
+
+func (w *WebauthnStruct) FinishRegistration(user User, session string, response []byte) (Credential, error) {
+	var sessionData goWebauthn.SessionData
+	if err := json.Unmarshal([]byte(session), &sessionData); err != nil {
+		return Credential{}, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return Credential{}, verificationFailed("FinishRegistration", err)
+	}
+
+	credential, err := w.webauthn.CreateCredential(webauthnUser{user}, sessionData, parsed)
+	if err != nil {
+		return Credential{}, verificationFailed("FinishRegistration", err)
+	}
+
+	return fromLibraryCredential(*credential), nil
+}
+
+func (w *WebauthnStruct) BeginLogin() (json.RawMessage, string, error) {
+	assertion, session, err := w.webauthn.BeginDiscoverableLogin(
+		goWebauthn.WithUserVerification(protocol.VerificationRequired),
+	)
+	if err != nil {
+		log.Error(log.LogInfo{
+			"error": err.Error(),
+		}, "[WEBAUTHN][BeginLogin] failed to begin login")
+		return nil, "", err
+	}
+
+	return marshalCeremony(assertion, session)
+}
+
+func (w *WebauthnStruct) FinishLogin(
+	session string,
+	response []byte,
+	findUser func(userHandle []byte) (User, error),
+) (Credential, error) {
+	var sessionData goWebauthn.SessionData
+	if err := json.Unmarshal([]byte(session), &sessionData); err != nil {
+		return Credential{}, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return Credential{}, verificationFailed("FinishLogin", err)
+	}
+
+	handler := func(_ []byte, userHandle []byte) (goWebauthn.User, error) {
+		user, err := findUser(userHandle)
+		if err != nil {
+			return nil, err
+		}
+
+		return webauthnUser{user}, nil
+	}
+
+	credential, err := w.webauthn.ValidateDiscoverableLogin(handler, sessionData, parsed)
+	if err != nil {
+		return Credential{}, verificationFailed("FinishLogin", err)
+	}
+
+	// a counter that didn't move forward means the private key may have been
+	// copied to another authenticator, authenticators that don't count at
+	// all always report zero and are let through
+	if credential.Authenticator.CloneWarning {
+		return Credential{}, ErrCredentialCloned
+	}
+
+	return fromLibraryCredential(*credential), nil
+}
+
+type webauthnUser struct {
+	User
+}
+
+func (u webauthnUser) WebAuthnID() []byte {
+	return u.ID
+}
+
+func (u webauthnUser) WebAuthnName() string {
+	return u.Name
+}
+
+func (u webauthnUser) WebAuthnDisplayName() string {
+	return u.DisplayName
+}
+
+func (u webauthnUser) WebAuthnIcon() string {
+	return ""
+}
+
+func (u webauthnUser) WebAuthnCredentials() []goWebauthn.Credential {
+	credentials := make([]goWebauthn.Credential, 0, len(u.Credentials))
+	for _, credential := range u.Credentials {
+		credentials = append(credentials, toLibraryCredential(credential))
+	}
+
+	return credentials
+}
+
+func marshalCeremony(options any, session *goWebauthn.SessionData) (json.RawMessage, string, error) {
+	rawOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rawSession, err := json.Marshal(session)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return rawOptions, string(rawSession), nil
+}
+
+// verificationFailed keeps the details of a rejected response in the logs,
+// clients only learn that the ceremony failed
+func verificationFailed(method string, err error) error {
+	info := log.LogInfo{
+		"error": err.Error(),
+	}
+
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) {
+		info["details"] = protocolErr.DevInfo
+	}
+
+	log.Warn(info, "[WEBAUTHN]["+method+"] webauthn response rejected")
+
+	return ErrVerificationFailed
+}
+
+func toLibraryCredential(credential Credential) goWebauthn.Credential {
+	transports := make([]protocol.AuthenticatorTransport, 0, len(credential.Transports))
+	for _, transport := range credential.Transports {
+		transports = append(transports, protocol.AuthenticatorTransport(transport))
+	}
+
+	return goWebauthn.Credential{
+		ID:              credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transport:       transports,
+		Flags: goWebauthn.CredentialFlags{
+			BackupEligible: credential.BackupEligible,
+			BackupState:    credential.BackupState,
+		},
+		Authenticator: goWebauthn.Authenticator{
+			AAGUID:    credential.AAGUID,
+			SignCount: credential.SignCount,
+		},
+	}
+}
+
+func fromLibraryCredential(credential goWebauthn.Credential) Credential {
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+
+	return Credential{
+		ID:              credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      transports,
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+	}
+}
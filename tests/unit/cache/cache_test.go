@@ -0,0 +1,221 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kelompok1-swe-academya/caper-be/pkg/cache"
+)
+
+var errLoader = errors.New("database unavailable")
+
+// countingLoader returns value and counts how often the cache had to load it
+func countingLoader(value string, calls *atomic.Int32) cache.Loader {
+	return func(_ context.Context) ([]byte, error) {
+		calls.Add(1)
+		return []byte(value), nil
+	}
+}
+
+func TestMemoryCache_GetOrLoad(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemoryCache(10)
+
+	var calls atomic.Int32
+	for range 3 {
+		value, err := c.GetOrLoad(ctx, "user:1", time.Minute, countingLoader("jane", &calls))
+		require.NoError(t, err)
+		assert.Equal(t, "jane", string(value))
+	}
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+// a failed load is returned as is and never cached
+func TestMemoryCache_GetOrLoad_LoaderError(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemoryCache(10)
+
+	_, err := c.GetOrLoad(ctx, "user:1", time.Minute, func(_ context.Context) ([]byte, error) {
+		return nil, errLoader
+	})
+	assert.ErrorIs(t, err, errLoader)
+
+	_, err = c.Get(ctx, "user:1")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+
+	var calls atomic.Int32
+	value, err := c.GetOrLoad(ctx, "user:1", time.Minute, countingLoader("jane", &calls))
+	require.NoError(t, err)
+	assert.Equal(t, "jane", string(value))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestMemoryCache_GetOrLoad_Expired(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemoryCache(10)
+
+	var calls atomic.Int32
+	_, err := c.GetOrLoad(ctx, "user:1", 10*time.Millisecond, countingLoader("jane", &calls))
+	require.NoError(t, err)
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = c.GetOrLoad(ctx, "user:1", 10*time.Millisecond, countingLoader("jane", &calls))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+// concurrent misses on one key share a single load
+func TestMemoryCache_GetOrLoad_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemoryCache(10)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loader := func(_ context.Context) ([]byte, error) {
+		calls.Add(1)
+		<-release
+		return []byte("jane"), nil
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			value, err := c.GetOrLoad(ctx, "user:1", time.Minute, loader)
+			assert.NoError(t, err)
+			assert.Equal(t, "jane", string(value))
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestMemoryCache_InvalidateTags(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemoryCache(10)
+
+	var calls atomic.Int32
+	_, err := c.GetOrLoad(ctx, "user:1", time.Minute, countingLoader("jane", &calls), "users", "user:1")
+	require.NoError(t, err)
+	_, err = c.GetOrLoad(ctx, "user:2", time.Minute, countingLoader("john", &calls), "users", "user:2")
+	require.NoError(t, err)
+	require.NoError(t, c.Set(ctx, "roles", []byte("admin"), time.Minute, "roles"))
+
+	require.NoError(t, c.InvalidateTags(ctx, "user:1"))
+
+	_, err = c.Get(ctx, "user:1")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	_, err = c.Get(ctx, "user:2")
+	assert.NoError(t, err)
+
+	require.NoError(t, c.InvalidateTags(ctx, "users"))
+
+	_, err = c.Get(ctx, "user:2")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	_, err = c.Get(ctx, "roles")
+	assert.NoError(t, err, "other tags are kept")
+
+	_, err = c.GetOrLoad(ctx, "user:1", time.Minute, countingLoader("jane", &calls), "users", "user:1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), calls.Load())
+
+	assert.NoError(t, c.InvalidateTags(ctx, "unknown"))
+}
+
+// overwriting a key drops the tags it was stored with before
+func TestMemoryCache_InvalidateTags_Overwritten(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemoryCache(10)
+
+	require.NoError(t, c.Set(ctx, "user:1", []byte("jane"), time.Minute, "old"))
+	require.NoError(t, c.Set(ctx, "user:1", []byte("jane"), time.Minute, "new"))
+
+	require.NoError(t, c.InvalidateTags(ctx, "old"))
+
+	value, err := c.Get(ctx, "user:1")
+	require.NoError(t, err)
+	assert.Equal(t, "jane", string(value))
+}
+
+func TestMemoryCache_Eviction(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemoryCache(2)
+
+	require.NoError(t, c.Set(ctx, "a", []byte("a"), 0))
+	require.NoError(t, c.Set(ctx, "b", []byte("b"), 0))
+
+	// reading a makes b the least recently used
+	_, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+
+	require.NoError(t, c.Set(ctx, "c", []byte("c"), 0, "letters"))
+
+	_, err = c.Get(ctx, "b")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	_, err = c.Get(ctx, "a")
+	assert.NoError(t, err)
+	_, err = c.Get(ctx, "c")
+	assert.NoError(t, err)
+
+	// c was read last, so a goes next
+	require.NoError(t, c.Set(ctx, "d", []byte("d"), 0))
+
+	_, err = c.Get(ctx, "a")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+
+	require.NoError(t, c.InvalidateTags(ctx, "letters"))
+
+	_, err = c.Get(ctx, "c")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	_, err = c.Get(ctx, "d")
+	assert.NoError(t, err)
+}
+
+// values are copied in and out, callers can't change what is cached
+func TestMemoryCache_CopiesValues(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemoryCache(10)
+
+	value := []byte("jane")
+	require.NoError(t, c.Set(ctx, "user:1", value, time.Minute))
+	value[0] = 'J'
+
+	cached, err := c.Get(ctx, "user:1")
+	require.NoError(t, err)
+	assert.Equal(t, "jane", string(cached))
+
+	cached[0] = 'J'
+
+	cached, err = c.Get(ctx, "user:1")
+	require.NoError(t, err)
+	assert.Equal(t, "jane", string(cached))
+}
+
+func TestMemoryCache_Delete(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemoryCache(10)
+
+	require.NoError(t, c.Set(ctx, "a", []byte("a"), 0))
+	require.NoError(t, c.Set(ctx, "b", []byte("b"), 0))
+	require.NoError(t, c.Delete(ctx, "a", "b", "missing"))
+
+	_, err := c.Get(ctx, "a")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	_, err = c.Get(ctx, "b")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+}
@@ -0,0 +1,140 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/contracts/email_change_contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=domain/contracts/email_change_contracts.go -destination=tests/unit/email_change/repository/mock/email_change_repository_mock.go -package=repository_mock
+//
+
+// Package repository_mock is a generated GoMock package.
+package repository_mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	dto "github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	entity "github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEmailChangeRepository is a mock of EmailChangeRepository interface.
+type MockEmailChangeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmailChangeRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockEmailChangeRepositoryMockRecorder is the mock recorder for MockEmailChangeRepository.
+type MockEmailChangeRepositoryMockRecorder struct {
+	mock *MockEmailChangeRepository
+}
+
+// NewMockEmailChangeRepository creates a new mock instance.
+func NewMockEmailChangeRepository(ctrl *gomock.Controller) *MockEmailChangeRepository {
+	mock := &MockEmailChangeRepository{ctrl: ctrl}
+	mock.recorder = &MockEmailChangeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmailChangeRepository) EXPECT() *MockEmailChangeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockEmailChangeRepository) Consume(ctx context.Context, tokenHash string, now time.Time) (entity.EmailChangeToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, tokenHash, now)
+	ret0, _ := ret[0].(entity.EmailChangeToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockEmailChangeRepositoryMockRecorder) Consume(ctx, tokenHash, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockEmailChangeRepository)(nil).Consume), ctx, tokenHash, now)
+}
+
+// Create mocks base method.
+func (m *MockEmailChangeRepository) Create(ctx context.Context, token *entity.EmailChangeToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockEmailChangeRepositoryMockRecorder) Create(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEmailChangeRepository)(nil).Create), ctx, token)
+}
+
+// InvalidateByUserID mocks base method.
+func (m *MockEmailChangeRepository) InvalidateByUserID(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateByUserID", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateByUserID indicates an expected call of InvalidateByUserID.
+func (mr *MockEmailChangeRepositoryMockRecorder) InvalidateByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateByUserID", reflect.TypeOf((*MockEmailChangeRepository)(nil).InvalidateByUserID), ctx, userID)
+}
+
+// MockEmailChangeService is a mock of EmailChangeService interface.
+type MockEmailChangeService struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmailChangeServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockEmailChangeServiceMockRecorder is the mock recorder for MockEmailChangeService.
+type MockEmailChangeServiceMockRecorder struct {
+	mock *MockEmailChangeService
+}
+
+// NewMockEmailChangeService creates a new mock instance.
+func NewMockEmailChangeService(ctrl *gomock.Controller) *MockEmailChangeService {
+	mock := &MockEmailChangeService{ctrl: ctrl}
+	mock.recorder = &MockEmailChangeServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmailChangeService) EXPECT() *MockEmailChangeServiceMockRecorder {
+	return m.recorder
+}
+
+// Confirm mocks base method.
+func (m *MockEmailChangeService) Confirm(ctx context.Context, req dto.ConfirmEmailChangeRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockEmailChangeServiceMockRecorder) Confirm(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockEmailChangeService)(nil).Confirm), ctx, req)
+}
+
+// Request mocks base method.
+func (m *MockEmailChangeService) Request(ctx context.Context, user entity.User, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Request", ctx, user, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Request indicates an expected call of Request.
+func (mr *MockEmailChangeServiceMockRecorder) Request(ctx, user, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*MockEmailChangeService)(nil).Request), ctx, user, email)
+}
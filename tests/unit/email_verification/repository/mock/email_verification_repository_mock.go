@@ -0,0 +1,154 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/contracts/email_verification_contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=domain/contracts/email_verification_contracts.go -destination=tests/unit/email_verification/repository/mock/email_verification_repository_mock.go -package=repository_mock
+//
+
+// Package repository_mock is a generated GoMock package.
+package repository_mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	dto "github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	entity "github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEmailVerificationRepository is a mock of EmailVerificationRepository interface.
+type MockEmailVerificationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmailVerificationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockEmailVerificationRepositoryMockRecorder is the mock recorder for MockEmailVerificationRepository.
+type MockEmailVerificationRepositoryMockRecorder struct {
+	mock *MockEmailVerificationRepository
+}
+
+// NewMockEmailVerificationRepository creates a new mock instance.
+func NewMockEmailVerificationRepository(ctrl *gomock.Controller) *MockEmailVerificationRepository {
+	mock := &MockEmailVerificationRepository{ctrl: ctrl}
+	mock.recorder = &MockEmailVerificationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmailVerificationRepository) EXPECT() *MockEmailVerificationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockEmailVerificationRepository) Consume(ctx context.Context, tokenHash string, now time.Time) (entity.EmailVerificationToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, tokenHash, now)
+	ret0, _ := ret[0].(entity.EmailVerificationToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockEmailVerificationRepositoryMockRecorder) Consume(ctx, tokenHash, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockEmailVerificationRepository)(nil).Consume), ctx, tokenHash, now)
+}
+
+// Create mocks base method.
+func (m *MockEmailVerificationRepository) Create(ctx context.Context, token *entity.EmailVerificationToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockEmailVerificationRepositoryMockRecorder) Create(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEmailVerificationRepository)(nil).Create), ctx, token)
+}
+
+// InvalidateByUserID mocks base method.
+func (m *MockEmailVerificationRepository) InvalidateByUserID(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateByUserID", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateByUserID indicates an expected call of InvalidateByUserID.
+func (mr *MockEmailVerificationRepositoryMockRecorder) InvalidateByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateByUserID", reflect.TypeOf((*MockEmailVerificationRepository)(nil).InvalidateByUserID), ctx, userID)
+}
+
+// MockEmailVerificationService is a mock of EmailVerificationService interface.
+type MockEmailVerificationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmailVerificationServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockEmailVerificationServiceMockRecorder is the mock recorder for MockEmailVerificationService.
+type MockEmailVerificationServiceMockRecorder struct {
+	mock *MockEmailVerificationService
+}
+
+// NewMockEmailVerificationService creates a new mock instance.
+func NewMockEmailVerificationService(ctrl *gomock.Controller) *MockEmailVerificationService {
+	mock := &MockEmailVerificationService{ctrl: ctrl}
+	mock.recorder = &MockEmailVerificationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmailVerificationService) EXPECT() *MockEmailVerificationServiceMockRecorder {
+	return m.recorder
+}
+
+// Resend mocks base method.
+func (m *MockEmailVerificationService) Resend(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resend", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resend indicates an expected call of Resend.
+func (mr *MockEmailVerificationServiceMockRecorder) Resend(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resend", reflect.TypeOf((*MockEmailVerificationService)(nil).Resend), ctx, userID)
+}
+
+// Send mocks base method.
+func (m *MockEmailVerificationService) Send(ctx context.Context, user entity.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockEmailVerificationServiceMockRecorder) Send(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockEmailVerificationService)(nil).Send), ctx, user)
+}
+
+// Verify mocks base method.
+func (m *MockEmailVerificationService) Verify(ctx context.Context, req dto.VerifyEmailRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockEmailVerificationServiceMockRecorder) Verify(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockEmailVerificationService)(nil).Verify), ctx, req)
+}
@@ -0,0 +1,94 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/health"
+)
+
+var errDown = errors.New("connection refused")
+
+func check(err error) health.Check {
+	return func(context.Context) error {
+		return err
+	}
+}
+
+func TestHealth_Readiness(t *testing.T) {
+	tests := []struct {
+		name        string
+		databaseErr error
+		redisErr    error
+		wantStatus  string
+		wantReady   bool
+	}{
+		{
+			name:       "every check passes",
+			wantStatus: health.StatusOK,
+			wantReady:  true,
+		},
+		{
+			name:       "degraded check fails",
+			redisErr:   errDown,
+			wantStatus: health.StatusDegraded,
+			wantReady:  true,
+		},
+		{
+			name:        "check fails",
+			databaseErr: errDown,
+			wantStatus:  health.StatusUnavailable,
+		},
+		{
+			name:        "both fail",
+			databaseErr: errDown,
+			redisErr:    errDown,
+			wantStatus:  health.StatusUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := health.NewHealth()
+			checks.Register("database", time.Second, check(tt.databaseErr))
+			checks.RegisterDegraded("redis", time.Second, check(tt.redisErr))
+
+			report := checks.Readiness(context.Background())
+			assert.Equal(t, tt.wantStatus, report.Status)
+			assert.Equal(t, tt.wantReady, report.Ready())
+			assert.Len(t, report.Checks, 2)
+
+			if tt.redisErr != nil {
+				assert.Equal(t, health.StatusDegraded, report.Checks["redis"].Status)
+				assert.Equal(t, errDown.Error(), report.Checks["redis"].Error)
+			}
+		})
+	}
+}
+
+func TestHealth_Readiness_Timeout(t *testing.T) {
+	checks := health.NewHealth()
+	checks.Register("database", 10*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report := checks.Readiness(context.Background())
+	assert.Equal(t, health.StatusUnavailable, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["database"].Error)
+}
+
+func TestHealth_ShuttingDown(t *testing.T) {
+	checks := health.NewHealth()
+	checks.Register("database", time.Second, check(nil))
+	checks.MarkShuttingDown()
+
+	report := checks.Readiness(context.Background())
+	assert.Equal(t, health.StatusShuttingDown, report.Status)
+	assert.False(t, report.Ready())
+	assert.Equal(t, health.StatusOK, checks.Liveness().Status)
+}
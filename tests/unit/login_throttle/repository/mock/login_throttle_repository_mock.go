@@ -0,0 +1,214 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/contracts/login_throttle_contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=domain/contracts/login_throttle_contracts.go -destination=tests/unit/login_throttle/repository/mock/login_throttle_repository_mock.go -package=repository_mock
+//
+
+// Package repository_mock is a generated GoMock package.
+package repository_mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLoginThrottleRepository is a mock of LoginThrottleRepository interface.
+type MockLoginThrottleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginThrottleRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockLoginThrottleRepositoryMockRecorder is the mock recorder for MockLoginThrottleRepository.
+type MockLoginThrottleRepositoryMockRecorder struct {
+	mock *MockLoginThrottleRepository
+}
+
+// NewMockLoginThrottleRepository creates a new mock instance.
+func NewMockLoginThrottleRepository(ctrl *gomock.Controller) *MockLoginThrottleRepository {
+	mock := &MockLoginThrottleRepository{ctrl: ctrl}
+	mock.recorder = &MockLoginThrottleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginThrottleRepository) EXPECT() *MockLoginThrottleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpired mocks base method.
+func (m *MockLoginThrottleRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockLoginThrottleRepositoryMockRecorder) DeleteExpired(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockLoginThrottleRepository)(nil).DeleteExpired), ctx, before)
+}
+
+// FindLockedUntil mocks base method.
+func (m *MockLoginThrottleRepository) FindLockedUntil(ctx context.Context, now time.Time, throttleKeys ...string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, now}
+	for _, a := range throttleKeys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FindLockedUntil", varargs...)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLockedUntil indicates an expected call of FindLockedUntil.
+func (mr *MockLoginThrottleRepositoryMockRecorder) FindLockedUntil(ctx, now any, throttleKeys ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, now}, throttleKeys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLockedUntil", reflect.TypeOf((*MockLoginThrottleRepository)(nil).FindLockedUntil), varargs...)
+}
+
+// Lock mocks base method.
+func (m *MockLoginThrottleRepository) Lock(ctx context.Context, throttleKey string, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, throttleKey, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockLoginThrottleRepositoryMockRecorder) Lock(ctx, throttleKey, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockLoginThrottleRepository)(nil).Lock), ctx, throttleKey, until)
+}
+
+// RecordFailure mocks base method.
+func (m *MockLoginThrottleRepository) RecordFailure(ctx context.Context, throttleKey string, now, since time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", ctx, throttleKey, now, since)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockLoginThrottleRepositoryMockRecorder) RecordFailure(ctx, throttleKey, now, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockLoginThrottleRepository)(nil).RecordFailure), ctx, throttleKey, now, since)
+}
+
+// Reset mocks base method.
+func (m *MockLoginThrottleRepository) Reset(ctx context.Context, throttleKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, throttleKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockLoginThrottleRepositoryMockRecorder) Reset(ctx, throttleKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockLoginThrottleRepository)(nil).Reset), ctx, throttleKey)
+}
+
+// MockLoginThrottleService is a mock of LoginThrottleService interface.
+type MockLoginThrottleService struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginThrottleServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockLoginThrottleServiceMockRecorder is the mock recorder for MockLoginThrottleService.
+type MockLoginThrottleServiceMockRecorder struct {
+	mock *MockLoginThrottleService
+}
+
+// NewMockLoginThrottleService creates a new mock instance.
+func NewMockLoginThrottleService(ctrl *gomock.Controller) *MockLoginThrottleService {
+	mock := &MockLoginThrottleService{ctrl: ctrl}
+	mock.recorder = &MockLoginThrottleServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginThrottleService) EXPECT() *MockLoginThrottleServiceMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockLoginThrottleService) Check(ctx context.Context, email, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, email, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockLoginThrottleServiceMockRecorder) Check(ctx, email, ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockLoginThrottleService)(nil).Check), ctx, email, ip)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockLoginThrottleService) DeleteExpired(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockLoginThrottleServiceMockRecorder) DeleteExpired(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockLoginThrottleService)(nil).DeleteExpired), ctx)
+}
+
+// RecordFailure mocks base method.
+func (m *MockLoginThrottleService) RecordFailure(ctx context.Context, email, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", ctx, email, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockLoginThrottleServiceMockRecorder) RecordFailure(ctx, email, ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockLoginThrottleService)(nil).RecordFailure), ctx, email, ip)
+}
+
+// RecordSuccess mocks base method.
+func (m *MockLoginThrottleService) RecordSuccess(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordSuccess", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordSuccess indicates an expected call of RecordSuccess.
+func (mr *MockLoginThrottleServiceMockRecorder) RecordSuccess(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSuccess", reflect.TypeOf((*MockLoginThrottleService)(nil).RecordSuccess), ctx, email)
+}
+
+// Unlock mocks base method.
+func (m *MockLoginThrottleService) Unlock(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockLoginThrottleServiceMockRecorder) Unlock(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockLoginThrottleService)(nil).Unlock), ctx, userID)
+}
@@ -0,0 +1,305 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/app/login_throttle/service"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/mail"
+	timeMock "github.com/kelompok1-swe-academya/caper-be/pkg/time/mock"
+	loginThrottleMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/login_throttle/repository/mock"
+	mailMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/mail/repository/mock"
+	userMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/user/repository/mock"
+)
+
+const (
+	email    = "jane@example.com"
+	ip       = "203.0.113.7"
+	emailKey = "email:" + email
+	ipKey    = "ip:" + ip
+)
+
+var now = time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+func newClock(ctrl *gomock.Controller) *timeMock.MockTimeInterface {
+	clock := timeMock.NewMockTimeInterface(ctrl)
+	clock.EXPECT().Now().Return(now).AnyTimes()
+
+	return clock
+}
+
+// expectFailure counts a failure of throttleKey as its attempts-th in the
+// window and expects a lock for lockout when it is not zero
+func expectFailure(
+	loginThrottleRepo *loginThrottleMock.MockLoginThrottleRepository,
+	throttleKey string,
+	attempts int,
+	lockout time.Duration,
+) {
+	loginThrottleRepo.EXPECT().
+		RecordFailure(gomock.Any(), throttleKey, now, now.Add(-time.Hour)).
+		Return(attempts, nil)
+
+	if lockout > 0 {
+		loginThrottleRepo.EXPECT().Lock(gomock.Any(), throttleKey, now.Add(lockout)).Return(nil)
+	}
+}
+
+func TestLoginThrottleService_RecordFailure_AccountSchedule(t *testing.T) {
+	tests := []struct {
+		attempts int
+		lockout  time.Duration
+	}{
+		{attempts: 1, lockout: 0},
+		{attempts: 2, lockout: 0},
+		{attempts: 3, lockout: 2 * time.Second},
+		{attempts: 4, lockout: 4 * time.Second},
+		{attempts: 6, lockout: 30 * time.Minute},
+		{attempts: 7, lockout: time.Hour},
+		{attempts: 8, lockout: 2 * time.Hour},
+		{attempts: 11, lockout: 16 * time.Hour},
+		{attempts: 12, lockout: 24 * time.Hour},
+		{attempts: 20, lockout: 24 * time.Hour},
+		{attempts: 1000, lockout: 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lockout.String(), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			loginThrottleRepo := loginThrottleMock.NewMockLoginThrottleRepository(ctrl)
+			expectFailure(loginThrottleRepo, emailKey, tt.attempts, tt.lockout)
+			expectFailure(loginThrottleRepo, ipKey, 1, 0)
+
+			loginThrottleService := service.NewLoginThrottleService(
+				loginThrottleRepo,
+				userMock.NewMockUserRepository(ctrl),
+				mailMock.NewMockMailService(ctrl),
+				newClock(ctrl),
+			)
+
+			err := loginThrottleService.RecordFailure(context.Background(), email, ip)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestLoginThrottleService_RecordFailure_IPSchedule(t *testing.T) {
+	tests := []struct {
+		attempts int
+		lockout  time.Duration
+	}{
+		{attempts: 9, lockout: 0},
+		{attempts: 10, lockout: 2 * time.Second},
+		{attempts: 11, lockout: 4 * time.Second},
+		{attempts: 19, lockout: 1024 * time.Second},
+		{attempts: 20, lockout: 15 * time.Minute},
+		{attempts: 21, lockout: 30 * time.Minute},
+		{attempts: 40, lockout: 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lockout.String(), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			loginThrottleRepo := loginThrottleMock.NewMockLoginThrottleRepository(ctrl)
+			expectFailure(loginThrottleRepo, emailKey, 1, 0)
+			expectFailure(loginThrottleRepo, ipKey, tt.attempts, tt.lockout)
+
+			loginThrottleService := service.NewLoginThrottleService(
+				loginThrottleRepo,
+				userMock.NewMockUserRepository(ctrl),
+				mailMock.NewMockMailService(ctrl),
+				newClock(ctrl),
+			)
+
+			err := loginThrottleService.RecordFailure(context.Background(), email, ip)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestLoginThrottleService_RecordFailure_MailsFirstLock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	loginThrottleRepo := loginThrottleMock.NewMockLoginThrottleRepository(ctrl)
+	userRepo := userMock.NewMockUserRepository(ctrl)
+	mailService := mailMock.NewMockMailService(ctrl)
+
+	expectFailure(loginThrottleRepo, emailKey, 5, 15*time.Minute)
+	expectFailure(loginThrottleRepo, ipKey, 5, 0)
+
+	user := entity.User{ID: uuid.New(), Name: "Jane Doe", Email: email, Locale: "id"}
+	userRepo.EXPECT().FindByEmail(gomock.Any(), email).Return(user, nil)
+	mailService.EXPECT().
+		Queue(gomock.Any(), email, "id", mail.TemplateAccountLocked, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ string, _ string, data any) error {
+			locked, ok := data.(mail.AccountLockedData)
+			assert.True(t, ok)
+			assert.Equal(t, "Jane Doe", locked.Name)
+			assert.Equal(t, "15m0s", locked.LockedFor)
+			assert.Contains(t, locked.Link, "/forgot-password")
+			return nil
+		})
+
+	loginThrottleService := service.NewLoginThrottleService(loginThrottleRepo, userRepo, mailService, newClock(ctrl))
+
+	err := loginThrottleService.RecordFailure(context.Background(), email, ip)
+	assert.NoError(t, err)
+}
+
+// an unknown email is throttled the same way but never mailed, the response
+// must not tell it apart from a registered one
+func TestLoginThrottleService_RecordFailure_UnknownEmailLock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	loginThrottleRepo := loginThrottleMock.NewMockLoginThrottleRepository(ctrl)
+	userRepo := userMock.NewMockUserRepository(ctrl)
+
+	expectFailure(loginThrottleRepo, emailKey, 5, 15*time.Minute)
+	expectFailure(loginThrottleRepo, ipKey, 5, 0)
+	userRepo.EXPECT().FindByEmail(gomock.Any(), email).Return(entity.User{}, domain.ErrUserNotFound)
+
+	loginThrottleService := service.NewLoginThrottleService(
+		loginThrottleRepo,
+		userRepo,
+		mailMock.NewMockMailService(ctrl),
+		newClock(ctrl),
+	)
+
+	err := loginThrottleService.RecordFailure(context.Background(), email, ip)
+	assert.NoError(t, err)
+}
+
+func TestLoginThrottleService_RecordFailure_MailFailureIgnored(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	loginThrottleRepo := loginThrottleMock.NewMockLoginThrottleRepository(ctrl)
+	userRepo := userMock.NewMockUserRepository(ctrl)
+	mailService := mailMock.NewMockMailService(ctrl)
+
+	expectFailure(loginThrottleRepo, emailKey, 5, 15*time.Minute)
+	expectFailure(loginThrottleRepo, ipKey, 5, 0)
+	userRepo.EXPECT().FindByEmail(gomock.Any(), email).Return(entity.User{Email: email}, nil)
+	mailService.EXPECT().
+		Queue(gomock.Any(), email, gomock.Any(), mail.TemplateAccountLocked, gomock.Any()).
+		Return(errors.New("outbox unavailable"))
+
+	loginThrottleService := service.NewLoginThrottleService(loginThrottleRepo, userRepo, mailService, newClock(ctrl))
+
+	err := loginThrottleService.RecordFailure(context.Background(), email, ip)
+	assert.NoError(t, err)
+}
+
+func TestLoginThrottleService_RecordFailure_RepositoryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	loginThrottleRepo := loginThrottleMock.NewMockLoginThrottleRepository(ctrl)
+
+	repoErr := errors.New("connection reset")
+	loginThrottleRepo.EXPECT().
+		RecordFailure(gomock.Any(), emailKey, now, now.Add(-time.Hour)).
+		Return(0, repoErr)
+
+	loginThrottleService := service.NewLoginThrottleService(
+		loginThrottleRepo,
+		userMock.NewMockUserRepository(ctrl),
+		mailMock.NewMockMailService(ctrl),
+		newClock(ctrl),
+	)
+
+	err := loginThrottleService.RecordFailure(context.Background(), email, ip)
+	assert.ErrorIs(t, err, repoErr)
+}
+
+func TestLoginThrottleService_Check(t *testing.T) {
+	tests := []struct {
+		name        string
+		lockedUntil time.Time
+		wantErr     error
+	}{
+		{
+			name: "not locked",
+		},
+		{
+			name:        "locked",
+			lockedUntil: now.Add(time.Minute),
+			wantErr:     domain.ErrTooManyLoginAttempts,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			loginThrottleRepo := loginThrottleMock.NewMockLoginThrottleRepository(ctrl)
+			loginThrottleRepo.EXPECT().
+				FindLockedUntil(gomock.Any(), now, emailKey, ipKey).
+				Return(tt.lockedUntil, nil)
+
+			loginThrottleService := service.NewLoginThrottleService(
+				loginThrottleRepo,
+				userMock.NewMockUserRepository(ctrl),
+				mailMock.NewMockMailService(ctrl),
+				newClock(ctrl),
+			)
+
+			err := loginThrottleService.Check(context.Background(), email, ip)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+// a successful login only clears the account, the ip keeps its counter
+func TestLoginThrottleService_RecordSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	loginThrottleRepo := loginThrottleMock.NewMockLoginThrottleRepository(ctrl)
+	loginThrottleRepo.EXPECT().Reset(gomock.Any(), emailKey).Return(nil)
+
+	loginThrottleService := service.NewLoginThrottleService(
+		loginThrottleRepo,
+		userMock.NewMockUserRepository(ctrl),
+		mailMock.NewMockMailService(ctrl),
+		newClock(ctrl),
+	)
+
+	err := loginThrottleService.RecordSuccess(context.Background(), email)
+	assert.NoError(t, err)
+}
+
+func TestLoginThrottleService_Unlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	loginThrottleRepo := loginThrottleMock.NewMockLoginThrottleRepository(ctrl)
+	userRepo := userMock.NewMockUserRepository(ctrl)
+
+	userID := uuid.New()
+	userRepo.EXPECT().FindByID(gomock.Any(), userID).Return(entity.User{ID: userID, Email: email}, nil)
+	loginThrottleRepo.EXPECT().Reset(gomock.Any(), emailKey).Return(nil)
+
+	loginThrottleService := service.NewLoginThrottleService(
+		loginThrottleRepo,
+		userRepo,
+		mailMock.NewMockMailService(ctrl),
+		newClock(ctrl),
+	)
+
+	err := loginThrottleService.Unlock(context.Background(), userID)
+	assert.NoError(t, err)
+}
+
+func TestLoginThrottleService_DeleteExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	loginThrottleRepo := loginThrottleMock.NewMockLoginThrottleRepository(ctrl)
+	loginThrottleRepo.EXPECT().DeleteExpired(gomock.Any(), now.Add(-time.Hour)).Return(nil)
+
+	loginThrottleService := service.NewLoginThrottleService(
+		loginThrottleRepo,
+		userMock.NewMockUserRepository(ctrl),
+		mailMock.NewMockMailService(ctrl),
+		newClock(ctrl),
+	)
+
+	err := loginThrottleService.DeleteExpired(context.Background())
+	assert.NoError(t, err)
+}
@@ -0,0 +1,155 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/contracts/magic_link_contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=domain/contracts/magic_link_contracts.go -destination=tests/unit/magic_link/repository/mock/magic_link_repository_mock.go -package=repository_mock
+//
+
+// Package repository_mock is a generated GoMock package.
+package repository_mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	dto "github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	entity "github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMagicLinkRepository is a mock of MagicLinkRepository interface.
+type MockMagicLinkRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMagicLinkRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockMagicLinkRepositoryMockRecorder is the mock recorder for MockMagicLinkRepository.
+type MockMagicLinkRepositoryMockRecorder struct {
+	mock *MockMagicLinkRepository
+}
+
+// NewMockMagicLinkRepository creates a new mock instance.
+func NewMockMagicLinkRepository(ctrl *gomock.Controller) *MockMagicLinkRepository {
+	mock := &MockMagicLinkRepository{ctrl: ctrl}
+	mock.recorder = &MockMagicLinkRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMagicLinkRepository) EXPECT() *MockMagicLinkRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockMagicLinkRepository) Consume(ctx context.Context, tokenHash string, now time.Time) (entity.MagicLinkToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, tokenHash, now)
+	ret0, _ := ret[0].(entity.MagicLinkToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockMagicLinkRepositoryMockRecorder) Consume(ctx, tokenHash, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockMagicLinkRepository)(nil).Consume), ctx, tokenHash, now)
+}
+
+// Create mocks base method.
+func (m *MockMagicLinkRepository) Create(ctx context.Context, token *entity.MagicLinkToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockMagicLinkRepositoryMockRecorder) Create(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockMagicLinkRepository)(nil).Create), ctx, token)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockMagicLinkRepository) DeleteExpired(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockMagicLinkRepositoryMockRecorder) DeleteExpired(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockMagicLinkRepository)(nil).DeleteExpired), ctx)
+}
+
+// InvalidateByUserID mocks base method.
+func (m *MockMagicLinkRepository) InvalidateByUserID(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateByUserID", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateByUserID indicates an expected call of InvalidateByUserID.
+func (mr *MockMagicLinkRepositoryMockRecorder) InvalidateByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateByUserID", reflect.TypeOf((*MockMagicLinkRepository)(nil).InvalidateByUserID), ctx, userID)
+}
+
+// MockMagicLinkService is a mock of MagicLinkService interface.
+type MockMagicLinkService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMagicLinkServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockMagicLinkServiceMockRecorder is the mock recorder for MockMagicLinkService.
+type MockMagicLinkServiceMockRecorder struct {
+	mock *MockMagicLinkService
+}
+
+// NewMockMagicLinkService creates a new mock instance.
+func NewMockMagicLinkService(ctrl *gomock.Controller) *MockMagicLinkService {
+	mock := &MockMagicLinkService{ctrl: ctrl}
+	mock.recorder = &MockMagicLinkServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMagicLinkService) EXPECT() *MockMagicLinkServiceMockRecorder {
+	return m.recorder
+}
+
+// Login mocks base method.
+func (m *MockMagicLinkService) Login(ctx context.Context, req dto.MagicLinkLoginRequest) (dto.LoginResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", ctx, req)
+	ret0, _ := ret[0].(dto.LoginResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockMagicLinkServiceMockRecorder) Login(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockMagicLinkService)(nil).Login), ctx, req)
+}
+
+// Send mocks base method.
+func (m *MockMagicLinkService) Send(ctx context.Context, req dto.MagicLinkRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockMagicLinkServiceMockRecorder) Send(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockMagicLinkService)(nil).Send), ctx, req)
+}
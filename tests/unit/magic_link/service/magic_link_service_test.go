@@ -0,0 +1,313 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/app/magic_link/service"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/mail"
+	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/token"
+	uuidPkg "github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
+	loginThrottleMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/login_throttle/repository/mock"
+	magicLinkMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/magic_link/repository/mock"
+	mailMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/mail/repository/mock"
+	mfaMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/mfa/repository/mock"
+	userMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/user/repository/mock"
+)
+
+const (
+	plainToken = "bWFnaWMtbGluay10b2tlbg"
+	ipAddress  = "203.0.113.7"
+	userAgent  = "Mozilla/5.0 (X11; Linux x86_64) Firefox/131.0"
+)
+
+var user = entity.User{
+	ID:     uuid.MustParse("0192b7a4-5c1e-7d3a-9f21-6b8e4c2d1a07"),
+	Name:   "Jane Doe",
+	Email:  "jane@example.com",
+	Locale: "id",
+}
+
+// expectAudit expects one audit log for user with the given action and
+// actor, and returns its decoded metadata once it is written
+func expectAudit(userRepo *userMock.MockUserRepository, action string, actorID uuid.NullUUID) map[string]any {
+	metadata := make(map[string]any)
+
+	userRepo.EXPECT().
+		CreateAuditLog(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, audit *entity.AuditLog) error {
+			if audit.Action != action || audit.ActorID != actorID || audit.SubjectID.UUID != user.ID {
+				return errors.New("unexpected audit log")
+			}
+
+			return json.Unmarshal(audit.Metadata, &metadata)
+		})
+
+	return metadata
+}
+
+func TestMagicLinkService_Send(t *testing.T) {
+	tests := []struct {
+		name           string
+		req            dto.MagicLinkRequest
+		wantIPAddress  sql.NullString
+		wantDeviceHash sql.NullString
+	}{
+		{
+			name: "unbound",
+			req:  dto.MagicLinkRequest{Email: " Jane@Example.com ", IPAddress: ipAddress, UserAgent: userAgent},
+		},
+		{
+			name:          "bound to the ip address",
+			req:           dto.MagicLinkRequest{Email: user.Email, BindIP: true, IPAddress: ipAddress, UserAgent: userAgent},
+			wantIPAddress: sql.NullString{String: ipAddress, Valid: true},
+		},
+		{
+			name:           "bound to the device",
+			req:            dto.MagicLinkRequest{Email: user.Email, BindDevice: true, IPAddress: ipAddress, UserAgent: userAgent},
+			wantDeviceHash: sql.NullString{String: token.Token.Hash(userAgent), Valid: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			magicLinkRepo := magicLinkMock.NewMockMagicLinkRepository(ctrl)
+			userRepo := userMock.NewMockUserRepository(ctrl)
+			loginThrottleService := loginThrottleMock.NewMockLoginThrottleService(ctrl)
+			mailService := mailMock.NewMockMailService(ctrl)
+
+			var created entity.MagicLinkToken
+
+			loginThrottleService.EXPECT().Check(gomock.Any(), user.Email, ipAddress).Return(nil)
+			userRepo.EXPECT().FindByEmail(gomock.Any(), user.Email).Return(user, nil)
+			magicLinkRepo.EXPECT().InvalidateByUserID(gomock.Any(), user.ID).Return(nil)
+			magicLinkRepo.EXPECT().
+				Create(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, magicLink *entity.MagicLinkToken) error {
+					created = *magicLink
+					return nil
+				})
+			mailService.EXPECT().
+				Queue(gomock.Any(), user.Email, user.Locale, mail.TemplateMagicLink, gomock.Any()).
+				Return(nil)
+			metadata := expectAudit(userRepo, entity.AuditActionMagicLinkSent, uuid.NullUUID{})
+
+			magicLinkService := service.NewMagicLinkService(
+				magicLinkRepo,
+				userRepo,
+				mfaMock.NewMockMFAService(ctrl),
+				loginThrottleService,
+				validator.Validator,
+				uuidPkg.UUID,
+				token.Token,
+				mailService,
+				timePkg.Time,
+			)
+
+			require.NoError(t, magicLinkService.Send(context.Background(), tt.req))
+			assert.Equal(t, user.ID, created.UserID)
+			assert.Equal(t, tt.wantIPAddress, created.IPAddress)
+			assert.Equal(t, tt.wantDeviceHash, created.UserAgentHash)
+			assert.Equal(t, ipAddress, metadata["ip_address"])
+		})
+	}
+}
+
+// an unknown email looks like a sent link and leaves nothing behind
+func TestMagicLinkService_Send_UnknownEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userRepo := userMock.NewMockUserRepository(ctrl)
+	loginThrottleService := loginThrottleMock.NewMockLoginThrottleService(ctrl)
+
+	loginThrottleService.EXPECT().Check(gomock.Any(), "john@example.com", ipAddress).Return(nil)
+	userRepo.EXPECT().FindByEmail(gomock.Any(), "john@example.com").Return(entity.User{}, domain.ErrUserNotFound)
+
+	magicLinkService := service.NewMagicLinkService(
+		magicLinkMock.NewMockMagicLinkRepository(ctrl),
+		userRepo,
+		mfaMock.NewMockMFAService(ctrl),
+		loginThrottleService,
+		validator.Validator,
+		uuidPkg.UUID,
+		token.Token,
+		mailMock.NewMockMailService(ctrl),
+		timePkg.Time,
+	)
+
+	err := magicLinkService.Send(context.Background(), dto.MagicLinkRequest{
+		Email:     "john@example.com",
+		IPAddress: ipAddress,
+	})
+	assert.NoError(t, err)
+}
+
+func TestMagicLinkService_Login(t *testing.T) {
+	tests := []struct {
+		name          string
+		boundIP       sql.NullString
+		boundDevice   sql.NullString
+		req           dto.MagicLinkLoginRequest
+		wantErr       error
+		wantRejected  bool
+		mfaRequired   bool
+		wantSucceeded bool
+	}{
+		{
+			name:          "unbound",
+			req:           dto.MagicLinkLoginRequest{Token: plainToken, IPAddress: "198.51.100.20", UserAgent: "curl/8.0"},
+			wantSucceeded: true,
+		},
+		{
+			name:          "same ip address",
+			boundIP:       sql.NullString{String: ipAddress, Valid: true},
+			req:           dto.MagicLinkLoginRequest{Token: plainToken, IPAddress: ipAddress, UserAgent: userAgent},
+			wantSucceeded: true,
+		},
+		{
+			name:         "other ip address",
+			boundIP:      sql.NullString{String: ipAddress, Valid: true},
+			req:          dto.MagicLinkLoginRequest{Token: plainToken, IPAddress: "198.51.100.20", UserAgent: userAgent},
+			wantErr:      domain.ErrInvalidMagicLinkToken,
+			wantRejected: true,
+		},
+		{
+			name:          "same device",
+			boundDevice:   sql.NullString{String: token.Token.Hash(userAgent), Valid: true},
+			req:           dto.MagicLinkLoginRequest{Token: plainToken, IPAddress: "198.51.100.20", UserAgent: userAgent},
+			wantSucceeded: true,
+		},
+		{
+			name:         "other device",
+			boundDevice:  sql.NullString{String: token.Token.Hash(userAgent), Valid: true},
+			req:          dto.MagicLinkLoginRequest{Token: plainToken, IPAddress: ipAddress, UserAgent: "curl/8.0"},
+			wantErr:      domain.ErrInvalidMagicLinkToken,
+			wantRejected: true,
+		},
+		{
+			name:         "same device from another ip address",
+			boundIP:      sql.NullString{String: ipAddress, Valid: true},
+			boundDevice:  sql.NullString{String: token.Token.Hash(userAgent), Valid: true},
+			req:          dto.MagicLinkLoginRequest{Token: plainToken, IPAddress: "198.51.100.20", UserAgent: userAgent},
+			wantErr:      domain.ErrInvalidMagicLinkToken,
+			wantRejected: true,
+		},
+		{
+			// the lockout is only cleared once the second factor is verified
+			name:        "second factor pending",
+			req:         dto.MagicLinkLoginRequest{Token: plainToken, IPAddress: ipAddress, UserAgent: userAgent},
+			mfaRequired: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			magicLinkRepo := magicLinkMock.NewMockMagicLinkRepository(ctrl)
+			userRepo := userMock.NewMockUserRepository(ctrl)
+			mfaService := mfaMock.NewMockMFAService(ctrl)
+			loginThrottleService := loginThrottleMock.NewMockLoginThrottleService(ctrl)
+
+			magicLink := entity.MagicLinkToken{
+				ID:            uuid.New(),
+				UserID:        user.ID,
+				TokenHash:     token.Token.Hash(plainToken),
+				IPAddress:     tt.boundIP,
+				UserAgentHash: tt.boundDevice,
+				ExpiresAt:     time.Now().Add(15 * time.Minute),
+			}
+
+			magicLinkRepo.EXPECT().Consume(gomock.Any(), magicLink.TokenHash, gomock.Any()).Return(magicLink, nil)
+			userRepo.EXPECT().FindByID(gomock.Any(), user.ID).Return(user, nil)
+			loginThrottleService.EXPECT().Check(gomock.Any(), user.Email, tt.req.IPAddress).Return(nil)
+
+			var metadata map[string]any
+			if tt.wantRejected {
+				metadata = expectAudit(userRepo, entity.AuditActionMagicLinkRejected, uuid.NullUUID{})
+				loginThrottleService.EXPECT().RecordFailure(gomock.Any(), user.Email, tt.req.IPAddress).Return(nil)
+			} else {
+				mfaService.EXPECT().
+					SignIn(gomock.Any(), user, []string{jwt.AMRMagicLink}).
+					Return(dto.LoginResponse{MFARequired: tt.mfaRequired}, nil)
+				metadata = expectAudit(
+					userRepo,
+					entity.AuditActionMagicLinkLogin,
+					uuid.NullUUID{UUID: user.ID, Valid: true},
+				)
+			}
+
+			if tt.wantSucceeded {
+				loginThrottleService.EXPECT().RecordSuccess(gomock.Any(), user.Email).Return(nil)
+			}
+
+			magicLinkService := service.NewMagicLinkService(
+				magicLinkRepo,
+				userRepo,
+				mfaService,
+				loginThrottleService,
+				validator.Validator,
+				uuidPkg.UUID,
+				token.Token,
+				mailMock.NewMockMailService(ctrl),
+				timePkg.Time,
+			)
+
+			res, err := magicLinkService.Login(context.Background(), tt.req)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.mfaRequired, res.MFARequired)
+			assert.Equal(t, tt.req.IPAddress, metadata["ip_address"])
+			assert.Equal(t, magicLink.ID.String(), metadata["magic_link_id"])
+		})
+	}
+}
+
+// the audit log is best effort, a failed write doesn't refuse the sign in
+func TestMagicLinkService_Login_AuditError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	magicLinkRepo := magicLinkMock.NewMockMagicLinkRepository(ctrl)
+	userRepo := userMock.NewMockUserRepository(ctrl)
+	mfaService := mfaMock.NewMockMFAService(ctrl)
+	loginThrottleService := loginThrottleMock.NewMockLoginThrottleService(ctrl)
+
+	magicLinkRepo.EXPECT().
+		Consume(gomock.Any(), token.Token.Hash(plainToken), gomock.Any()).
+		Return(entity.MagicLinkToken{ID: uuid.New(), UserID: user.ID}, nil)
+	userRepo.EXPECT().FindByID(gomock.Any(), user.ID).Return(user, nil)
+	loginThrottleService.EXPECT().Check(gomock.Any(), user.Email, ipAddress).Return(nil)
+	mfaService.EXPECT().SignIn(gomock.Any(), user, []string{jwt.AMRMagicLink}).Return(dto.LoginResponse{}, nil)
+	userRepo.EXPECT().CreateAuditLog(gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+	loginThrottleService.EXPECT().RecordSuccess(gomock.Any(), user.Email).Return(nil)
+
+	magicLinkService := service.NewMagicLinkService(
+		magicLinkRepo,
+		userRepo,
+		mfaService,
+		loginThrottleService,
+		validator.Validator,
+		uuidPkg.UUID,
+		token.Token,
+		mailMock.NewMockMailService(ctrl),
+		timePkg.Time,
+	)
+
+	_, err := magicLinkService.Login(context.Background(), dto.MagicLinkLoginRequest{
+		Token:     plainToken,
+		IPAddress: ipAddress,
+	})
+	assert.NoError(t, err)
+}
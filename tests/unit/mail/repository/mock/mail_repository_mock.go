@@ -0,0 +1,195 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/contracts/mail_contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=domain/contracts/mail_contracts.go -destination=tests/unit/mail/repository/mock/mail_repository_mock.go -package=repository_mock
+//
+
+// Package repository_mock is a generated GoMock package.
+package repository_mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	entity "github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMailOutboxRepository is a mock of MailOutboxRepository interface.
+type MockMailOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMailOutboxRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockMailOutboxRepositoryMockRecorder is the mock recorder for MockMailOutboxRepository.
+type MockMailOutboxRepositoryMockRecorder struct {
+	mock *MockMailOutboxRepository
+}
+
+// NewMockMailOutboxRepository creates a new mock instance.
+func NewMockMailOutboxRepository(ctrl *gomock.Controller) *MockMailOutboxRepository {
+	mock := &MockMailOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockMailOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMailOutboxRepository) EXPECT() *MockMailOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockMailOutboxRepository) Claim(ctx context.Context, limit int, now, leaseUntil time.Time) ([]entity.MailOutbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, limit, now, leaseUntil)
+	ret0, _ := ret[0].([]entity.MailOutbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockMailOutboxRepositoryMockRecorder) Claim(ctx, limit, now, leaseUntil any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockMailOutboxRepository)(nil).Claim), ctx, limit, now, leaseUntil)
+}
+
+// Create mocks base method.
+func (m *MockMailOutboxRepository) Create(ctx context.Context, mail *entity.MailOutbox) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, mail)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockMailOutboxRepositoryMockRecorder) Create(ctx, mail any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockMailOutboxRepository)(nil).Create), ctx, mail)
+}
+
+// DeleteFinished mocks base method.
+func (m *MockMailOutboxRepository) DeleteFinished(ctx context.Context, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFinished", ctx, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFinished indicates an expected call of DeleteFinished.
+func (mr *MockMailOutboxRepositoryMockRecorder) DeleteFinished(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFinished", reflect.TypeOf((*MockMailOutboxRepository)(nil).DeleteFinished), ctx, before)
+}
+
+// MarkFailed mocks base method.
+func (m *MockMailOutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", ctx, id, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockMailOutboxRepositoryMockRecorder) MarkFailed(ctx, id, lastError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockMailOutboxRepository)(nil).MarkFailed), ctx, id, lastError)
+}
+
+// MarkRetry mocks base method.
+func (m *MockMailOutboxRepository) MarkRetry(ctx context.Context, id uuid.UUID, lastError string, nextAttemptAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRetry", ctx, id, lastError, nextAttemptAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRetry indicates an expected call of MarkRetry.
+func (mr *MockMailOutboxRepositoryMockRecorder) MarkRetry(ctx, id, lastError, nextAttemptAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRetry", reflect.TypeOf((*MockMailOutboxRepository)(nil).MarkRetry), ctx, id, lastError, nextAttemptAt)
+}
+
+// MarkSent mocks base method.
+func (m *MockMailOutboxRepository) MarkSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSent", ctx, id, sentAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSent indicates an expected call of MarkSent.
+func (mr *MockMailOutboxRepositoryMockRecorder) MarkSent(ctx, id, sentAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSent", reflect.TypeOf((*MockMailOutboxRepository)(nil).MarkSent), ctx, id, sentAt)
+}
+
+// MockMailService is a mock of MailService interface.
+type MockMailService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMailServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockMailServiceMockRecorder is the mock recorder for MockMailService.
+type MockMailServiceMockRecorder struct {
+	mock *MockMailService
+}
+
+// NewMockMailService creates a new mock instance.
+func NewMockMailService(ctrl *gomock.Controller) *MockMailService {
+	mock := &MockMailService{ctrl: ctrl}
+	mock.recorder = &MockMailServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMailService) EXPECT() *MockMailServiceMockRecorder {
+	return m.recorder
+}
+
+// ProcessOutbox mocks base method.
+func (m *MockMailService) ProcessOutbox(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessOutbox", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessOutbox indicates an expected call of ProcessOutbox.
+func (mr *MockMailServiceMockRecorder) ProcessOutbox(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessOutbox", reflect.TypeOf((*MockMailService)(nil).ProcessOutbox), ctx)
+}
+
+// PurgeFinished mocks base method.
+func (m *MockMailService) PurgeFinished(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeFinished", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeFinished indicates an expected call of PurgeFinished.
+func (mr *MockMailServiceMockRecorder) PurgeFinished(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeFinished", reflect.TypeOf((*MockMailService)(nil).PurgeFinished), ctx)
+}
+
+// Queue mocks base method.
+func (m *MockMailService) Queue(ctx context.Context, to, locale, template string, data any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Queue", ctx, to, locale, template, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Queue indicates an expected call of Queue.
+func (mr *MockMailServiceMockRecorder) Queue(ctx, to, locale, template, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Queue", reflect.TypeOf((*MockMailService)(nil).Queue), ctx, to, locale, template, data)
+}
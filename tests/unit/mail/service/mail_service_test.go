@@ -0,0 +1,314 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/app/mail/service"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/mail"
+	mailPkgMock "github.com/kelompok1-swe-academya/caper-be/pkg/mail/mock"
+	timeMock "github.com/kelompok1-swe-academya/caper-be/pkg/time/mock"
+	uuidPkg "github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+	mailMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/mail/repository/mock"
+)
+
+var (
+	now         = time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	errSMTPBusy = errors.New("421 service not available")
+)
+
+func newClock(ctrl *gomock.Controller) *timeMock.MockTimeInterface {
+	clock := timeMock.NewMockTimeInterface(ctrl)
+	clock.EXPECT().Now().Return(now).AnyTimes()
+	clock.EXPECT().Add(gomock.Any()).DoAndReturn(now.Add).AnyTimes()
+
+	return clock
+}
+
+// claimInTurn hands out mails one claim at a time the way the repository
+// does, already counting the attempt that is about to be made, and reports
+// the outbox empty afterwards
+func claimInTurn(mailOutboxRepo *mailMock.MockMailOutboxRepository, mails ...entity.MailOutbox) {
+	calls := make([]any, 0, len(mails)+1)
+	for _, outbox := range mails {
+		calls = append(calls, mailOutboxRepo.EXPECT().
+			Claim(gomock.Any(), 1, now, now.Add(time.Minute)).
+			Return([]entity.MailOutbox{outbox}, nil))
+	}
+
+	calls = append(calls, mailOutboxRepo.EXPECT().
+		Claim(gomock.Any(), 1, now, now.Add(time.Minute)).
+		Return(nil, nil))
+
+	gomock.InOrder(calls...)
+}
+
+func newOutbox(attempts int) entity.MailOutbox {
+	return entity.MailOutbox{
+		ID:        uuid.New(),
+		Recipient: "jane@example.com",
+		Subject:   "Subject",
+		TextBody:  "Text",
+		HTMLBody:  "<p>HTML</p>",
+		Status:    entity.MailStatusPending,
+		Attempts:  attempts,
+	}
+}
+
+func TestMailService_Queue(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  bool
+	}{
+		{
+			name:     "rendered",
+			template: mail.TemplateAccountLocked,
+		},
+		{
+			// a template that doesn't exist never reaches the outbox
+			name:     "unknown template",
+			template: "unknown",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mailOutboxRepo := mailMock.NewMockMailOutboxRepository(ctrl)
+			mailService := service.NewMailService(
+				mailOutboxRepo,
+				mail.NewMemoryMailer(),
+				mail.Template,
+				uuidPkg.UUID,
+				newClock(ctrl),
+			)
+
+			if !tt.wantErr {
+				mailOutboxRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, outbox *entity.MailOutbox) error {
+						assert.NotEqual(t, uuid.Nil, outbox.ID)
+						assert.Equal(t, "jane@example.com", outbox.Recipient)
+						assert.NotEmpty(t, outbox.Subject)
+						assert.Contains(t, outbox.TextBody, "https://example.com/forgot-password")
+						assert.Equal(t, entity.MailStatusPending, outbox.Status)
+						assert.Equal(t, now, outbox.NextAttemptAt)
+						return nil
+					})
+			}
+
+			err := mailService.Queue(
+				context.Background(),
+				"jane@example.com",
+				"",
+				tt.template,
+				mail.AccountLockedData{
+					Name:      "Jane Doe",
+					LockedFor: "15m0s",
+					Link:      "https://example.com/forgot-password",
+				},
+			)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestMailService_ProcessOutbox_Sent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mailOutboxRepo := mailMock.NewMockMailOutboxRepository(ctrl)
+	mailer := mail.NewMemoryMailer()
+	mailService := service.NewMailService(mailOutboxRepo, mailer, mail.Template, uuidPkg.UUID, newClock(ctrl))
+
+	first, second := newOutbox(1), newOutbox(3)
+	claimInTurn(mailOutboxRepo, first, second)
+	mailOutboxRepo.EXPECT().MarkSent(gomock.Any(), first.ID, now).Return(nil)
+	mailOutboxRepo.EXPECT().MarkSent(gomock.Any(), second.ID, now).Return(nil)
+
+	err := mailService.ProcessOutbox(context.Background())
+	assert.NoError(t, err)
+
+	messages := mailer.Messages()
+	require.Len(t, messages, 2)
+	assert.Equal(t, []string{first.Recipient}, messages[0].To)
+	assert.Equal(t, first.Subject, messages[0].Subject)
+	assert.Equal(t, first.TextBody, messages[0].Text)
+	assert.Equal(t, first.HTMLBody, messages[0].HTML)
+}
+
+// a run stops after one batch even when more mails are due
+func TestMailService_ProcessOutbox_BatchSize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mailOutboxRepo := mailMock.NewMockMailOutboxRepository(ctrl)
+	mailer := mail.NewMemoryMailer()
+	mailService := service.NewMailService(mailOutboxRepo, mailer, mail.Template, uuidPkg.UUID, newClock(ctrl))
+
+	mailOutboxRepo.EXPECT().
+		Claim(gomock.Any(), 1, now, now.Add(time.Minute)).
+		DoAndReturn(func(context.Context, int, time.Time, time.Time) ([]entity.MailOutbox, error) {
+			return []entity.MailOutbox{newOutbox(1)}, nil
+		}).
+		Times(20)
+	mailOutboxRepo.EXPECT().MarkSent(gomock.Any(), gomock.Any(), now).Return(nil).Times(20)
+
+	err := mailService.ProcessOutbox(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, mailer.Messages(), 20)
+}
+
+func TestMailService_ProcessOutbox_Failed(t *testing.T) {
+	tests := []struct {
+		attempts int
+		// backoff is when a retry is due, zero gives the mail up
+		backoff time.Duration
+	}{
+		{attempts: 1, backoff: 30 * time.Second},
+		{attempts: 2, backoff: time.Minute},
+		{attempts: 3, backoff: 2 * time.Minute},
+		{attempts: 4, backoff: 4 * time.Minute},
+		{attempts: 5, backoff: 8 * time.Minute},
+		{attempts: 6},
+		{attempts: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run("attempt "+strconv.Itoa(tt.attempts), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mailOutboxRepo := mailMock.NewMockMailOutboxRepository(ctrl)
+			mailer := mailPkgMock.NewMockMailInterface(ctrl)
+			mailService := service.NewMailService(mailOutboxRepo, mailer, mail.Template, uuidPkg.UUID, newClock(ctrl))
+
+			outbox := newOutbox(tt.attempts)
+			claimInTurn(mailOutboxRepo, outbox)
+			mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errSMTPBusy)
+
+			if tt.backoff > 0 {
+				mailOutboxRepo.EXPECT().
+					MarkRetry(gomock.Any(), outbox.ID, errSMTPBusy.Error(), now.Add(tt.backoff)).
+					Return(nil)
+			} else {
+				mailOutboxRepo.EXPECT().MarkFailed(gomock.Any(), outbox.ID, errSMTPBusy.Error()).Return(nil)
+			}
+
+			err := mailService.ProcessOutbox(context.Background())
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// one mail failing doesn't hold back the rest of the batch
+func TestMailService_ProcessOutbox_FailureKeepsBatchGoing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mailOutboxRepo := mailMock.NewMockMailOutboxRepository(ctrl)
+	mailer := mailPkgMock.NewMockMailInterface(ctrl)
+	mailService := service.NewMailService(mailOutboxRepo, mailer, mail.Template, uuidPkg.UUID, newClock(ctrl))
+
+	failing, sent := newOutbox(1), newOutbox(1)
+	claimInTurn(mailOutboxRepo, failing, sent)
+
+	gomock.InOrder(
+		mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errSMTPBusy),
+		mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil),
+	)
+	mailOutboxRepo.EXPECT().
+		MarkRetry(gomock.Any(), failing.ID, errSMTPBusy.Error(), now.Add(30*time.Second)).
+		Return(nil)
+	mailOutboxRepo.EXPECT().MarkSent(gomock.Any(), sent.ID, now).Return(nil)
+
+	err := mailService.ProcessOutbox(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestMailService_ProcessOutbox_ClaimError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mailOutboxRepo := mailMock.NewMockMailOutboxRepository(ctrl)
+	mailer := mail.NewMemoryMailer()
+	mailService := service.NewMailService(mailOutboxRepo, mailer, mail.Template, uuidPkg.UUID, newClock(ctrl))
+
+	claimErr := errors.New("connection reset")
+	mailOutboxRepo.EXPECT().Claim(gomock.Any(), 1, now, now.Add(time.Minute)).Return(nil, claimErr)
+
+	err := mailService.ProcessOutbox(context.Background())
+	assert.ErrorIs(t, err, claimErr)
+	assert.Empty(t, mailer.Messages())
+}
+
+// a shutdown stops the batch before the next claim, nothing is left leased
+func TestMailService_ProcessOutbox_Canceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mailOutboxRepo := mailMock.NewMockMailOutboxRepository(ctrl)
+	mailer := mail.NewMemoryMailer()
+	mailService := service.NewMailService(mailOutboxRepo, mailer, mail.Template, uuidPkg.UUID, newClock(ctrl))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	first := newOutbox(1)
+	mailOutboxRepo.EXPECT().
+		Claim(gomock.Any(), 1, now, now.Add(time.Minute)).
+		Return([]entity.MailOutbox{first}, nil)
+	mailOutboxRepo.EXPECT().
+		MarkSent(gomock.Any(), first.ID, now).
+		DoAndReturn(func(context.Context, uuid.UUID, time.Time) error {
+			cancel()
+			return nil
+		})
+
+	err := mailService.ProcessOutbox(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, mailer.Messages(), 1)
+}
+
+func TestMailService_PurgeFinished(t *testing.T) {
+	retention := env.AppEnv.MailOutboxRetention
+	t.Cleanup(func() {
+		env.AppEnv.MailOutboxRetention = retention
+	})
+
+	tests := []struct {
+		name      string
+		retention time.Duration
+	}{
+		{
+			name:      "retention",
+			retention: 720 * time.Hour,
+		},
+		{
+			name:      "no retention keeps every mail",
+			retention: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env.AppEnv.MailOutboxRetention = tt.retention
+
+			ctrl := gomock.NewController(t)
+			mailOutboxRepo := mailMock.NewMockMailOutboxRepository(ctrl)
+			mailService := service.NewMailService(
+				mailOutboxRepo,
+				mail.NewMemoryMailer(),
+				mail.Template,
+				uuidPkg.UUID,
+				newClock(ctrl),
+			)
+
+			if tt.retention > 0 {
+				mailOutboxRepo.EXPECT().DeleteFinished(gomock.Any(), now.Add(-tt.retention)).Return(nil)
+			}
+
+			err := mailService.PurgeFinished(context.Background())
+			assert.NoError(t, err)
+		})
+	}
+}
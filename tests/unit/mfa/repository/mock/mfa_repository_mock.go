@@ -0,0 +1,343 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/contracts/mfa_contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=domain/contracts/mfa_contracts.go -destination=tests/unit/mfa/repository/mock/mfa_repository_mock.go -package=repository_mock
+//
+
+// Package repository_mock is a generated GoMock package.
+package repository_mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	dto "github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	entity "github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMFARepository is a mock of MFARepository interface.
+type MockMFARepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMFARepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockMFARepositoryMockRecorder is the mock recorder for MockMFARepository.
+type MockMFARepositoryMockRecorder struct {
+	mock *MockMFARepository
+}
+
+// NewMockMFARepository creates a new mock instance.
+func NewMockMFARepository(ctrl *gomock.Controller) *MockMFARepository {
+	mock := &MockMFARepository{ctrl: ctrl}
+	mock.recorder = &MockMFARepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMFARepository) EXPECT() *MockMFARepositoryMockRecorder {
+	return m.recorder
+}
+
+// ConfirmTotp mocks base method.
+func (m *MockMFARepository) ConfirmTotp(ctx context.Context, userID uuid.UUID, step int64, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTotp", ctx, userID, step, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmTotp indicates an expected call of ConfirmTotp.
+func (mr *MockMFARepositoryMockRecorder) ConfirmTotp(ctx, userID, step, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTotp", reflect.TypeOf((*MockMFARepository)(nil).ConfirmTotp), ctx, userID, step, now)
+}
+
+// CreateChallenge mocks base method.
+func (m *MockMFARepository) CreateChallenge(ctx context.Context, challenge *entity.MFAChallenge) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChallenge", ctx, challenge)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateChallenge indicates an expected call of CreateChallenge.
+func (mr *MockMFARepositoryMockRecorder) CreateChallenge(ctx, challenge any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChallenge", reflect.TypeOf((*MockMFARepository)(nil).CreateChallenge), ctx, challenge)
+}
+
+// DeleteChallenge mocks base method.
+func (m *MockMFARepository) DeleteChallenge(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChallenge", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteChallenge indicates an expected call of DeleteChallenge.
+func (mr *MockMFARepositoryMockRecorder) DeleteChallenge(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChallenge", reflect.TypeOf((*MockMFARepository)(nil).DeleteChallenge), ctx, id)
+}
+
+// DeleteExpiredChallenges mocks base method.
+func (m *MockMFARepository) DeleteExpiredChallenges(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredChallenges", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredChallenges indicates an expected call of DeleteExpiredChallenges.
+func (mr *MockMFARepositoryMockRecorder) DeleteExpiredChallenges(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredChallenges", reflect.TypeOf((*MockMFARepository)(nil).DeleteExpiredChallenges), ctx)
+}
+
+// DeleteTotp mocks base method.
+func (m *MockMFARepository) DeleteTotp(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTotp", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTotp indicates an expected call of DeleteTotp.
+func (mr *MockMFARepositoryMockRecorder) DeleteTotp(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTotp", reflect.TypeOf((*MockMFARepository)(nil).DeleteTotp), ctx, userID)
+}
+
+// FindChallenge mocks base method.
+func (m *MockMFARepository) FindChallenge(ctx context.Context, tokenHash string, now time.Time) (entity.MFAChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindChallenge", ctx, tokenHash, now)
+	ret0, _ := ret[0].(entity.MFAChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindChallenge indicates an expected call of FindChallenge.
+func (mr *MockMFARepositoryMockRecorder) FindChallenge(ctx, tokenHash, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindChallenge", reflect.TypeOf((*MockMFARepository)(nil).FindChallenge), ctx, tokenHash, now)
+}
+
+// FindTotp mocks base method.
+func (m *MockMFARepository) FindTotp(ctx context.Context, userID uuid.UUID) (entity.UserTotp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTotp", ctx, userID)
+	ret0, _ := ret[0].(entity.UserTotp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTotp indicates an expected call of FindTotp.
+func (mr *MockMFARepositoryMockRecorder) FindTotp(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTotp", reflect.TypeOf((*MockMFARepository)(nil).FindTotp), ctx, userID)
+}
+
+// IncrementChallengeAttempts mocks base method.
+func (m *MockMFARepository) IncrementChallengeAttempts(ctx context.Context, id uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementChallengeAttempts", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementChallengeAttempts indicates an expected call of IncrementChallengeAttempts.
+func (mr *MockMFARepositoryMockRecorder) IncrementChallengeAttempts(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementChallengeAttempts", reflect.TypeOf((*MockMFARepository)(nil).IncrementChallengeAttempts), ctx, id)
+}
+
+// ReplaceRecoveryCodes mocks base method.
+func (m *MockMFARepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codes []entity.MFARecoveryCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodes", ctx, userID, codes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecoveryCodes indicates an expected call of ReplaceRecoveryCodes.
+func (mr *MockMFARepositoryMockRecorder) ReplaceRecoveryCodes(ctx, userID, codes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodes", reflect.TypeOf((*MockMFARepository)(nil).ReplaceRecoveryCodes), ctx, userID, codes)
+}
+
+// SaveTotp mocks base method.
+func (m *MockMFARepository) SaveTotp(ctx context.Context, totp *entity.UserTotp) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTotp", ctx, totp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTotp indicates an expected call of SaveTotp.
+func (mr *MockMFARepositoryMockRecorder) SaveTotp(ctx, totp any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTotp", reflect.TypeOf((*MockMFARepository)(nil).SaveTotp), ctx, totp)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockMFARepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, userID, codeHash, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockMFARepositoryMockRecorder) UseRecoveryCode(ctx, userID, codeHash, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockMFARepository)(nil).UseRecoveryCode), ctx, userID, codeHash, now)
+}
+
+// UseTotpStep mocks base method.
+func (m *MockMFARepository) UseTotpStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseTotpStep", ctx, userID, step)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseTotpStep indicates an expected call of UseTotpStep.
+func (mr *MockMFARepositoryMockRecorder) UseTotpStep(ctx, userID, step any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTotpStep", reflect.TypeOf((*MockMFARepository)(nil).UseTotpStep), ctx, userID, step)
+}
+
+// MockMFAService is a mock of MFAService interface.
+type MockMFAService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMFAServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockMFAServiceMockRecorder is the mock recorder for MockMFAService.
+type MockMFAServiceMockRecorder struct {
+	mock *MockMFAService
+}
+
+// NewMockMFAService creates a new mock instance.
+func NewMockMFAService(ctrl *gomock.Controller) *MockMFAService {
+	mock := &MockMFAService{ctrl: ctrl}
+	mock.recorder = &MockMFAServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMFAService) EXPECT() *MockMFAServiceMockRecorder {
+	return m.recorder
+}
+
+// ConfirmTotp mocks base method.
+func (m *MockMFAService) ConfirmTotp(ctx context.Context, userID uuid.UUID, req dto.TotpCodeRequest) (dto.RecoveryCodesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTotp", ctx, userID, req)
+	ret0, _ := ret[0].(dto.RecoveryCodesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTotp indicates an expected call of ConfirmTotp.
+func (mr *MockMFAServiceMockRecorder) ConfirmTotp(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTotp", reflect.TypeOf((*MockMFAService)(nil).ConfirmTotp), ctx, userID, req)
+}
+
+// DisableTotp mocks base method.
+func (m *MockMFAService) DisableTotp(ctx context.Context, userID uuid.UUID, req dto.MFAStepUpRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTotp", ctx, userID, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTotp indicates an expected call of DisableTotp.
+func (mr *MockMFAServiceMockRecorder) DisableTotp(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTotp", reflect.TypeOf((*MockMFAService)(nil).DisableTotp), ctx, userID, req)
+}
+
+// EnrollTotp mocks base method.
+func (m *MockMFAService) EnrollTotp(ctx context.Context, userID uuid.UUID) (dto.TotpEnrollResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTotp", ctx, userID)
+	ret0, _ := ret[0].(dto.TotpEnrollResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollTotp indicates an expected call of EnrollTotp.
+func (mr *MockMFAServiceMockRecorder) EnrollTotp(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTotp", reflect.TypeOf((*MockMFAService)(nil).EnrollTotp), ctx, userID)
+}
+
+// Reauthenticate mocks base method.
+func (m *MockMFAService) Reauthenticate(ctx context.Context, userID uuid.UUID, req dto.ReauthRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reauthenticate", ctx, userID, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reauthenticate indicates an expected call of Reauthenticate.
+func (mr *MockMFAServiceMockRecorder) Reauthenticate(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reauthenticate", reflect.TypeOf((*MockMFAService)(nil).Reauthenticate), ctx, userID, req)
+}
+
+// RegenerateRecoveryCodes mocks base method.
+func (m *MockMFAService) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, req dto.MFAStepUpRequest) (dto.RecoveryCodesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateRecoveryCodes", ctx, userID, req)
+	ret0, _ := ret[0].(dto.RecoveryCodesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegenerateRecoveryCodes indicates an expected call of RegenerateRecoveryCodes.
+func (mr *MockMFAServiceMockRecorder) RegenerateRecoveryCodes(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateRecoveryCodes", reflect.TypeOf((*MockMFAService)(nil).RegenerateRecoveryCodes), ctx, userID, req)
+}
+
+// SignIn mocks base method.
+func (m *MockMFAService) SignIn(ctx context.Context, user entity.User, amr []string) (dto.LoginResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignIn", ctx, user, amr)
+	ret0, _ := ret[0].(dto.LoginResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignIn indicates an expected call of SignIn.
+func (mr *MockMFAServiceMockRecorder) SignIn(ctx, user, amr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignIn", reflect.TypeOf((*MockMFAService)(nil).SignIn), ctx, user, amr)
+}
+
+// Verify mocks base method.
+func (m *MockMFAService) Verify(ctx context.Context, req dto.MFAVerifyRequest) (dto.LoginResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, req)
+	ret0, _ := ret[0].(dto.LoginResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockMFAServiceMockRecorder) Verify(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockMFAService)(nil).Verify), ctx, req)
+}
@@ -0,0 +1,357 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/app/mfa/service"
+	bcryptMock "github.com/kelompok1-swe-academya/caper-be/pkg/bcrypt/mock"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
+	timeMock "github.com/kelompok1-swe-academya/caper-be/pkg/time/mock"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/token"
+	totpMock "github.com/kelompok1-swe-academya/caper-be/pkg/totp/mock"
+	uuidPkg "github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
+	loginThrottleMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/login_throttle/repository/mock"
+	mfaMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/mfa/repository/mock"
+	sessionMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/session/repository/mock"
+	userMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/user/repository/mock"
+)
+
+const (
+	mfaToken     = "bWZhLWNoYWxsZW5nZS10b2tlbg"
+	ipAddress    = "203.0.113.7"
+	sealed       = "sealed-secret"
+	secret       = "JBSWY3DPEHPK3PXP"
+	code         = "492039"
+	recoveryCode = "k3v9q2x7m1"
+	lastUsedStep = 59305800
+)
+
+var (
+	now  = time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC)
+	user = entity.User{
+		ID:       uuid.MustParse("0192b7a4-5c1e-7d3a-9f21-6b8e4c2d1a07"),
+		Email:    "jane@example.com",
+		Password: "hashed-password",
+	}
+	challenge = entity.MFAChallenge{
+		ID:     uuid.MustParse("0192b7a4-6d2f-7e4b-8a32-7c9f5d3e2b18"),
+		UserID: user.ID,
+		AMR:    jwt.AMRPassword,
+	}
+	enrollment = entity.UserTotp{
+		UserID:       user.ID,
+		Secret:       sealed,
+		LastUsedStep: lastUsedStep,
+		ConfirmedAt:  sql.NullTime{Time: now.Add(-24 * time.Hour), Valid: true},
+	}
+)
+
+func newClock(ctrl *gomock.Controller) *timeMock.MockTimeInterface {
+	clock := timeMock.NewMockTimeInterface(ctrl)
+	clock.EXPECT().Now().Return(now).AnyTimes()
+
+	return clock
+}
+
+// expectTotp makes code validate to step against the enrolled secret
+func expectTotp(ctrl *gomock.Controller, step int64, ok bool) *totpMock.MockTotpInterface {
+	totp := totpMock.NewMockTotpInterface(ctrl)
+	totp.EXPECT().Open(sealed).Return(secret, nil)
+	totp.EXPECT().Validate(code, secret, now).Return(step, ok)
+
+	return totp
+}
+
+func TestMFAService_Verify(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         dto.MFACodeRequest
+		step        int64
+		valid       bool
+		recoveryErr error
+		attempts    int
+		wantAMR     []string
+		wantDeleted bool
+		wantFailure bool
+		wantErr     error
+	}{
+		{
+			name:    "code",
+			req:     dto.MFACodeRequest{Code: code},
+			step:    lastUsedStep + 1,
+			valid:   true,
+			wantAMR: []string{jwt.AMRPassword, jwt.AMROTP, jwt.AMRMultiFactor},
+		},
+		{
+			name:    "recovery code",
+			req:     dto.MFACodeRequest{RecoveryCode: " " + recoveryCode + " "},
+			wantAMR: []string{jwt.AMRPassword, jwt.AMRRecoveryCode, jwt.AMRMultiFactor},
+		},
+		{
+			name:        "wrong code",
+			req:         dto.MFACodeRequest{Code: code},
+			attempts:    1,
+			wantFailure: true,
+			wantErr:     domain.ErrInvalidMFACode,
+		},
+		{
+			name:        "replayed code",
+			req:         dto.MFACodeRequest{Code: code},
+			step:        lastUsedStep,
+			valid:       true,
+			attempts:    1,
+			wantFailure: true,
+			wantErr:     domain.ErrInvalidMFACode,
+		},
+		{
+			name:        "used recovery code",
+			req:         dto.MFACodeRequest{RecoveryCode: recoveryCode},
+			recoveryErr: domain.ErrInvalidMFACode,
+			attempts:    1,
+			wantFailure: true,
+			wantErr:     domain.ErrInvalidMFACode,
+		},
+		{
+			// the challenge is dropped so a new one needs the password again
+			name:        "last attempt",
+			req:         dto.MFACodeRequest{Code: code},
+			attempts:    5,
+			wantFailure: true,
+			wantDeleted: true,
+			wantErr:     domain.ErrInvalidMFACode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mfaRepo := mfaMock.NewMockMFARepository(ctrl)
+			userRepo := userMock.NewMockUserRepository(ctrl)
+			sessionService := sessionMock.NewMockSessionService(ctrl)
+			loginThrottleService := loginThrottleMock.NewMockLoginThrottleService(ctrl)
+			totp := totpMock.NewMockTotpInterface(ctrl)
+
+			mfaRepo.EXPECT().FindChallenge(gomock.Any(), token.Token.Hash(mfaToken), now).Return(challenge, nil)
+			userRepo.EXPECT().FindByID(gomock.Any(), user.ID).Return(user, nil)
+			loginThrottleService.EXPECT().Check(gomock.Any(), user.Email, ipAddress).Return(nil)
+			mfaRepo.EXPECT().FindTotp(gomock.Any(), user.ID).Return(enrollment, nil)
+
+			if tt.req.Code != "" {
+				totp = expectTotp(ctrl, tt.step, tt.valid)
+				if tt.valid && tt.step > lastUsedStep {
+					mfaRepo.EXPECT().UseTotpStep(gomock.Any(), user.ID, tt.step).Return(nil)
+				}
+			} else {
+				mfaRepo.EXPECT().
+					UseRecoveryCode(gomock.Any(), user.ID, token.Token.Hash(recoveryCode), now).
+					Return(tt.recoveryErr)
+			}
+
+			if tt.wantFailure {
+				loginThrottleService.EXPECT().RecordFailure(gomock.Any(), user.Email, ipAddress).Return(nil)
+				mfaRepo.EXPECT().IncrementChallengeAttempts(gomock.Any(), challenge.ID).Return(tt.attempts, nil)
+			} else {
+				loginThrottleService.EXPECT().RecordSuccess(gomock.Any(), user.Email).Return(nil)
+				sessionService.EXPECT().
+					Issue(gomock.Any(), user, tt.wantAMR).
+					Return(dto.TokenResponse{AccessToken: "access-token"}, nil)
+			}
+
+			if tt.wantDeleted || tt.wantErr == nil {
+				mfaRepo.EXPECT().DeleteChallenge(gomock.Any(), challenge.ID).Return(nil)
+			}
+
+			mfaService := service.NewMFAService(
+				mfaRepo,
+				userRepo,
+				sessionService,
+				loginThrottleService,
+				validator.Validator,
+				uuidPkg.UUID,
+				bcryptMock.NewMockBcryptInterface(ctrl),
+				token.Token,
+				totp,
+				newClock(ctrl),
+			)
+
+			res, err := mfaService.Verify(context.Background(), dto.MFAVerifyRequest{
+				MFAToken:       mfaToken,
+				MFACodeRequest: tt.req,
+				IPAddress:      ipAddress,
+			})
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			if tt.wantErr == nil {
+				assert.Equal(t, "access-token", res.TokenResponse.AccessToken)
+			}
+		})
+	}
+}
+
+// a locked account is refused before its code is looked at
+func TestMFAService_Verify_LockedOut(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mfaRepo := mfaMock.NewMockMFARepository(ctrl)
+	userRepo := userMock.NewMockUserRepository(ctrl)
+	loginThrottleService := loginThrottleMock.NewMockLoginThrottleService(ctrl)
+
+	mfaRepo.EXPECT().FindChallenge(gomock.Any(), token.Token.Hash(mfaToken), now).Return(challenge, nil)
+	userRepo.EXPECT().FindByID(gomock.Any(), user.ID).Return(user, nil)
+	loginThrottleService.EXPECT().Check(gomock.Any(), user.Email, ipAddress).Return(domain.ErrTooManyLoginAttempts)
+
+	mfaService := service.NewMFAService(
+		mfaRepo,
+		userRepo,
+		sessionMock.NewMockSessionService(ctrl),
+		loginThrottleService,
+		validator.Validator,
+		uuidPkg.UUID,
+		bcryptMock.NewMockBcryptInterface(ctrl),
+		token.Token,
+		totpMock.NewMockTotpInterface(ctrl),
+		newClock(ctrl),
+	)
+
+	_, err := mfaService.Verify(context.Background(), dto.MFAVerifyRequest{
+		MFAToken:       mfaToken,
+		MFACodeRequest: dto.MFACodeRequest{Code: code},
+		IPAddress:      ipAddress,
+	})
+	assert.ErrorIs(t, err, domain.ErrTooManyLoginAttempts)
+}
+
+// a wrong password or code of a signed in user counts towards the login
+// lockout, the step up through a code goes through the same replay check
+// as Verify
+func TestMFAService_Reauthenticate(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         dto.ReauthRequest
+		passwordOK  bool
+		step        int64
+		valid       bool
+		notEnrolled bool
+		lockedOut   bool
+		wantFailure bool
+		wantErr     error
+	}{
+		{
+			name:       "password",
+			req:        dto.ReauthRequest{CurrentPassword: "correct horse"},
+			passwordOK: true,
+		},
+		{
+			name:        "wrong password",
+			req:         dto.ReauthRequest{CurrentPassword: "battery staple"},
+			wantFailure: true,
+			wantErr:     domain.ErrCredentialsNotMatch,
+		},
+		{
+			name:  "code",
+			req:   dto.ReauthRequest{Code: code},
+			step:  lastUsedStep + 1,
+			valid: true,
+		},
+		{
+			name:        "replayed code",
+			req:         dto.ReauthRequest{Code: code},
+			step:        lastUsedStep,
+			valid:       true,
+			wantFailure: true,
+			wantErr:     domain.ErrInvalidMFACode,
+		},
+		{
+			name:        "earlier code",
+			req:         dto.ReauthRequest{Code: code},
+			step:        lastUsedStep - 1,
+			valid:       true,
+			wantFailure: true,
+			wantErr:     domain.ErrInvalidMFACode,
+		},
+		{
+			name:        "wrong code",
+			req:         dto.ReauthRequest{Code: code},
+			wantFailure: true,
+			wantErr:     domain.ErrInvalidMFACode,
+		},
+		{
+			// not a guess, so it doesn't count towards the lockout
+			name:        "mfa not enabled",
+			req:         dto.ReauthRequest{Code: code},
+			notEnrolled: true,
+			wantErr:     domain.ErrMFANotEnabled,
+		},
+		{
+			name:      "locked out",
+			req:       dto.ReauthRequest{CurrentPassword: "correct horse"},
+			lockedOut: true,
+			wantErr:   domain.ErrTooManyLoginAttempts,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mfaRepo := mfaMock.NewMockMFARepository(ctrl)
+			userRepo := userMock.NewMockUserRepository(ctrl)
+			loginThrottleService := loginThrottleMock.NewMockLoginThrottleService(ctrl)
+			bcrypt := bcryptMock.NewMockBcryptInterface(ctrl)
+			totp := totpMock.NewMockTotpInterface(ctrl)
+
+			userRepo.EXPECT().FindByID(gomock.Any(), user.ID).Return(user, nil)
+
+			if tt.lockedOut {
+				loginThrottleService.EXPECT().Check(gomock.Any(), user.Email, ipAddress).Return(domain.ErrTooManyLoginAttempts)
+			} else {
+				loginThrottleService.EXPECT().Check(gomock.Any(), user.Email, ipAddress).Return(nil)
+
+				switch {
+				case tt.req.CurrentPassword != "":
+					bcrypt.EXPECT().Compare(tt.req.CurrentPassword, user.Password).Return(tt.passwordOK)
+				case tt.notEnrolled:
+					mfaRepo.EXPECT().FindTotp(gomock.Any(), user.ID).Return(entity.UserTotp{}, domain.ErrMFAEnrollmentNotFound)
+				default:
+					mfaRepo.EXPECT().FindTotp(gomock.Any(), user.ID).Return(enrollment, nil)
+					totp = expectTotp(ctrl, tt.step, tt.valid)
+					if tt.valid && tt.step > lastUsedStep {
+						mfaRepo.EXPECT().UseTotpStep(gomock.Any(), user.ID, tt.step).Return(nil)
+					}
+				}
+
+				if tt.wantFailure {
+					loginThrottleService.EXPECT().RecordFailure(gomock.Any(), user.Email, ipAddress).Return(nil)
+				} else if tt.wantErr == nil {
+					loginThrottleService.EXPECT().RecordSuccess(gomock.Any(), user.Email).Return(nil)
+				}
+			}
+
+			mfaService := service.NewMFAService(
+				mfaRepo,
+				userRepo,
+				sessionMock.NewMockSessionService(ctrl),
+				loginThrottleService,
+				validator.Validator,
+				uuidPkg.UUID,
+				bcrypt,
+				token.Token,
+				totp,
+				newClock(ctrl),
+			)
+
+			tt.req.IPAddress = ipAddress
+			err := mfaService.Reauthenticate(context.Background(), user.ID, tt.req)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/middlewares"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/helpers/http/error_handler"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
+	rateLimiterMock "github.com/kelompok1-swe-academya/caper-be/pkg/rate_limiter/mock"
+	personalAccessTokenMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/personal_access_token/repository/mock"
+	roleMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/role/repository/mock"
+	sessionMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/session/repository/mock"
+	userMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/user/repository/mock"
+)
+
+const plainToken = entity.PersonalAccessTokenPrefix + "c2VjcmV0LXNlY3JldC1zZWNyZXQ"
+
+// newApp mounts the middleware the way the routes do, a profile route scoped
+// to profile:read and an admin route that also needs users:read and the
+// admin role
+func newApp(middleware *middlewares.Middleware) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: errorhandler.ErrorHandler})
+
+	ok := func(ctx *fiber.Ctx) error {
+		return ctx.SendStatus(http.StatusNoContent)
+	}
+
+	app.Get("/profile", middleware.RequireAuth(entity.ScopeProfileRead), ok)
+	app.Get("/sessions", middleware.RequireAuth(), ok)
+	app.Get(
+		"/users",
+		middleware.RequireAuth(entity.ScopeUsersRead),
+		middleware.RequireRoles("admin"),
+		ok,
+	)
+
+	return app
+}
+
+func do(t *testing.T, app *fiber.App, path string, token string) int {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res, err := app.Test(req)
+	require.NoError(t, err)
+
+	return res.StatusCode
+}
+
+// expectPersonalAccessToken authenticates plainToken as a token holding scopes
+func expectPersonalAccessToken(
+	personalAccessTokenService *personalAccessTokenMock.MockPersonalAccessTokenService,
+	roleName string,
+	scopes ...string,
+) {
+	personalAccessTokenService.EXPECT().
+		Authenticate(gomock.Any(), plainToken, gomock.Any()).
+		Return(jwt.Claims{
+			UserID:                uuid.New(),
+			RoleName:              roleName,
+			PersonalAccessTokenID: uuid.New(),
+			Scopes:                scopes,
+		}, nil)
+}
+
+func TestRequireAuth_PersonalAccessTokenScopes(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		scopes     []string
+		wantStatus int
+	}{
+		{
+			name:       "holds the scope",
+			path:       "/profile",
+			scopes:     []string{entity.ScopeProfileRead},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "holds more scopes than needed",
+			path:       "/profile",
+			scopes:     []string{entity.ScopeUploadsWrite, entity.ScopeProfileRead},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "missing the scope",
+			path:       "/profile",
+			scopes:     []string{entity.ScopeProfileWrite},
+			wantStatus: domain.ErrInsufficientTokenScope.StatusCode,
+		},
+		{
+			name:       "no scopes",
+			path:       "/profile",
+			wantStatus: domain.ErrInsufficientTokenScope.StatusCode,
+		},
+		{
+			// routes without scopes are closed to personal access tokens
+			name: "route without scopes",
+			path: "/sessions",
+			scopes: []string{
+				entity.ScopeProfileRead,
+				entity.ScopeProfileWrite,
+				entity.ScopeUploadsWrite,
+				entity.ScopeUsersRead,
+				entity.ScopeUsersWrite,
+			},
+			wantStatus: domain.ErrInsufficientTokenScope.StatusCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			personalAccessTokenService := personalAccessTokenMock.NewMockPersonalAccessTokenService(ctrl)
+			expectPersonalAccessToken(personalAccessTokenService, "user", tt.scopes...)
+
+			middleware := middlewares.NewMiddleware(
+				jwt.Jwt,
+				sessionMock.NewMockRevokedTokenRepository(ctrl),
+				personalAccessTokenService,
+				roleMock.NewMockRoleService(ctrl),
+				userMock.NewMockUserRepository(ctrl),
+				rateLimiterMock.NewMockRateLimiterInterface(ctrl),
+			)
+
+			assert.Equal(t, tt.wantStatus, do(t, newApp(middleware), tt.path, plainToken))
+		})
+	}
+}
+
+// a scope never lifts the role check, users:read alone doesn't make an admin
+func TestRequireAuth_PersonalAccessTokenKeepsRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	personalAccessTokenService := personalAccessTokenMock.NewMockPersonalAccessTokenService(ctrl)
+	roleService := roleMock.NewMockRoleService(ctrl)
+
+	expectPersonalAccessToken(personalAccessTokenService, "user", entity.ScopeUsersRead)
+	roleService.EXPECT().HasAnyRole(gomock.Any(), "user", "admin").Return(false, nil)
+
+	middleware := middlewares.NewMiddleware(
+		jwt.Jwt,
+		sessionMock.NewMockRevokedTokenRepository(ctrl),
+		personalAccessTokenService,
+		roleService,
+		userMock.NewMockUserRepository(ctrl),
+		rateLimiterMock.NewMockRateLimiterInterface(ctrl),
+	)
+
+	assert.Equal(t, domain.ErrRoleCantAccessResource.StatusCode, do(t, newApp(middleware), "/users", plainToken))
+}
+
+func TestRequireAuth_PersonalAccessTokenInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	personalAccessTokenService := personalAccessTokenMock.NewMockPersonalAccessTokenService(ctrl)
+	personalAccessTokenService.EXPECT().
+		Authenticate(gomock.Any(), plainToken, gomock.Any()).
+		Return(jwt.Claims{}, domain.ErrInvalidBearerToken)
+
+	middleware := middlewares.NewMiddleware(
+		jwt.Jwt,
+		sessionMock.NewMockRevokedTokenRepository(ctrl),
+		personalAccessTokenService,
+		roleMock.NewMockRoleService(ctrl),
+		userMock.NewMockUserRepository(ctrl),
+		rateLimiterMock.NewMockRateLimiterInterface(ctrl),
+	)
+
+	assert.Equal(t, domain.ErrInvalidBearerToken.StatusCode, do(t, newApp(middleware), "/profile", plainToken))
+}
+
+// a jwt is the user signed in, scopes don't apply to it
+func TestRequireAuth_JwtIgnoresScopes(t *testing.T) {
+	token, err := jwt.Jwt.Create(uuid.New(), "user", []string{jwt.AMRPassword})
+	require.NoError(t, err)
+
+	for _, path := range []string{"/profile", "/sessions"} {
+		t.Run(path, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			revokedTokenRepo := sessionMock.NewMockRevokedTokenRepository(ctrl)
+			revokedTokenRepo.EXPECT().
+				IsRevoked(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(false, nil)
+
+			middleware := middlewares.NewMiddleware(
+				jwt.Jwt,
+				revokedTokenRepo,
+				personalAccessTokenMock.NewMockPersonalAccessTokenService(ctrl),
+				roleMock.NewMockRoleService(ctrl),
+				userMock.NewMockUserRepository(ctrl),
+				rateLimiterMock.NewMockRateLimiterInterface(ctrl),
+			)
+
+			assert.Equal(t, http.StatusNoContent, do(t, newApp(middleware), path, token))
+		})
+	}
+}
+
+func TestRequireAuth_NoToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	middleware := middlewares.NewMiddleware(
+		jwt.Jwt,
+		sessionMock.NewMockRevokedTokenRepository(ctrl),
+		personalAccessTokenMock.NewMockPersonalAccessTokenService(ctrl),
+		roleMock.NewMockRoleService(ctrl),
+		userMock.NewMockUserRepository(ctrl),
+		rateLimiterMock.NewMockRateLimiterInterface(ctrl),
+	)
+
+	assert.Equal(t, domain.ErrNoBearerToken.StatusCode, do(t, newApp(middleware), "/profile", ""))
+}
@@ -0,0 +1,170 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/contracts/oauth_contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=domain/contracts/oauth_contracts.go -destination=tests/unit/oauth/repository/mock/oauth_repository_mock.go -package=repository_mock
+//
+
+// Package repository_mock is a generated GoMock package.
+package repository_mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	dto "github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	entity "github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOAuthRepository is a mock of OAuthRepository interface.
+type MockOAuthRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockOAuthRepositoryMockRecorder is the mock recorder for MockOAuthRepository.
+type MockOAuthRepositoryMockRecorder struct {
+	mock *MockOAuthRepository
+}
+
+// NewMockOAuthRepository creates a new mock instance.
+func NewMockOAuthRepository(ctrl *gomock.Controller) *MockOAuthRepository {
+	mock := &MockOAuthRepository{ctrl: ctrl}
+	mock.recorder = &MockOAuthRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthRepository) EXPECT() *MockOAuthRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ConsumeState mocks base method.
+func (m *MockOAuthRepository) ConsumeState(ctx context.Context, stateHash string, now time.Time) (entity.OAuthState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeState", ctx, stateHash, now)
+	ret0, _ := ret[0].(entity.OAuthState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeState indicates an expected call of ConsumeState.
+func (mr *MockOAuthRepositoryMockRecorder) ConsumeState(ctx, stateHash, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeState", reflect.TypeOf((*MockOAuthRepository)(nil).ConsumeState), ctx, stateHash, now)
+}
+
+// CreateIdentity mocks base method.
+func (m *MockOAuthRepository) CreateIdentity(ctx context.Context, identity *entity.UserIdentity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdentity", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateIdentity indicates an expected call of CreateIdentity.
+func (mr *MockOAuthRepositoryMockRecorder) CreateIdentity(ctx, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdentity", reflect.TypeOf((*MockOAuthRepository)(nil).CreateIdentity), ctx, identity)
+}
+
+// CreateState mocks base method.
+func (m *MockOAuthRepository) CreateState(ctx context.Context, state *entity.OAuthState) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateState", ctx, state)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateState indicates an expected call of CreateState.
+func (mr *MockOAuthRepositoryMockRecorder) CreateState(ctx, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateState", reflect.TypeOf((*MockOAuthRepository)(nil).CreateState), ctx, state)
+}
+
+// DeleteExpiredStates mocks base method.
+func (m *MockOAuthRepository) DeleteExpiredStates(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredStates", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredStates indicates an expected call of DeleteExpiredStates.
+func (mr *MockOAuthRepositoryMockRecorder) DeleteExpiredStates(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredStates", reflect.TypeOf((*MockOAuthRepository)(nil).DeleteExpiredStates), ctx)
+}
+
+// FindIdentity mocks base method.
+func (m *MockOAuthRepository) FindIdentity(ctx context.Context, provider, subject string) (entity.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindIdentity", ctx, provider, subject)
+	ret0, _ := ret[0].(entity.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindIdentity indicates an expected call of FindIdentity.
+func (mr *MockOAuthRepositoryMockRecorder) FindIdentity(ctx, provider, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIdentity", reflect.TypeOf((*MockOAuthRepository)(nil).FindIdentity), ctx, provider, subject)
+}
+
+// MockOAuthService is a mock of OAuthService interface.
+type MockOAuthService struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockOAuthServiceMockRecorder is the mock recorder for MockOAuthService.
+type MockOAuthServiceMockRecorder struct {
+	mock *MockOAuthService
+}
+
+// NewMockOAuthService creates a new mock instance.
+func NewMockOAuthService(ctrl *gomock.Controller) *MockOAuthService {
+	mock := &MockOAuthService{ctrl: ctrl}
+	mock.recorder = &MockOAuthServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthService) EXPECT() *MockOAuthServiceMockRecorder {
+	return m.recorder
+}
+
+// GoogleCallback mocks base method.
+func (m *MockOAuthService) GoogleCallback(ctx context.Context, req dto.OAuthCallbackRequest) (dto.LoginResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GoogleCallback", ctx, req)
+	ret0, _ := ret[0].(dto.LoginResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GoogleCallback indicates an expected call of GoogleCallback.
+func (mr *MockOAuthServiceMockRecorder) GoogleCallback(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GoogleCallback", reflect.TypeOf((*MockOAuthService)(nil).GoogleCallback), ctx, req)
+}
+
+// GoogleLogin mocks base method.
+func (m *MockOAuthService) GoogleLogin(ctx context.Context) (dto.OAuthLoginResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GoogleLogin", ctx)
+	ret0, _ := ret[0].(dto.OAuthLoginResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GoogleLogin indicates an expected call of GoogleLogin.
+func (mr *MockOAuthServiceMockRecorder) GoogleLogin(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GoogleLogin", reflect.TypeOf((*MockOAuthService)(nil).GoogleLogin), ctx)
+}
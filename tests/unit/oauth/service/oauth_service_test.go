@@ -0,0 +1,498 @@
+package service_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/oauth2"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/app/oauth/service"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	bcryptMock "github.com/kelompok1-swe-academya/caper-be/pkg/bcrypt/mock"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
+	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/token"
+	uuidPkg "github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
+	mfaMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/mfa/repository/mock"
+	oauthMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/oauth/repository/mock"
+	sessionMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/session/repository/mock"
+	userMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/user/repository/mock"
+)
+
+const (
+	clientID     = "caper.apps.googleusercontent.com"
+	clientSecret = "google-client-secret"
+	redirectURL  = "http://localhost:8080/api/v1/auth/google/callback"
+	authCode     = "4/0AeanS0b-authorization-code"
+	plainState   = "b2F1dGgtc3RhdGUtdG9rZW4"
+	nonce        = "b2F1dGgtbm9uY2UtdmFsdWU"
+	subject      = "109876543210987654321"
+	keyID        = "provider-key"
+)
+
+var (
+	providerKey = mustGenerateKey()
+	otherKey    = mustGenerateKey()
+
+	codeVerifier = oauth2.GenerateVerifier()
+	user         = entity.User{
+		ID:              uuid.MustParse("0192b7a4-5c1e-7d3a-9f21-6b8e4c2d1a07"),
+		Name:            "Jane Doe",
+		Email:           "jane@example.com",
+		EmailVerifiedAt: sql.NullTime{Time: time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC), Valid: true},
+	}
+)
+
+func mustGenerateKey() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	return key
+}
+
+func encodeSegment(value any) string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// signIDToken signs claims with RS256 the way the provider does
+func signIDToken(key *rsa.PrivateKey, claims map[string]any) string {
+	signed := encodeSegment(map[string]string{"alg": "RS256", "kid": keyID, "typ": "JWT"}) + "." + encodeSegment(claims)
+
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		panic(err)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// idTokenClaims are the claims google sends for a verified account
+func idTokenClaims(issuer string) map[string]any {
+	now := time.Now()
+
+	return map[string]any{
+		"iss":            issuer,
+		"sub":            subject,
+		"aud":            clientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          nonce,
+		"email":          "Jane@Example.com",
+		"email_verified": true,
+		"name":           "Jane Doe",
+	}
+}
+
+// newProvider starts a fake oidc provider that publishes providerKey and
+// answers the authorization code with the id token built by idToken. It
+// only accepts the code together with the verifier of the state.
+func newProvider(t *testing.T, idToken func(issuer string) string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                                srv.URL,
+			"authorization_endpoint":                srv.URL + "/auth",
+			"token_endpoint":                        srv.URL + "/token",
+			"jwks_uri":                              srv.URL + "/keys",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": keyID,
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(providerKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(providerKey.E)).Bytes()),
+			}},
+		})
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil ||
+			r.PostForm.Get("code") != authCode ||
+			r.PostForm.Get("code_verifier") != codeVerifier {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "ya29.access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3599,
+			"id_token":     idToken(srv.URL),
+		})
+	})
+
+	return srv
+}
+
+// configureGoogle points the google client at issuer for the test
+func configureGoogle(t *testing.T, issuer string) {
+	t.Helper()
+
+	previous := *env.AppEnv
+	t.Cleanup(func() {
+		env.AppEnv.GoogleClientID = previous.GoogleClientID
+		env.AppEnv.GoogleClientSecret = previous.GoogleClientSecret
+		env.AppEnv.GoogleRedirectURL = previous.GoogleRedirectURL
+		env.AppEnv.GoogleIssuerURL = previous.GoogleIssuerURL
+	})
+
+	env.AppEnv.GoogleClientID = clientID
+	env.AppEnv.GoogleClientSecret = clientSecret
+	env.AppEnv.GoogleRedirectURL = redirectURL
+	env.AppEnv.GoogleIssuerURL = issuer
+}
+
+func expectState(oauthRepo *oauthMock.MockOAuthRepository) {
+	oauthRepo.EXPECT().
+		ConsumeState(gomock.Any(), token.Token.Hash(plainState), gomock.Any()).
+		Return(entity.OAuthState{Nonce: nonce, CodeVerifier: codeVerifier}, nil)
+}
+
+func TestOAuthService_GoogleLogin(t *testing.T) {
+	srv := newProvider(t, func(string) string { return "" })
+	configureGoogle(t, srv.URL)
+
+	ctrl := gomock.NewController(t)
+	oauthRepo := oauthMock.NewMockOAuthRepository(ctrl)
+
+	var created entity.OAuthState
+	oauthRepo.EXPECT().
+		CreateState(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, state *entity.OAuthState) error {
+			created = *state
+			return nil
+		})
+
+	oauthService := service.NewOAuthService(
+		oauthRepo,
+		userMock.NewMockUserRepository(ctrl),
+		sessionMock.NewMockSessionService(ctrl),
+		mfaMock.NewMockMFAService(ctrl),
+		validator.Validator,
+		uuidPkg.UUID,
+		bcryptMock.NewMockBcryptInterface(ctrl),
+		token.Token,
+		timePkg.Time,
+	)
+
+	res, err := oauthService.GoogleLogin(context.Background())
+	require.NoError(t, err)
+
+	authURL, err := url.Parse(res.URL)
+	require.NoError(t, err)
+
+	query := authURL.Query()
+	assert.Equal(t, srv.URL+"/auth", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+	assert.Equal(t, clientID, query.Get("client_id"))
+	assert.Equal(t, redirectURL, query.Get("redirect_uri"))
+	assert.Equal(t, res.State, query.Get("state"))
+	assert.Equal(t, token.Token.Hash(res.State), created.StateHash)
+	assert.Equal(t, created.Nonce, query.Get("nonce"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, oauth2.S256ChallengeFromVerifier(created.CodeVerifier), query.Get("code_challenge"))
+}
+
+func TestOAuthService_GoogleLogin_NotConfigured(t *testing.T) {
+	configureGoogle(t, "")
+	env.AppEnv.GoogleClientSecret = ""
+
+	ctrl := gomock.NewController(t)
+	oauthService := service.NewOAuthService(
+		oauthMock.NewMockOAuthRepository(ctrl),
+		userMock.NewMockUserRepository(ctrl),
+		sessionMock.NewMockSessionService(ctrl),
+		mfaMock.NewMockMFAService(ctrl),
+		validator.Validator,
+		uuidPkg.UUID,
+		bcryptMock.NewMockBcryptInterface(ctrl),
+		token.Token,
+		timePkg.Time,
+	)
+
+	_, err := oauthService.GoogleLogin(context.Background())
+	assert.ErrorIs(t, err, domain.ErrOAuthNotConfigured)
+}
+
+// every id token that isn't for this client, from this provider and for
+// this login is refused before any account is looked at
+func TestOAuthService_GoogleCallback_Rejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		key     *rsa.PrivateKey
+		claims  func(claims map[string]any)
+		wantErr error
+	}{
+		{
+			name:    "code not accepted",
+			code:    "4/0AeanS0b-other-code",
+			wantErr: domain.ErrOAuthAuthenticationFailed,
+		},
+		{
+			name:    "signed by another key",
+			key:     otherKey,
+			wantErr: domain.ErrOAuthAuthenticationFailed,
+		},
+		{
+			name:    "other issuer",
+			claims:  func(claims map[string]any) { claims["iss"] = "https://accounts.example.com" },
+			wantErr: domain.ErrOAuthAuthenticationFailed,
+		},
+		{
+			name:    "other client",
+			claims:  func(claims map[string]any) { claims["aud"] = "other.apps.googleusercontent.com" },
+			wantErr: domain.ErrOAuthAuthenticationFailed,
+		},
+		{
+			name:    "expired",
+			claims:  func(claims map[string]any) { claims["exp"] = time.Now().Add(-time.Minute).Unix() },
+			wantErr: domain.ErrOAuthAuthenticationFailed,
+		},
+		{
+			name:    "other login",
+			claims:  func(claims map[string]any) { claims["nonce"] = "b3RoZXItbm9uY2UtdmFsdWU" },
+			wantErr: domain.ErrOAuthAuthenticationFailed,
+		},
+		{
+			name:    "email not verified",
+			claims:  func(claims map[string]any) { claims["email_verified"] = false },
+			wantErr: domain.ErrOAuthEmailNotVerified,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := providerKey
+			if tt.key != nil {
+				key = tt.key
+			}
+
+			srv := newProvider(t, func(issuer string) string {
+				claims := idTokenClaims(issuer)
+				if tt.claims != nil {
+					tt.claims(claims)
+				}
+
+				return signIDToken(key, claims)
+			})
+			configureGoogle(t, srv.URL)
+
+			ctrl := gomock.NewController(t)
+			oauthRepo := oauthMock.NewMockOAuthRepository(ctrl)
+			expectState(oauthRepo)
+
+			oauthService := service.NewOAuthService(
+				oauthRepo,
+				userMock.NewMockUserRepository(ctrl),
+				sessionMock.NewMockSessionService(ctrl),
+				mfaMock.NewMockMFAService(ctrl),
+				validator.Validator,
+				uuidPkg.UUID,
+				bcryptMock.NewMockBcryptInterface(ctrl),
+				token.Token,
+				timePkg.Time,
+			)
+
+			code := authCode
+			if tt.code != "" {
+				code = tt.code
+			}
+
+			_, err := oauthService.GoogleCallback(context.Background(), dto.OAuthCallbackRequest{
+				Code:  code,
+				State: plainState,
+			})
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestOAuthService_GoogleCallback(t *testing.T) {
+	unverified := user
+	unverified.EmailVerifiedAt = sql.NullTime{}
+
+	tests := []struct {
+		name  string
+		setup func(
+			oauthRepo *oauthMock.MockOAuthRepository,
+			userRepo *userMock.MockUserRepository,
+			sessionService *sessionMock.MockSessionService,
+			bcrypt *bcryptMock.MockBcryptInterface,
+		)
+	}{
+		{
+			name: "linked account",
+			setup: func(
+				oauthRepo *oauthMock.MockOAuthRepository,
+				userRepo *userMock.MockUserRepository,
+				_ *sessionMock.MockSessionService,
+				_ *bcryptMock.MockBcryptInterface,
+			) {
+				oauthRepo.EXPECT().
+					FindIdentity(gomock.Any(), entity.OAuthProviderGoogle, subject).
+					Return(entity.UserIdentity{UserID: user.ID}, nil)
+				userRepo.EXPECT().FindByID(gomock.Any(), user.ID).Return(user, nil)
+			},
+		},
+		{
+			name: "verified account with the same email",
+			setup: func(
+				oauthRepo *oauthMock.MockOAuthRepository,
+				userRepo *userMock.MockUserRepository,
+				_ *sessionMock.MockSessionService,
+				_ *bcryptMock.MockBcryptInterface,
+			) {
+				oauthRepo.EXPECT().
+					FindIdentity(gomock.Any(), entity.OAuthProviderGoogle, subject).
+					Return(entity.UserIdentity{}, domain.ErrUserIdentityNotFound)
+				userRepo.EXPECT().FindByEmail(gomock.Any(), user.Email).Return(user, nil)
+				expectIdentity(oauthRepo, userRepo)
+			},
+		},
+		{
+			// somebody else may have registered the email, their password
+			// and sessions must not survive
+			name: "unverified account with the same email",
+			setup: func(
+				oauthRepo *oauthMock.MockOAuthRepository,
+				userRepo *userMock.MockUserRepository,
+				sessionService *sessionMock.MockSessionService,
+				bcrypt *bcryptMock.MockBcryptInterface,
+			) {
+				oauthRepo.EXPECT().
+					FindIdentity(gomock.Any(), entity.OAuthProviderGoogle, subject).
+					Return(entity.UserIdentity{}, domain.ErrUserIdentityNotFound)
+				userRepo.EXPECT().FindByEmail(gomock.Any(), user.Email).Return(unverified, nil)
+				bcrypt.EXPECT().Hash(gomock.Any()).Return("unusable-password", nil)
+				userRepo.EXPECT().UpdatePassword(gomock.Any(), user.ID, "unusable-password").Return(nil)
+				sessionService.EXPECT().RevokeUserSessions(gomock.Any(), user.ID).Return(nil)
+				userRepo.EXPECT().MarkEmailVerified(gomock.Any(), user.ID).Return(nil)
+				expectIdentity(oauthRepo, userRepo)
+			},
+		},
+		{
+			name: "new account",
+			setup: func(
+				oauthRepo *oauthMock.MockOAuthRepository,
+				userRepo *userMock.MockUserRepository,
+				_ *sessionMock.MockSessionService,
+				bcrypt *bcryptMock.MockBcryptInterface,
+			) {
+				oauthRepo.EXPECT().
+					FindIdentity(gomock.Any(), entity.OAuthProviderGoogle, subject).
+					Return(entity.UserIdentity{}, domain.ErrUserIdentityNotFound)
+				userRepo.EXPECT().FindByEmail(gomock.Any(), user.Email).Return(entity.User{}, domain.ErrUserNotFound)
+				bcrypt.EXPECT().Hash(gomock.Any()).Return("unusable-password", nil)
+				userRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, created *entity.User) error {
+						assert.Equal(t, "Jane Doe", created.Name)
+						assert.Equal(t, user.Email, created.Email)
+						assert.Equal(t, "id", created.Locale)
+						created.ID = user.ID
+						return nil
+					})
+				userRepo.EXPECT().MarkEmailVerified(gomock.Any(), user.ID).Return(nil)
+				expectIdentity(oauthRepo, userRepo)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newProvider(t, func(issuer string) string {
+				return signIDToken(providerKey, idTokenClaims(issuer))
+			})
+			configureGoogle(t, srv.URL)
+
+			ctrl := gomock.NewController(t)
+			oauthRepo := oauthMock.NewMockOAuthRepository(ctrl)
+			userRepo := userMock.NewMockUserRepository(ctrl)
+			sessionService := sessionMock.NewMockSessionService(ctrl)
+			mfaService := mfaMock.NewMockMFAService(ctrl)
+			bcrypt := bcryptMock.NewMockBcryptInterface(ctrl)
+
+			expectState(oauthRepo)
+			tt.setup(oauthRepo, userRepo, sessionService, bcrypt)
+			mfaService.EXPECT().
+				SignIn(gomock.Any(), user, []string{jwt.AMRFederated}).
+				Return(dto.LoginResponse{MFARequired: true}, nil)
+
+			oauthService := service.NewOAuthService(
+				oauthRepo,
+				userRepo,
+				sessionService,
+				mfaService,
+				validator.Validator,
+				uuidPkg.UUID,
+				bcrypt,
+				token.Token,
+				timePkg.Time,
+			)
+
+			res, err := oauthService.GoogleCallback(context.Background(), dto.OAuthCallbackRequest{
+				Code:   authCode,
+				State:  plainState,
+				Locale: "id",
+			})
+			require.NoError(t, err)
+			assert.True(t, res.MFARequired)
+		})
+	}
+}
+
+// expectIdentity links the google account to user and reloads it
+func expectIdentity(oauthRepo *oauthMock.MockOAuthRepository, userRepo *userMock.MockUserRepository) {
+	oauthRepo.EXPECT().
+		CreateIdentity(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, identity *entity.UserIdentity) error {
+			if identity.UserID != user.ID || identity.Subject != subject || identity.Email != user.Email {
+				return domain.ErrOAuthAuthenticationFailed
+			}
+
+			return nil
+		})
+	userRepo.EXPECT().FindByID(gomock.Any(), user.ID).Return(user, nil)
+}
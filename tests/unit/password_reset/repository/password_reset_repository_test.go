@@ -0,0 +1,102 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/internal/app/password_reset/repository"
+)
+
+var errConnectionReset = errors.New("connection reset")
+
+const (
+	consumeQuery  = `UPDATE password_reset_tokens SET used_at = $2 WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2 RETURNING id, user_id, token_hash, expires_at, used_at, created_at`
+	passwordQuery = `UPDATE users SET password = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+)
+
+// the token is only burned together with the password change, any failure
+// rolls both back
+func TestPasswordResetRepository_ResetPassword(t *testing.T) {
+	id := uuid.New()
+	userID := uuid.New()
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		invalidToken bool
+		updateErr    error
+		rows         int64
+		wantErr      error
+	}{
+		{
+			name: "reset",
+			rows: 1,
+		},
+		{
+			name:         "used or expired token",
+			invalidToken: true,
+			wantErr:      domain.ErrInvalidPasswordResetToken,
+		},
+		{
+			name:    "account deleted",
+			rows:    0,
+			wantErr: domain.ErrInvalidPasswordResetToken,
+		},
+		{
+			name:      "password not stored",
+			updateErr: errConnectionReset,
+			wantErr:   errConnectionReset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+
+			consume := mock.ExpectQuery(consumeQuery).WithArgs("token-hash", now)
+			if tt.invalidToken {
+				consume.WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			} else {
+				consume.WillReturnRows(
+					sqlmock.NewRows([]string{"id", "user_id", "token_hash", "expires_at", "used_at", "created_at"}).
+						AddRow(id, userID, "token-hash", now.Add(time.Hour), now, now.Add(-time.Minute)),
+				)
+
+				update := mock.ExpectExec(passwordQuery).WithArgs(userID, "hashed-password")
+				if tt.updateErr != nil {
+					update.WillReturnError(tt.updateErr)
+				} else {
+					update.WillReturnResult(sqlmock.NewResult(0, tt.rows))
+				}
+			}
+
+			if tt.wantErr == nil {
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			repo := repository.NewPasswordResetRepository(sqlx.NewDb(db, "pgx"))
+			token, err := repo.ResetPassword(context.Background(), "token-hash", "hashed-password", now)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			if tt.wantErr == nil {
+				assert.Equal(t, userID, token.UserID)
+			}
+		})
+	}
+}
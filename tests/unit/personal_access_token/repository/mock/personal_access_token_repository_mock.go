@@ -0,0 +1,215 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/contracts/personal_access_token_contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=domain/contracts/personal_access_token_contracts.go -destination=tests/unit/personal_access_token/repository/mock/personal_access_token_repository_mock.go -package=repository_mock
+//
+
+// Package repository_mock is a generated GoMock package.
+package repository_mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	dto "github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	entity "github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	jwt "github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPersonalAccessTokenRepository is a mock of PersonalAccessTokenRepository interface.
+type MockPersonalAccessTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPersonalAccessTokenRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPersonalAccessTokenRepositoryMockRecorder is the mock recorder for MockPersonalAccessTokenRepository.
+type MockPersonalAccessTokenRepositoryMockRecorder struct {
+	mock *MockPersonalAccessTokenRepository
+}
+
+// NewMockPersonalAccessTokenRepository creates a new mock instance.
+func NewMockPersonalAccessTokenRepository(ctrl *gomock.Controller) *MockPersonalAccessTokenRepository {
+	mock := &MockPersonalAccessTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockPersonalAccessTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPersonalAccessTokenRepository) EXPECT() *MockPersonalAccessTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPersonalAccessTokenRepository) Create(ctx context.Context, token *entity.PersonalAccessToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPersonalAccessTokenRepositoryMockRecorder) Create(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPersonalAccessTokenRepository)(nil).Create), ctx, token)
+}
+
+// Delete mocks base method.
+func (m *MockPersonalAccessTokenRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPersonalAccessTokenRepositoryMockRecorder) Delete(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPersonalAccessTokenRepository)(nil).Delete), ctx, userID, id)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockPersonalAccessTokenRepository) DeleteExpired(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockPersonalAccessTokenRepositoryMockRecorder) DeleteExpired(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockPersonalAccessTokenRepository)(nil).DeleteExpired), ctx)
+}
+
+// FindByHash mocks base method.
+func (m *MockPersonalAccessTokenRepository) FindByHash(ctx context.Context, tokenHash string) (entity.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByHash", ctx, tokenHash)
+	ret0, _ := ret[0].(entity.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByHash indicates an expected call of FindByHash.
+func (mr *MockPersonalAccessTokenRepositoryMockRecorder) FindByHash(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByHash", reflect.TypeOf((*MockPersonalAccessTokenRepository)(nil).FindByHash), ctx, tokenHash)
+}
+
+// FindByUserID mocks base method.
+func (m *MockPersonalAccessTokenRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]entity.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserID", ctx, userID)
+	ret0, _ := ret[0].([]entity.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserID indicates an expected call of FindByUserID.
+func (mr *MockPersonalAccessTokenRepositoryMockRecorder) FindByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockPersonalAccessTokenRepository)(nil).FindByUserID), ctx, userID)
+}
+
+// TouchLastUsed mocks base method.
+func (m *MockPersonalAccessTokenRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, ip string, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchLastUsed", ctx, id, ip, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchLastUsed indicates an expected call of TouchLastUsed.
+func (mr *MockPersonalAccessTokenRepositoryMockRecorder) TouchLastUsed(ctx, id, ip, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchLastUsed", reflect.TypeOf((*MockPersonalAccessTokenRepository)(nil).TouchLastUsed), ctx, id, ip, now)
+}
+
+// MockPersonalAccessTokenService is a mock of PersonalAccessTokenService interface.
+type MockPersonalAccessTokenService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPersonalAccessTokenServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockPersonalAccessTokenServiceMockRecorder is the mock recorder for MockPersonalAccessTokenService.
+type MockPersonalAccessTokenServiceMockRecorder struct {
+	mock *MockPersonalAccessTokenService
+}
+
+// NewMockPersonalAccessTokenService creates a new mock instance.
+func NewMockPersonalAccessTokenService(ctrl *gomock.Controller) *MockPersonalAccessTokenService {
+	mock := &MockPersonalAccessTokenService{ctrl: ctrl}
+	mock.recorder = &MockPersonalAccessTokenServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPersonalAccessTokenService) EXPECT() *MockPersonalAccessTokenServiceMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockPersonalAccessTokenService) Authenticate(ctx context.Context, token, ip string) (jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, token, ip)
+	ret0, _ := ret[0].(jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockPersonalAccessTokenServiceMockRecorder) Authenticate(ctx, token, ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockPersonalAccessTokenService)(nil).Authenticate), ctx, token, ip)
+}
+
+// Create mocks base method.
+func (m *MockPersonalAccessTokenService) Create(ctx context.Context, userID uuid.UUID, req dto.CreatePersonalAccessTokenRequest) (dto.CreatePersonalAccessTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, req)
+	ret0, _ := ret[0].(dto.CreatePersonalAccessTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPersonalAccessTokenServiceMockRecorder) Create(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPersonalAccessTokenService)(nil).Create), ctx, userID, req)
+}
+
+// List mocks base method.
+func (m *MockPersonalAccessTokenService) List(ctx context.Context, userID uuid.UUID) ([]dto.PersonalAccessTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].([]dto.PersonalAccessTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockPersonalAccessTokenServiceMockRecorder) List(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPersonalAccessTokenService)(nil).List), ctx, userID)
+}
+
+// Revoke mocks base method.
+func (m *MockPersonalAccessTokenService) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockPersonalAccessTokenServiceMockRecorder) Revoke(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockPersonalAccessTokenService)(nil).Revoke), ctx, userID, id)
+}
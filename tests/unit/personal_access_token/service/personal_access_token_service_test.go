@@ -0,0 +1,236 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/app/personal_access_token/service"
+	timeMock "github.com/kelompok1-swe-academya/caper-be/pkg/time/mock"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/token"
+	uuidPkg "github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
+	personalAccessTokenMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/personal_access_token/repository/mock"
+	userMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/user/repository/mock"
+)
+
+const (
+	plainToken = entity.PersonalAccessTokenPrefix + "c2VjcmV0LXNlY3JldC1zZWNyZXQ"
+	ip         = "203.0.113.7"
+)
+
+var (
+	now  = time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	user = entity.User{
+		ID:    uuid.MustParse("0192b7a4-5c1e-7d3a-9f21-6b8e4c2d1a07"),
+		Email: "jane@example.com",
+		Role:  entity.Role{Name: "user"},
+	}
+	personalAccessToken = entity.PersonalAccessToken{
+		ID:        uuid.MustParse("0192b7a4-6d2f-7e4b-8a32-7c9f5d3e2b18"),
+		UserID:    user.ID,
+		TokenHash: token.Token.Hash(plainToken),
+		Scopes:    entity.ScopeProfileRead + " " + entity.ScopeUploadsWrite,
+		ExpiresAt: now.Add(time.Hour),
+	}
+)
+
+func newClock(ctrl *gomock.Controller) *timeMock.MockTimeInterface {
+	clock := timeMock.NewMockTimeInterface(ctrl)
+	clock.EXPECT().Now().Return(now).AnyTimes()
+
+	return clock
+}
+
+func expectFindByHash(
+	personalAccessTokenRepo *personalAccessTokenMock.MockPersonalAccessTokenRepository,
+	personalAccessToken entity.PersonalAccessToken,
+) {
+	personalAccessTokenRepo.EXPECT().
+		FindByHash(gomock.Any(), token.Token.Hash(plainToken)).
+		Return(personalAccessToken, nil)
+}
+
+func TestPersonalAccessTokenService_Authenticate(t *testing.T) {
+	admin := user
+	admin.Role = entity.Role{Name: "admin"}
+	touchErr := errors.New("connection reset")
+
+	tests := []struct {
+		name         string
+		user         entity.User
+		touchErr     error
+		wantRoleName string
+		wantErr      error
+	}{
+		{
+			name:         "authenticated",
+			user:         user,
+			wantRoleName: "user",
+		},
+		{
+			// the role comes from the user, not from when the token was created
+			name:         "current role",
+			user:         admin,
+			wantRoleName: "admin",
+		},
+		{
+			name:     "last use not recorded",
+			user:     user,
+			touchErr: touchErr,
+			wantErr:  touchErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			personalAccessTokenRepo := personalAccessTokenMock.NewMockPersonalAccessTokenRepository(ctrl)
+			userRepo := userMock.NewMockUserRepository(ctrl)
+
+			expectFindByHash(personalAccessTokenRepo, personalAccessToken)
+			userRepo.EXPECT().FindByID(gomock.Any(), user.ID).Return(tt.user, nil)
+			personalAccessTokenRepo.EXPECT().
+				TouchLastUsed(gomock.Any(), personalAccessToken.ID, ip, now).
+				Return(tt.touchErr)
+
+			personalAccessTokenService := service.NewPersonalAccessTokenService(
+				personalAccessTokenRepo,
+				userRepo,
+				validator.Validator,
+				uuidPkg.UUID,
+				token.Token,
+				newClock(ctrl),
+			)
+
+			claims, err := personalAccessTokenService.Authenticate(context.Background(), plainToken, ip)
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			if tt.wantErr == nil {
+				assert.Equal(t, user.ID, claims.UserID)
+				assert.Equal(t, user.ID.String(), claims.Subject)
+				assert.Equal(t, tt.wantRoleName, claims.RoleName)
+				assert.Equal(t, personalAccessToken.ID, claims.PersonalAccessTokenID)
+				assert.Equal(t, []string{entity.ScopeProfileRead, entity.ScopeUploadsWrite}, claims.Scopes)
+			}
+		})
+	}
+}
+
+func TestPersonalAccessTokenService_Authenticate_UnknownToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	personalAccessTokenRepo := personalAccessTokenMock.NewMockPersonalAccessTokenRepository(ctrl)
+	personalAccessTokenRepo.EXPECT().
+		FindByHash(gomock.Any(), token.Token.Hash(plainToken)).
+		Return(entity.PersonalAccessToken{}, domain.ErrPersonalAccessTokenNotFound)
+
+	personalAccessTokenService := service.NewPersonalAccessTokenService(
+		personalAccessTokenRepo,
+		userMock.NewMockUserRepository(ctrl),
+		validator.Validator,
+		uuidPkg.UUID,
+		token.Token,
+		newClock(ctrl),
+	)
+
+	_, err := personalAccessTokenService.Authenticate(context.Background(), plainToken, ip)
+	assert.ErrorIs(t, err, domain.ErrInvalidBearerToken)
+}
+
+func TestPersonalAccessTokenService_Authenticate_Expired(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+	}{
+		{
+			name:      "expires now",
+			expiresAt: now,
+		},
+		{
+			name:      "expired",
+			expiresAt: now.Add(-time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			personalAccessTokenRepo := personalAccessTokenMock.NewMockPersonalAccessTokenRepository(ctrl)
+
+			expired := personalAccessToken
+			expired.ExpiresAt = tt.expiresAt
+			expectFindByHash(personalAccessTokenRepo, expired)
+
+			personalAccessTokenService := service.NewPersonalAccessTokenService(
+				personalAccessTokenRepo,
+				userMock.NewMockUserRepository(ctrl),
+				validator.Validator,
+				uuidPkg.UUID,
+				token.Token,
+				newClock(ctrl),
+			)
+
+			_, err := personalAccessTokenService.Authenticate(context.Background(), plainToken, ip)
+			assert.ErrorIs(t, err, domain.ErrExpiredBearerToken)
+		})
+	}
+}
+
+// a deleted user takes their tokens with them
+func TestPersonalAccessTokenService_Authenticate_DeletedUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	personalAccessTokenRepo := personalAccessTokenMock.NewMockPersonalAccessTokenRepository(ctrl)
+	userRepo := userMock.NewMockUserRepository(ctrl)
+
+	expectFindByHash(personalAccessTokenRepo, personalAccessToken)
+	userRepo.EXPECT().FindByID(gomock.Any(), user.ID).Return(entity.User{}, domain.ErrUserNotFound)
+
+	personalAccessTokenService := service.NewPersonalAccessTokenService(
+		personalAccessTokenRepo,
+		userRepo,
+		validator.Validator,
+		uuidPkg.UUID,
+		token.Token,
+		newClock(ctrl),
+	)
+
+	_, err := personalAccessTokenService.Authenticate(context.Background(), plainToken, ip)
+	assert.ErrorIs(t, err, domain.ErrInvalidBearerToken)
+}
+
+func TestPersonalAccessTokenService_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	personalAccessTokenRepo := personalAccessTokenMock.NewMockPersonalAccessTokenRepository(ctrl)
+
+	used := personalAccessToken
+	used.LastUsedIP = sql.NullString{String: ip, Valid: true}
+	personalAccessTokenRepo.EXPECT().
+		FindByUserID(gomock.Any(), user.ID).
+		Return([]entity.PersonalAccessToken{used}, nil)
+
+	personalAccessTokenService := service.NewPersonalAccessTokenService(
+		personalAccessTokenRepo,
+		userMock.NewMockUserRepository(ctrl),
+		validator.Validator,
+		uuidPkg.UUID,
+		token.Token,
+		newClock(ctrl),
+	)
+
+	res, err := personalAccessTokenService.List(context.Background(), user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, []string{entity.ScopeProfileRead, entity.ScopeUploadsWrite}, res[0].Scopes)
+	assert.Nil(t, res[0].LastUsedAt)
+	if assert.NotNil(t, res[0].LastUsedIP) {
+		assert.Equal(t, ip, *res[0].LastUsedIP)
+	}
+}
@@ -0,0 +1,202 @@
+package ratelimiter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	ratelimiter "github.com/kelompok1-swe-academya/caper-be/pkg/rate_limiter"
+	timeMock "github.com/kelompok1-swe-academya/caper-be/pkg/time/mock"
+)
+
+// windowStart is on a minute boundary, so windows of a minute start there
+var windowStart = time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC)
+
+// step is one request made after moving the clock forward by wait
+type step struct {
+	name           string
+	wait           time.Duration
+	wantAllowed    bool
+	wantRemaining  int
+	wantRetryAfter time.Duration
+}
+
+// movingClock reads the time from now, so a test moves it by changing now
+func movingClock(ctrl *gomock.Controller, now *time.Time) *timeMock.MockTimeInterface {
+	clock := timeMock.NewMockTimeInterface(ctrl)
+	clock.EXPECT().
+		Now().
+		DoAndReturn(func() time.Time {
+			return *now
+		}).
+		AnyTimes()
+
+	return clock
+}
+
+// runSteps makes every request in order against one key
+func runSteps(t *testing.T, policy ratelimiter.Policy, start time.Time, steps []step) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	now := start
+	limiter := ratelimiter.NewMemoryRateLimiter(movingClock(ctrl, &now))
+
+	for _, request := range steps {
+		now = now.Add(request.wait)
+
+		res, err := limiter.Allow(context.Background(), "ip:203.0.113.7", policy)
+		require.NoError(t, err)
+
+		assert.Equal(t, request.wantAllowed, res.Allowed, request.name)
+		assert.Equal(t, policy.Limit, res.Limit, request.name)
+		assert.Equal(t, request.wantRemaining, res.Remaining, request.name)
+		assert.Equal(t, request.wantRetryAfter, res.RetryAfter, request.name)
+	}
+}
+
+func TestMemoryRateLimiter_FixedWindow(t *testing.T) {
+	policy := ratelimiter.Policy{Algorithm: ratelimiter.FixedWindow, Limit: 2, Window: time.Minute}
+
+	runSteps(t, policy, windowStart, []step{
+		{name: "first", wantAllowed: true, wantRemaining: 1},
+		{name: "second", wait: 10 * time.Second, wantAllowed: true},
+		{name: "over the limit", wait: 20 * time.Second, wantRetryAfter: 30 * time.Second},
+		{name: "window over", wait: 30 * time.Second, wantAllowed: true, wantRemaining: 1},
+	})
+}
+
+func TestMemoryRateLimiter_SlidingWindow(t *testing.T) {
+	policy := ratelimiter.Policy{Algorithm: ratelimiter.SlidingWindow, Limit: 5, Window: time.Minute}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "current window full",
+			steps: []step{
+				{name: "1", wait: 10 * time.Second, wantAllowed: true, wantRemaining: 4},
+				{name: "2", wantAllowed: true, wantRemaining: 3},
+				{name: "3", wantAllowed: true, wantRemaining: 2},
+				{name: "4", wantAllowed: true, wantRemaining: 1},
+				{name: "5", wantAllowed: true},
+				// the full window still counts in full when the next one
+				// starts, it has to slide out by a millisecond
+				{name: "6", wantRetryAfter: 50*time.Second + time.Millisecond},
+				{name: "next window", wait: 50 * time.Second, wantRetryAfter: time.Millisecond},
+				{name: "after retry after", wait: time.Millisecond, wantAllowed: true},
+			},
+		},
+		{
+			// 15s into the next window the previous one still counts for
+			// 5*45/60 = 3 requests, so only 2 more fit. The third fits once
+			// that share drops to 2, at 24.001s.
+			name: "previous window still overlaps",
+			steps: []step{
+				{name: "1", wait: 50 * time.Second, wantAllowed: true, wantRemaining: 4},
+				{name: "2", wantAllowed: true, wantRemaining: 3},
+				{name: "3", wantAllowed: true, wantRemaining: 2},
+				{name: "4", wantAllowed: true, wantRemaining: 1},
+				{name: "5", wantAllowed: true},
+				{name: "next window 1", wait: 25 * time.Second, wantAllowed: true, wantRemaining: 1},
+				{name: "next window 2", wantAllowed: true},
+				{name: "next window 3", wantRetryAfter: 9001 * time.Millisecond},
+				{name: "a millisecond early", wait: 9 * time.Second, wantRetryAfter: time.Millisecond},
+				{name: "after retry after", wait: time.Millisecond, wantAllowed: true},
+			},
+		},
+		{
+			name: "idle for a whole window",
+			steps: []step{
+				{name: "1", wait: 50 * time.Second, wantAllowed: true, wantRemaining: 4},
+				{name: "2", wantAllowed: true, wantRemaining: 3},
+				{name: "two windows later", wait: 2 * time.Minute, wantAllowed: true, wantRemaining: 4},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runSteps(t, policy, windowStart, tt.steps)
+		})
+	}
+}
+
+// a bucket of 4 refills a token every 1024ms, which keeps the float math exact
+func TestMemoryRateLimiter_TokenBucket(t *testing.T) {
+	policy := ratelimiter.Policy{Algorithm: ratelimiter.TokenBucket, Limit: 4, Window: 4096 * time.Millisecond}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "burst then refill",
+			steps: []step{
+				{name: "1", wantAllowed: true, wantRemaining: 3},
+				{name: "2", wantAllowed: true, wantRemaining: 2},
+				{name: "3", wantAllowed: true, wantRemaining: 1},
+				{name: "4", wantAllowed: true},
+				{name: "empty", wantRetryAfter: 1025 * time.Millisecond},
+				{name: "half a token", wait: 512 * time.Millisecond, wantRetryAfter: 513 * time.Millisecond},
+				{name: "refilled", wait: 513 * time.Millisecond, wantAllowed: true},
+			},
+		},
+		{
+			name: "never above the limit",
+			steps: []step{
+				{name: "1", wantAllowed: true, wantRemaining: 3},
+				{name: "long idle", wait: time.Hour, wantAllowed: true, wantRemaining: 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runSteps(t, policy, windowStart, tt.steps)
+		})
+	}
+}
+
+func TestMemoryRateLimiter_TokenBucket_ResetAfter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	now := windowStart
+	limiter := ratelimiter.NewMemoryRateLimiter(movingClock(ctrl, &now))
+	policy := ratelimiter.Policy{Algorithm: ratelimiter.TokenBucket, Limit: 4, Window: 4096 * time.Millisecond}
+
+	res, err := limiter.Allow(context.Background(), "ip:203.0.113.7", policy)
+	require.NoError(t, err)
+	assert.Equal(t, 1024*time.Millisecond, res.ResetAfter, "one token to refill")
+
+	for range 3 {
+		res, err = limiter.Allow(context.Background(), "ip:203.0.113.7", policy)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, policy.Window, res.ResetAfter, "the whole bucket to refill")
+}
+
+// keys are counted apart
+func TestMemoryRateLimiter_Keys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	now := windowStart
+	limiter := ratelimiter.NewMemoryRateLimiter(movingClock(ctrl, &now))
+	policy := ratelimiter.Policy{Algorithm: ratelimiter.SlidingWindow, Limit: 1, Window: time.Minute}
+
+	res, err := limiter.Allow(context.Background(), "ip:203.0.113.7", policy)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	res, err = limiter.Allow(context.Background(), "ip:203.0.113.7", policy)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+
+	res, err = limiter.Allow(context.Background(), "ip:198.51.100.20", policy)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+}
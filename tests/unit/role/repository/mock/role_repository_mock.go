@@ -0,0 +1,357 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/contracts/role_contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=domain/contracts/role_contracts.go -destination=tests/unit/role/repository/mock/role_repository_mock.go -package=repository_mock
+//
+
+// Package repository_mock is a generated GoMock package.
+package repository_mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	dto "github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	entity "github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRoleRepository is a mock of RoleRepository interface.
+type MockRoleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRoleRepositoryMockRecorder is the mock recorder for MockRoleRepository.
+type MockRoleRepositoryMockRecorder struct {
+	mock *MockRoleRepository
+}
+
+// NewMockRoleRepository creates a new mock instance.
+func NewMockRoleRepository(ctrl *gomock.Controller) *MockRoleRepository {
+	mock := &MockRoleRepository{ctrl: ctrl}
+	mock.recorder = &MockRoleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleRepository) EXPECT() *MockRoleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AttachPermission mocks base method.
+func (m *MockRoleRepository) AttachPermission(ctx context.Context, roleID, permissionID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachPermission", ctx, roleID, permissionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachPermission indicates an expected call of AttachPermission.
+func (mr *MockRoleRepositoryMockRecorder) AttachPermission(ctx, roleID, permissionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachPermission", reflect.TypeOf((*MockRoleRepository)(nil).AttachPermission), ctx, roleID, permissionID)
+}
+
+// Create mocks base method.
+func (m *MockRoleRepository) Create(ctx context.Context, role *entity.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRoleRepositoryMockRecorder) Create(ctx, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRoleRepository)(nil).Create), ctx, role)
+}
+
+// CreatePermission mocks base method.
+func (m *MockRoleRepository) CreatePermission(ctx context.Context, permission *entity.Permission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePermission", ctx, permission)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePermission indicates an expected call of CreatePermission.
+func (mr *MockRoleRepositoryMockRecorder) CreatePermission(ctx, permission any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePermission", reflect.TypeOf((*MockRoleRepository)(nil).CreatePermission), ctx, permission)
+}
+
+// DetachPermission mocks base method.
+func (m *MockRoleRepository) DetachPermission(ctx context.Context, roleID, permissionID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachPermission", ctx, roleID, permissionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachPermission indicates an expected call of DetachPermission.
+func (mr *MockRoleRepositoryMockRecorder) DetachPermission(ctx, roleID, permissionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachPermission", reflect.TypeOf((*MockRoleRepository)(nil).DetachPermission), ctx, roleID, permissionID)
+}
+
+// FindAll mocks base method.
+func (m *MockRoleRepository) FindAll(ctx context.Context) ([]entity.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx)
+	ret0, _ := ret[0].([]entity.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockRoleRepositoryMockRecorder) FindAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockRoleRepository)(nil).FindAll), ctx)
+}
+
+// FindAllPermissions mocks base method.
+func (m *MockRoleRepository) FindAllPermissions(ctx context.Context) ([]entity.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllPermissions", ctx)
+	ret0, _ := ret[0].([]entity.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAllPermissions indicates an expected call of FindAllPermissions.
+func (mr *MockRoleRepositoryMockRecorder) FindAllPermissions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllPermissions", reflect.TypeOf((*MockRoleRepository)(nil).FindAllPermissions), ctx)
+}
+
+// FindByID mocks base method.
+func (m *MockRoleRepository) FindByID(ctx context.Context, id int) (entity.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(entity.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockRoleRepositoryMockRecorder) FindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockRoleRepository)(nil).FindByID), ctx, id)
+}
+
+// FindImpliedRoles mocks base method.
+func (m *MockRoleRepository) FindImpliedRoles(ctx context.Context, roleName string) ([]entity.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindImpliedRoles", ctx, roleName)
+	ret0, _ := ret[0].([]entity.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindImpliedRoles indicates an expected call of FindImpliedRoles.
+func (mr *MockRoleRepositoryMockRecorder) FindImpliedRoles(ctx, roleName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindImpliedRoles", reflect.TypeOf((*MockRoleRepository)(nil).FindImpliedRoles), ctx, roleName)
+}
+
+// FindPermissionByID mocks base method.
+func (m *MockRoleRepository) FindPermissionByID(ctx context.Context, id int) (entity.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPermissionByID", ctx, id)
+	ret0, _ := ret[0].(entity.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPermissionByID indicates an expected call of FindPermissionByID.
+func (mr *MockRoleRepositoryMockRecorder) FindPermissionByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPermissionByID", reflect.TypeOf((*MockRoleRepository)(nil).FindPermissionByID), ctx, id)
+}
+
+// FindPermissionsByRoleName mocks base method.
+func (m *MockRoleRepository) FindPermissionsByRoleName(ctx context.Context, roleName string) ([]entity.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPermissionsByRoleName", ctx, roleName)
+	ret0, _ := ret[0].([]entity.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPermissionsByRoleName indicates an expected call of FindPermissionsByRoleName.
+func (mr *MockRoleRepositoryMockRecorder) FindPermissionsByRoleName(ctx, roleName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPermissionsByRoleName", reflect.TypeOf((*MockRoleRepository)(nil).FindPermissionsByRoleName), ctx, roleName)
+}
+
+// MockRoleService is a mock of RoleService interface.
+type MockRoleService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockRoleServiceMockRecorder is the mock recorder for MockRoleService.
+type MockRoleServiceMockRecorder struct {
+	mock *MockRoleService
+}
+
+// NewMockRoleService creates a new mock instance.
+func NewMockRoleService(ctrl *gomock.Controller) *MockRoleService {
+	mock := &MockRoleService{ctrl: ctrl}
+	mock.recorder = &MockRoleServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleService) EXPECT() *MockRoleServiceMockRecorder {
+	return m.recorder
+}
+
+// AttachPermission mocks base method.
+func (m *MockRoleService) AttachPermission(ctx context.Context, roleID, permissionID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachPermission", ctx, roleID, permissionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachPermission indicates an expected call of AttachPermission.
+func (mr *MockRoleServiceMockRecorder) AttachPermission(ctx, roleID, permissionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachPermission", reflect.TypeOf((*MockRoleService)(nil).AttachPermission), ctx, roleID, permissionID)
+}
+
+// CreatePermission mocks base method.
+func (m *MockRoleService) CreatePermission(ctx context.Context, req dto.CreatePermissionRequest) (dto.PermissionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePermission", ctx, req)
+	ret0, _ := ret[0].(dto.PermissionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePermission indicates an expected call of CreatePermission.
+func (mr *MockRoleServiceMockRecorder) CreatePermission(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePermission", reflect.TypeOf((*MockRoleService)(nil).CreatePermission), ctx, req)
+}
+
+// CreateRole mocks base method.
+func (m *MockRoleService) CreateRole(ctx context.Context, req dto.CreateRoleRequest) (dto.RoleResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRole", ctx, req)
+	ret0, _ := ret[0].(dto.RoleResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRole indicates an expected call of CreateRole.
+func (mr *MockRoleServiceMockRecorder) CreateRole(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRole", reflect.TypeOf((*MockRoleService)(nil).CreateRole), ctx, req)
+}
+
+// DetachPermission mocks base method.
+func (m *MockRoleService) DetachPermission(ctx context.Context, roleID, permissionID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachPermission", ctx, roleID, permissionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachPermission indicates an expected call of DetachPermission.
+func (mr *MockRoleServiceMockRecorder) DetachPermission(ctx, roleID, permissionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachPermission", reflect.TypeOf((*MockRoleService)(nil).DetachPermission), ctx, roleID, permissionID)
+}
+
+// GetPermissions mocks base method.
+func (m *MockRoleService) GetPermissions(ctx context.Context) ([]dto.PermissionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPermissions", ctx)
+	ret0, _ := ret[0].([]dto.PermissionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPermissions indicates an expected call of GetPermissions.
+func (mr *MockRoleServiceMockRecorder) GetPermissions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPermissions", reflect.TypeOf((*MockRoleService)(nil).GetPermissions), ctx)
+}
+
+// GetRoles mocks base method.
+func (m *MockRoleService) GetRoles(ctx context.Context) ([]dto.RoleResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoles", ctx)
+	ret0, _ := ret[0].([]dto.RoleResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoles indicates an expected call of GetRoles.
+func (mr *MockRoleServiceMockRecorder) GetRoles(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoles", reflect.TypeOf((*MockRoleService)(nil).GetRoles), ctx)
+}
+
+// GetUserPermissions mocks base method.
+func (m *MockRoleService) GetUserPermissions(ctx context.Context, userID uuid.UUID) (dto.UserPermissionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPermissions", ctx, userID)
+	ret0, _ := ret[0].(dto.UserPermissionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPermissions indicates an expected call of GetUserPermissions.
+func (mr *MockRoleServiceMockRecorder) GetUserPermissions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPermissions", reflect.TypeOf((*MockRoleService)(nil).GetUserPermissions), ctx, userID)
+}
+
+// HasAllPermissions mocks base method.
+func (m *MockRoleService) HasAllPermissions(ctx context.Context, roleName string, required ...string) (bool, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, roleName}
+	for _, a := range required {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "HasAllPermissions", varargs...)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasAllPermissions indicates an expected call of HasAllPermissions.
+func (mr *MockRoleServiceMockRecorder) HasAllPermissions(ctx, roleName any, required ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, roleName}, required...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasAllPermissions", reflect.TypeOf((*MockRoleService)(nil).HasAllPermissions), varargs...)
+}
+
+// HasAnyRole mocks base method.
+func (m *MockRoleService) HasAnyRole(ctx context.Context, roleName string, required ...string) (bool, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, roleName}
+	for _, a := range required {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "HasAnyRole", varargs...)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasAnyRole indicates an expected call of HasAnyRole.
+func (mr *MockRoleServiceMockRecorder) HasAnyRole(ctx, roleName any, required ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, roleName}, required...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasAnyRole", reflect.TypeOf((*MockRoleService)(nil).HasAnyRole), varargs...)
+}
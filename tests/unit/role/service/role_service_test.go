@@ -0,0 +1,248 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/app/role/service"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/cache"
+	cacheMock "github.com/kelompok1-swe-academya/caper-be/pkg/cache/mock"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
+	roleMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/role/repository/mock"
+	userMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/user/repository/mock"
+)
+
+var errDatabase = errors.New("database unavailable")
+
+// expectAdmin resolves admin to itself and the user role it inherits from,
+// and expects the hierarchy to be loaded from the database times times
+func expectAdmin(roleRepo *roleMock.MockRoleRepository, times int) {
+	roleRepo.EXPECT().
+		FindImpliedRoles(gomock.Any(), "admin").
+		Return([]entity.Role{{ID: 1, Name: "admin"}, {ID: 2, Name: "user"}}, nil).
+		Times(times)
+
+	roleRepo.EXPECT().
+		FindPermissionsByRoleName(gomock.Any(), "admin").
+		Return([]entity.Permission{{ID: 1, Name: "users:read"}, {ID: 2, Name: "users:write"}}, nil).
+		Times(times)
+}
+
+func TestRoleService_HasAnyRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		required []string
+		want     bool
+	}{
+		{
+			name:     "own role",
+			required: []string{"admin"},
+			want:     true,
+		},
+		{
+			name:     "inherited role",
+			required: []string{"user"},
+			want:     true,
+		},
+		{
+			name:     "one of several",
+			required: []string{"superadmin", "user"},
+			want:     true,
+		},
+		{
+			name:     "not implied",
+			required: []string{"superadmin"},
+		},
+		{
+			name: "nothing required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			roleRepo := roleMock.NewMockRoleRepository(ctrl)
+			expectAdmin(roleRepo, 1)
+
+			roleService := service.NewRoleService(
+				roleRepo,
+				userMock.NewMockUserRepository(ctrl),
+				validator.Validator,
+				cache.NewMemoryCache(10),
+			)
+
+			got, err := roleService.HasAnyRole(context.Background(), "admin", tt.required...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRoleService_HasAllPermissions(t *testing.T) {
+	tests := []struct {
+		name     string
+		required []string
+		want     bool
+	}{
+		{
+			name:     "holds every permission",
+			required: []string{"users:read", "users:write"},
+			want:     true,
+		},
+		{
+			name:     "missing one",
+			required: []string{"users:read", "roles:write"},
+		},
+		{
+			name: "nothing required",
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			roleRepo := roleMock.NewMockRoleRepository(ctrl)
+			expectAdmin(roleRepo, 1)
+
+			roleService := service.NewRoleService(
+				roleRepo,
+				userMock.NewMockUserRepository(ctrl),
+				validator.Validator,
+				cache.NewMemoryCache(10),
+			)
+
+			got, err := roleService.HasAllPermissions(context.Background(), "admin", tt.required...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// the hierarchy is cached for ROLE_CACHE_TTL under the shared tag
+func TestRoleService_CachesAccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	roleRepo := roleMock.NewMockRoleRepository(ctrl)
+	cacheProvider := cacheMock.NewMockCacheInterface(ctrl)
+
+	cacheProvider.EXPECT().
+		GetOrLoad(gomock.Any(), "role_access:admin", env.AppEnv.RoleCacheTTL, gomock.Any(), "role_access").
+		Return([]byte(`{"roles":["admin","user"],"permissions":["users:read"]}`), nil)
+
+	roleService := service.NewRoleService(
+		roleRepo,
+		userMock.NewMockUserRepository(ctrl),
+		validator.Validator,
+		cacheProvider,
+	)
+
+	got, err := roleService.HasAnyRole(context.Background(), "admin", "user")
+	require.NoError(t, err)
+	assert.True(t, got)
+}
+
+func TestRoleService_CachesAccess_LoadError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	roleRepo := roleMock.NewMockRoleRepository(ctrl)
+	roleRepo.EXPECT().FindImpliedRoles(gomock.Any(), "admin").Return(nil, errDatabase)
+
+	roleService := service.NewRoleService(
+		roleRepo,
+		userMock.NewMockUserRepository(ctrl),
+		validator.Validator,
+		cache.NewMemoryCache(10),
+	)
+
+	_, err := roleService.HasAnyRole(context.Background(), "admin", "admin")
+	assert.ErrorIs(t, err, errDatabase)
+
+	// a failed load is not cached
+	expectAdmin(roleRepo, 1)
+
+	got, err := roleService.HasAnyRole(context.Background(), "admin", "admin")
+	require.NoError(t, err)
+	assert.True(t, got)
+}
+
+// every change drops the cached hierarchy, so the next check reloads it
+func TestRoleService_Invalidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(roleRepo *roleMock.MockRoleRepository, roleService contracts.RoleService) error
+	}{
+		{
+			name: "permission attached",
+			change: func(roleRepo *roleMock.MockRoleRepository, roleService contracts.RoleService) error {
+				roleRepo.EXPECT().FindByID(gomock.Any(), 1).Return(entity.Role{ID: 1, Name: "admin"}, nil)
+				roleRepo.EXPECT().FindPermissionByID(gomock.Any(), 3).Return(entity.Permission{ID: 3}, nil)
+				roleRepo.EXPECT().AttachPermission(gomock.Any(), 1, 3).Return(nil)
+
+				return roleService.AttachPermission(context.Background(), 1, 3)
+			},
+		},
+		{
+			name: "permission detached",
+			change: func(roleRepo *roleMock.MockRoleRepository, roleService contracts.RoleService) error {
+				roleRepo.EXPECT().FindByID(gomock.Any(), 1).Return(entity.Role{ID: 1, Name: "admin"}, nil)
+				roleRepo.EXPECT().FindPermissionByID(gomock.Any(), 2).Return(entity.Permission{ID: 2}, nil)
+				roleRepo.EXPECT().DetachPermission(gomock.Any(), 1, 2).Return(nil)
+
+				return roleService.DetachPermission(context.Background(), 1, 2)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			roleRepo := roleMock.NewMockRoleRepository(ctrl)
+			expectAdmin(roleRepo, 2)
+
+			roleService := service.NewRoleService(
+				roleRepo,
+				userMock.NewMockUserRepository(ctrl),
+				validator.Validator,
+				cache.NewMemoryCache(10),
+			)
+
+			for range 2 {
+				_, err := roleService.HasAnyRole(context.Background(), "admin", "admin")
+				require.NoError(t, err)
+			}
+
+			require.NoError(t, tt.change(roleRepo, roleService))
+
+			_, err := roleService.HasAnyRole(context.Background(), "admin", "admin")
+			require.NoError(t, err)
+		})
+	}
+}
+
+// the change is already stored, a cache that can't be reached doesn't fail it
+func TestRoleService_Invalidate_CacheError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	roleRepo := roleMock.NewMockRoleRepository(ctrl)
+	cacheProvider := cacheMock.NewMockCacheInterface(ctrl)
+
+	roleRepo.EXPECT().FindByID(gomock.Any(), 1).Return(entity.Role{ID: 1, Name: "admin"}, nil)
+	roleRepo.EXPECT().FindPermissionByID(gomock.Any(), 3).Return(entity.Permission{ID: 3}, nil)
+	roleRepo.EXPECT().AttachPermission(gomock.Any(), 1, 3).Return(nil)
+	cacheProvider.EXPECT().InvalidateTags(gomock.Any(), "role_access").Return(errors.New("connection refused"))
+
+	roleService := service.NewRoleService(
+		roleRepo,
+		userMock.NewMockUserRepository(ctrl),
+		validator.Validator,
+		cacheProvider,
+	)
+
+	assert.NoError(t, roleService.AttachPermission(context.Background(), 1, 3))
+}
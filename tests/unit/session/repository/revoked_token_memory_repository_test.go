@@ -0,0 +1,112 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kelompok1-swe-academya/caper-be/internal/app/session/repository"
+	timeMock "github.com/kelompok1-swe-academya/caper-be/pkg/time/mock"
+)
+
+var (
+	userID = uuid.MustParse("0192b7a4-5c1e-7d3a-9f21-6b8e4c2d1a07")
+	cutoff = time.Date(2026, 10, 17, 9, 30, 1, 0, time.UTC)
+)
+
+// a user revocation rejects tokens issued strictly before the cutoff, a
+// token is valid again once its iat is at or after it
+func TestRevokedTokenMemoryRepository_IsRevoked_UserCutoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		issuedAt time.Time
+		now      time.Time
+		want     bool
+	}{
+		{
+			name:     "issued a second before the cutoff",
+			issuedAt: cutoff.Add(-time.Second),
+			now:      cutoff,
+			want:     true,
+		},
+		{
+			name:     "issued at the cutoff",
+			issuedAt: cutoff,
+			now:      cutoff,
+		},
+		{
+			name:     "issued after the cutoff",
+			issuedAt: cutoff.Add(time.Second),
+			now:      cutoff.Add(time.Second),
+		},
+		{
+			// the revocation only has to outlive the tokens it rejects
+			name:     "revocation expired",
+			issuedAt: cutoff.Add(-time.Second),
+			now:      cutoff.Add(time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			clock := timeMock.NewMockTimeInterface(ctrl)
+			clock.EXPECT().Now().Return(tt.now).AnyTimes()
+
+			revokedTokenRepo := repository.NewRevokedTokenMemoryRepository(clock)
+			err := revokedTokenRepo.RevokeUserTokens(context.Background(), userID, cutoff, cutoff.Add(15*time.Minute))
+			require.NoError(t, err)
+
+			got, err := revokedTokenRepo.IsRevoked(context.Background(), uuid.NewString(), userID, tt.issuedAt)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRevokedTokenMemoryRepository_IsRevoked_Token(t *testing.T) {
+	tests := []struct {
+		name string
+		jti  string
+		now  time.Time
+		want bool
+	}{
+		{
+			name: "revoked",
+			jti:  "revoked",
+			now:  cutoff,
+			want: true,
+		},
+		{
+			name: "other token",
+			jti:  "other",
+			now:  cutoff,
+		},
+		{
+			name: "revocation expired",
+			jti:  "revoked",
+			now:  cutoff.Add(15 * time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			clock := timeMock.NewMockTimeInterface(ctrl)
+			clock.EXPECT().Now().Return(tt.now).AnyTimes()
+
+			revokedTokenRepo := repository.NewRevokedTokenMemoryRepository(clock)
+			err := revokedTokenRepo.RevokeToken(context.Background(), "revoked", userID, cutoff.Add(15*time.Minute))
+			require.NoError(t, err)
+
+			got, err := revokedTokenRepo.IsRevoked(context.Background(), tt.jti, userID, cutoff)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,380 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	jwtLib "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/app/session/service"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
+	jwtMock "github.com/kelompok1-swe-academya/caper-be/pkg/jwt/mock"
+	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+	timeMock "github.com/kelompok1-swe-academya/caper-be/pkg/time/mock"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/token"
+	uuidPkg "github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
+	sessionMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/session/repository/mock"
+	userMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/user/repository/mock"
+)
+
+const plainRefreshToken = "cmVmcmVzaC10b2tlbi1wbGFpbg"
+
+var (
+	user = entity.User{
+		ID:   uuid.MustParse("0192b7a4-5c1e-7d3a-9f21-6b8e4c2d1a07"),
+		Role: entity.Role{Name: "user"},
+	}
+	now = time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC)
+)
+
+// newClock stops the time at now
+func newClock(ctrl *gomock.Controller) *timeMock.MockTimeInterface {
+	clock := timeMock.NewMockTimeInterface(ctrl)
+	clock.EXPECT().Now().Return(now).AnyTimes()
+	clock.EXPECT().
+		Add(gomock.Any()).
+		DoAndReturn(func(duration time.Duration) time.Time {
+			return now.Add(duration)
+		}).
+		AnyTimes()
+
+	return clock
+}
+
+// storedRefreshToken is the refresh token plainRefreshToken was issued as
+func storedRefreshToken() entity.RefreshToken {
+	return entity.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		FamilyID:  uuid.MustParse("0192b7a4-6d2f-7e4b-8a32-7c9f5d3e2b18"),
+		TokenHash: token.Token.Hash(plainRefreshToken),
+		AMR:       jwt.AMRPassword + " " + jwt.AMROTP,
+		ExpiresAt: now.Add(time.Hour),
+	}
+}
+
+func TestSessionService_Refresh(t *testing.T) {
+	tests := []struct {
+		name    string
+		stored  func(stored *entity.RefreshToken)
+		userErr error
+		// rotateErr is what Rotate returns when another request used the
+		// token between FindByHash and Rotate
+		rotateErr         error
+		wantRotate        bool
+		wantFamilyRevoked bool
+		wantErr           error
+	}{
+		{
+			name:       "rotated",
+			wantRotate: true,
+		},
+		{
+			name: "revoked",
+			stored: func(stored *entity.RefreshToken) {
+				stored.RevokedAt = sql.NullTime{Time: now.Add(-time.Minute), Valid: true}
+			},
+			wantErr: domain.ErrInvalidRefreshToken,
+		},
+		{
+			name: "already used",
+			stored: func(stored *entity.RefreshToken) {
+				stored.UsedAt = sql.NullTime{Time: now.Add(-time.Minute), Valid: true}
+			},
+			wantFamilyRevoked: true,
+			wantErr:           domain.ErrRefreshTokenReused,
+		},
+		{
+			// a reused token is revoked even after it expired
+			name: "already used and expired",
+			stored: func(stored *entity.RefreshToken) {
+				stored.UsedAt = sql.NullTime{Time: now.Add(-2 * time.Hour), Valid: true}
+				stored.ExpiresAt = now.Add(-time.Hour)
+			},
+			wantFamilyRevoked: true,
+			wantErr:           domain.ErrRefreshTokenReused,
+		},
+		{
+			name: "expired",
+			stored: func(stored *entity.RefreshToken) {
+				stored.ExpiresAt = now
+			},
+			wantErr: domain.ErrExpiredRefreshToken,
+		},
+		{
+			name:    "user deleted",
+			userErr: domain.ErrUserNotFound,
+			wantErr: domain.ErrInvalidRefreshToken,
+		},
+		{
+			name:              "used concurrently",
+			rotateErr:         domain.ErrRefreshTokenReused,
+			wantFamilyRevoked: true,
+			wantErr:           domain.ErrRefreshTokenReused,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			refreshTokenRepo := sessionMock.NewMockRefreshTokenRepository(ctrl)
+			userRepo := userMock.NewMockUserRepository(ctrl)
+			jwtProvider := jwtMock.NewMockJwtInterface(ctrl)
+
+			stored := storedRefreshToken()
+			if tt.stored != nil {
+				tt.stored(&stored)
+			}
+
+			refreshTokenRepo.EXPECT().FindByHash(gomock.Any(), stored.TokenHash).Return(stored, nil)
+
+			if tt.userErr != nil || tt.rotateErr != nil || tt.wantRotate {
+				userRepo.EXPECT().FindByID(gomock.Any(), user.ID).Return(user, tt.userErr)
+			}
+
+			var next entity.RefreshToken
+			if tt.rotateErr != nil || tt.wantRotate {
+				refreshTokenRepo.EXPECT().
+					Rotate(gomock.Any(), stored.ID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ uuid.UUID, token *entity.RefreshToken) error {
+						next = *token
+						return tt.rotateErr
+					})
+			}
+
+			if tt.wantRotate {
+				jwtProvider.EXPECT().
+					Create(user.ID, user.Role.Name, []string{jwt.AMRPassword, jwt.AMROTP}).
+					Return("access-token", nil)
+			}
+
+			if tt.wantFamilyRevoked {
+				refreshTokenRepo.EXPECT().RevokeFamily(gomock.Any(), stored.FamilyID).Return(nil)
+			}
+
+			sessionService := service.NewSessionService(
+				refreshTokenRepo,
+				sessionMock.NewMockRevokedTokenRepository(ctrl),
+				userRepo,
+				validator.Validator,
+				uuidPkg.UUID,
+				jwtProvider,
+				token.Token,
+				newClock(ctrl),
+			)
+
+			res, err := sessionService.Refresh(context.Background(), dto.RefreshTokenRequest{
+				RefreshToken: plainRefreshToken,
+			})
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			if tt.wantRotate {
+				assert.Equal(t, "access-token", res.AccessToken)
+				assert.NotEqual(t, plainRefreshToken, res.RefreshToken)
+				assert.Equal(t, token.Token.Hash(res.RefreshToken), next.TokenHash)
+				assert.Equal(t, stored.FamilyID, next.FamilyID, "the rotated token stays in the family")
+				assert.Equal(t, stored.AMR, next.AMR)
+				assert.Equal(t, now.Add(env.AppEnv.RefreshTokenExpTime), next.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestSessionService_Logout(t *testing.T) {
+	tests := []struct {
+		name              string
+		refreshToken      string
+		owner             uuid.UUID
+		wantFamilyRevoked bool
+		wantErr           error
+	}{
+		{
+			name: "access token only",
+		},
+		{
+			name:              "with its refresh token",
+			refreshToken:      plainRefreshToken,
+			owner:             user.ID,
+			wantFamilyRevoked: true,
+		},
+		{
+			// a refresh token of someone else can't be used to end their session
+			name:         "refresh token of another user",
+			refreshToken: plainRefreshToken,
+			owner:        uuid.New(),
+			wantErr:      domain.ErrInvalidRefreshToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			refreshTokenRepo := sessionMock.NewMockRefreshTokenRepository(ctrl)
+			revokedTokenRepo := sessionMock.NewMockRevokedTokenRepository(ctrl)
+
+			claims := jwt.Claims{UserID: user.ID}
+			claims.ID = "access-token-id"
+			claims.ExpiresAt = jwtLib.NewNumericDate(now.Add(15 * time.Minute))
+
+			revokedTokenRepo.EXPECT().RevokeToken(gomock.Any(), claims.ID, user.ID, claims.ExpiresAt.Time).Return(nil)
+
+			stored := storedRefreshToken()
+			stored.UserID = tt.owner
+
+			if tt.refreshToken != "" {
+				refreshTokenRepo.EXPECT().FindByHash(gomock.Any(), stored.TokenHash).Return(stored, nil)
+			}
+
+			if tt.wantFamilyRevoked {
+				refreshTokenRepo.EXPECT().RevokeFamily(gomock.Any(), stored.FamilyID).Return(nil)
+			}
+
+			sessionService := service.NewSessionService(
+				refreshTokenRepo,
+				revokedTokenRepo,
+				userMock.NewMockUserRepository(ctrl),
+				validator.Validator,
+				uuidPkg.UUID,
+				jwtMock.NewMockJwtInterface(ctrl),
+				token.Token,
+				newClock(ctrl),
+			)
+
+			err := sessionService.Logout(context.Background(), claims, dto.LogoutRequest{
+				RefreshToken: tt.refreshToken,
+			})
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+// the cutoff is rounded up so tokens issued earlier in the same second are
+// rejected as well
+func TestSessionService_RevokeUserSessions(t *testing.T) {
+	tests := []struct {
+		name       string
+		now        time.Time
+		wantCutoff time.Time
+	}{
+		{
+			name:       "within a second",
+			now:        time.Date(2026, 10, 17, 9, 30, 0, 400_000_000, time.UTC),
+			wantCutoff: time.Date(2026, 10, 17, 9, 30, 1, 0, time.UTC),
+		},
+		{
+			name:       "on a whole second",
+			now:        time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC),
+			wantCutoff: time.Date(2026, 10, 17, 9, 30, 1, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			refreshTokenRepo := sessionMock.NewMockRefreshTokenRepository(ctrl)
+			revokedTokenRepo := sessionMock.NewMockRevokedTokenRepository(ctrl)
+			clock := timeMock.NewMockTimeInterface(ctrl)
+
+			clock.EXPECT().Now().Return(tt.now)
+			revokedTokenRepo.EXPECT().
+				RevokeUserTokens(gomock.Any(), user.ID, tt.wantCutoff, tt.wantCutoff.Add(env.AppEnv.JwtExpTime)).
+				Return(nil)
+			refreshTokenRepo.EXPECT().RevokeByUserID(gomock.Any(), user.ID).Return(nil)
+
+			sessionService := service.NewSessionService(
+				refreshTokenRepo,
+				revokedTokenRepo,
+				userMock.NewMockUserRepository(ctrl),
+				validator.Validator,
+				uuidPkg.UUID,
+				jwtMock.NewMockJwtInterface(ctrl),
+				token.Token,
+				clock,
+			)
+
+			require.NoError(t, sessionService.RevokeUserSessions(context.Background(), user.ID))
+		})
+	}
+}
+
+// the replacement access token is only signed once the cutoff has passed,
+// otherwise its iat would fall before it and the caller would be signed out too
+func TestSessionService_RevokeOtherSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	refreshTokenRepo := sessionMock.NewMockRefreshTokenRepository(ctrl)
+	revokedTokenRepo := sessionMock.NewMockRevokedTokenRepository(ctrl)
+	jwtProvider := jwtMock.NewMockJwtInterface(ctrl)
+
+	var cutoff, signedAt time.Time
+
+	revokedTokenRepo.EXPECT().
+		RevokeUserTokens(gomock.Any(), user.ID, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, revokedBefore time.Time, _ time.Time) error {
+			cutoff = revokedBefore
+			return nil
+		})
+	refreshTokenRepo.EXPECT().RevokeByUserID(gomock.Any(), user.ID).Return(nil)
+	refreshTokenRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	jwtProvider.EXPECT().
+		Create(user.ID, user.Role.Name, []string{jwt.AMRPassword}).
+		DoAndReturn(func(uuid.UUID, string, []string) (string, error) {
+			signedAt = time.Now()
+			return "access-token", nil
+		})
+
+	sessionService := service.NewSessionService(
+		refreshTokenRepo,
+		revokedTokenRepo,
+		userMock.NewMockUserRepository(ctrl),
+		validator.Validator,
+		uuidPkg.UUID,
+		jwtProvider,
+		token.Token,
+		timePkg.Time,
+	)
+
+	res, err := sessionService.RevokeOtherSessions(context.Background(), user, []string{jwt.AMRPassword})
+	require.NoError(t, err)
+	assert.Equal(t, "access-token", res.AccessToken)
+	assert.False(t, signedAt.Truncate(time.Second).Before(cutoff), "iat %s is before the cutoff %s", signedAt, cutoff)
+}
+
+func TestSessionService_RevokeOtherSessions_Canceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	refreshTokenRepo := sessionMock.NewMockRefreshTokenRepository(ctrl)
+	revokedTokenRepo := sessionMock.NewMockRevokedTokenRepository(ctrl)
+	clock := timeMock.NewMockTimeInterface(ctrl)
+
+	// a cutoff an hour away never passes before the request is canceled
+	clock.EXPECT().Now().Return(time.Now().Add(time.Hour))
+	revokedTokenRepo.EXPECT().RevokeUserTokens(gomock.Any(), user.ID, gomock.Any(), gomock.Any()).Return(nil)
+	refreshTokenRepo.EXPECT().RevokeByUserID(gomock.Any(), user.ID).Return(nil)
+
+	sessionService := service.NewSessionService(
+		refreshTokenRepo,
+		revokedTokenRepo,
+		userMock.NewMockUserRepository(ctrl),
+		validator.Validator,
+		uuidPkg.UUID,
+		jwtMock.NewMockJwtInterface(ctrl),
+		token.Token,
+		clock,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := sessionService.RevokeOtherSessions(ctx, user, []string{jwt.AMRPassword})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,74 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/internal/app/user/repository"
+)
+
+const (
+	purgeQuery      = `DELETE FROM users WHERE id = $1 AND deleted_at IS NOT NULL AND deleted_at <= $2 RETURNING email, pending_email`
+	purgeMailsQuery = `DELETE FROM mail_outbox WHERE recipient = $1 OR recipient = $2`
+)
+
+// the cutoff is checked again when the row is deleted, so a user restored
+// or deleted again after being listed is not erased
+func TestUserRepository_Purge(t *testing.T) {
+	id := uuid.New()
+	cutoff := time.Date(2026, 9, 17, 3, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		deleted bool
+		wantErr error
+	}{
+		{
+			name:    "deleted before the cutoff",
+			deleted: true,
+		},
+		{
+			name:    "restored or deleted after the cutoff",
+			wantErr: domain.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+
+			purge := mock.ExpectQuery(purgeQuery).WithArgs(id, cutoff)
+			if tt.deleted {
+				purge.WillReturnRows(
+					sqlmock.NewRows([]string{"email", "pending_email"}).
+						AddRow("jane@example.com", sql.NullString{String: "jane.doe@example.com", Valid: true}),
+				)
+				mock.ExpectExec(purgeMailsQuery).
+					WithArgs("jane@example.com", sql.NullString{String: "jane.doe@example.com", Valid: true}).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			} else {
+				purge.WillReturnRows(sqlmock.NewRows([]string{"email", "pending_email"}))
+				mock.ExpectRollback()
+			}
+
+			repo := repository.NewUserRepository(sqlx.NewDb(db, "pgx"))
+			err = repo.Purge(context.Background(), id, cutoff)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
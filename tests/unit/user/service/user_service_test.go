@@ -0,0 +1,390 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	roleService "github.com/kelompok1-swe-academya/caper-be/internal/app/role/service"
+	"github.com/kelompok1-swe-academya/caper-be/internal/app/user/service"
+	"github.com/kelompok1-swe-academya/caper-be/internal/infra/env"
+	bcryptMock "github.com/kelompok1-swe-academya/caper-be/pkg/bcrypt/mock"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/cache"
+	imageProcessorMock "github.com/kelompok1-swe-academya/caper-be/pkg/image_processor/mock"
+	storagePkg "github.com/kelompok1-swe-academya/caper-be/pkg/storage"
+	storageMock "github.com/kelompok1-swe-academya/caper-be/pkg/storage/mock"
+	timeMock "github.com/kelompok1-swe-academya/caper-be/pkg/time/mock"
+	uuidPkg "github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
+	emailChangeMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/email_change/repository/mock"
+	emailVerificationMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/email_verification/repository/mock"
+	loginThrottleMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/login_throttle/repository/mock"
+	mfaMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/mfa/repository/mock"
+	roleMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/role/repository/mock"
+	sessionMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/session/repository/mock"
+	userMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/user/repository/mock"
+)
+
+var (
+	actorID = uuid.MustParse("0192b7a4-5c1e-7d3a-9f21-6b8e4c2d1a07")
+	userID  = uuid.MustParse("0192b7a4-6d2f-7e4b-8a32-7c9f5d3e2b18")
+
+	roles = []entity.Role{
+		{ID: 1, Name: entity.RoleSuperAdmin},
+		{ID: 2, Name: entity.RoleAdmin},
+		{ID: 3, Name: entity.RoleModerator},
+		{ID: 4, Name: entity.RoleMember},
+	}
+)
+
+// expectHierarchy lets every role imply itself and the roles below it, so an
+// admin covers other admins, moderators and members but not superadmins
+func expectHierarchy(roleRepo *roleMock.MockRoleRepository) {
+	roleRepo.EXPECT().
+		FindImpliedRoles(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, name string) ([]entity.Role, error) {
+			for i, role := range roles {
+				if role.Name == name {
+					return roles[i:], nil
+				}
+			}
+
+			return nil, nil
+		}).
+		AnyTimes()
+
+	roleRepo.EXPECT().
+		FindPermissionsByRoleName(gomock.Any(), gomock.Any()).
+		Return(nil, nil).
+		AnyTimes()
+}
+
+func findRole(roleRepo *roleMock.MockRoleRepository, id int) {
+	roleRepo.EXPECT().FindByID(gomock.Any(), id).Return(roles[id-1], nil)
+}
+
+func managedUser(roleName string) entity.User {
+	user := entity.User{ID: userID, Name: "Jane Doe", Email: "jane@example.com"}
+	for _, role := range roles {
+		if role.Name == roleName {
+			user.RoleID = role.ID
+			user.Role = role
+		}
+	}
+
+	return user
+}
+
+func TestUserService_UpdateUser_Hierarchy(t *testing.T) {
+	name := "Jane Roe"
+
+	tests := []struct {
+		name      string
+		id        uuid.UUID
+		userRole  string
+		newRoleID int
+		wantErr   error
+	}{
+		{
+			name:     "member",
+			id:       userID,
+			userRole: entity.RoleMember,
+		},
+		{
+			name:     "other admin",
+			id:       userID,
+			userRole: entity.RoleAdmin,
+		},
+		{
+			name:     "superadmin",
+			id:       userID,
+			userRole: entity.RoleSuperAdmin,
+			wantErr:  domain.ErrRoleCantAccessResource,
+		},
+		{
+			name:      "promote to admin",
+			id:        userID,
+			userRole:  entity.RoleMember,
+			newRoleID: 2,
+		},
+		{
+			// nothing is written, not even the name that was allowed
+			name:      "promote to superadmin",
+			id:        userID,
+			userRole:  entity.RoleMember,
+			newRoleID: 1,
+			wantErr:   domain.ErrRoleCantAccessResource,
+		},
+		{
+			name:      "own role",
+			id:        actorID,
+			userRole:  entity.RoleMember,
+			newRoleID: 3,
+			wantErr:   domain.ErrCantManageOwnAccount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			userRepo := userMock.NewMockUserRepository(ctrl)
+			roleRepo := roleMock.NewMockRoleRepository(ctrl)
+			sessionService := sessionMock.NewMockSessionService(ctrl)
+			expectHierarchy(roleRepo)
+
+			user := managedUser(tt.userRole)
+			user.ID = tt.id
+			req := dto.UpdateUserRequest{Name: &name}
+			if tt.newRoleID != 0 {
+				req.RoleID = &tt.newRoleID
+			}
+
+			userRepo.EXPECT().FindByID(gomock.Any(), tt.id).Return(user, nil)
+
+			if tt.newRoleID != 0 && tt.id != actorID {
+				findRole(roleRepo, tt.newRoleID)
+			}
+
+			if tt.wantErr == nil {
+				userRepo.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).Return(nil)
+				userRepo.EXPECT().FindByID(gomock.Any(), tt.id).Return(user, nil)
+
+				if tt.newRoleID != 0 {
+					userRepo.EXPECT().UpdateRole(gomock.Any(), tt.id, tt.newRoleID, gomock.Any()).Return(nil)
+					sessionService.EXPECT().RevokeUserSessions(gomock.Any(), tt.id).Return(nil)
+				}
+			}
+
+			userService := service.NewUserService(
+				userRepo,
+				userMock.NewMockUserExportRepository(ctrl),
+				roleRepo,
+				sessionService,
+				mfaMock.NewMockMFAService(ctrl),
+				loginThrottleMock.NewMockLoginThrottleService(ctrl),
+				emailVerificationMock.NewMockEmailVerificationService(ctrl),
+				emailChangeMock.NewMockEmailChangeService(ctrl),
+				roleService.NewRoleService(roleRepo, userRepo, validator.Validator, cache.NewMemoryCache(10)),
+				validator.Validator,
+				uuidPkg.UUID,
+				bcryptMock.NewMockBcryptInterface(ctrl),
+				storageMock.NewMockStorageInterface(ctrl),
+				imageProcessorMock.NewMockImageProcessorInterface(ctrl),
+				timeMock.NewMockTimeInterface(ctrl),
+			)
+
+			_, err := userService.UpdateUser(context.Background(), actorID, entity.RoleAdmin, tt.id, req)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestUserService_DeleteUser_Hierarchy(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       uuid.UUID
+		userRole string
+		wantErr  error
+	}{
+		{
+			name:     "moderator",
+			id:       userID,
+			userRole: entity.RoleModerator,
+		},
+		{
+			name:     "other admin",
+			id:       userID,
+			userRole: entity.RoleAdmin,
+		},
+		{
+			name:     "superadmin",
+			id:       userID,
+			userRole: entity.RoleSuperAdmin,
+			wantErr:  domain.ErrRoleCantAccessResource,
+		},
+		{
+			name:     "own account",
+			id:       actorID,
+			userRole: entity.RoleAdmin,
+			wantErr:  domain.ErrCantManageOwnAccount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			userRepo := userMock.NewMockUserRepository(ctrl)
+			roleRepo := roleMock.NewMockRoleRepository(ctrl)
+			sessionService := sessionMock.NewMockSessionService(ctrl)
+			expectHierarchy(roleRepo)
+
+			if tt.id != actorID {
+				userRepo.EXPECT().FindByID(gomock.Any(), tt.id).Return(managedUser(tt.userRole), nil)
+			}
+
+			if tt.wantErr == nil {
+				userRepo.EXPECT().SoftDelete(gomock.Any(), tt.id).Return(nil)
+				sessionService.EXPECT().RevokeUserSessions(gomock.Any(), tt.id).Return(nil)
+			}
+
+			userService := service.NewUserService(
+				userRepo,
+				userMock.NewMockUserExportRepository(ctrl),
+				roleRepo,
+				sessionService,
+				mfaMock.NewMockMFAService(ctrl),
+				loginThrottleMock.NewMockLoginThrottleService(ctrl),
+				emailVerificationMock.NewMockEmailVerificationService(ctrl),
+				emailChangeMock.NewMockEmailChangeService(ctrl),
+				roleService.NewRoleService(roleRepo, userRepo, validator.Validator, cache.NewMemoryCache(10)),
+				validator.Validator,
+				uuidPkg.UUID,
+				bcryptMock.NewMockBcryptInterface(ctrl),
+				storageMock.NewMockStorageInterface(ctrl),
+				imageProcessorMock.NewMockImageProcessorInterface(ctrl),
+				timeMock.NewMockTimeInterface(ctrl),
+			)
+
+			err := userService.DeleteUser(context.Background(), actorID, entity.RoleAdmin, tt.id)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+// a role above the actor is refused before anything is hashed or stored
+func TestUserService_CreateUser_Hierarchy(t *testing.T) {
+	tests := []struct {
+		name    string
+		roleID  int
+		wantErr error
+	}{
+		{
+			name:    "superadmin",
+			roleID:  1,
+			wantErr: domain.ErrRoleCantAccessResource,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			userRepo := userMock.NewMockUserRepository(ctrl)
+			roleRepo := roleMock.NewMockRoleRepository(ctrl)
+			expectHierarchy(roleRepo)
+			findRole(roleRepo, tt.roleID)
+
+			userService := service.NewUserService(
+				userRepo,
+				userMock.NewMockUserExportRepository(ctrl),
+				roleRepo,
+				sessionMock.NewMockSessionService(ctrl),
+				mfaMock.NewMockMFAService(ctrl),
+				loginThrottleMock.NewMockLoginThrottleService(ctrl),
+				emailVerificationMock.NewMockEmailVerificationService(ctrl),
+				emailChangeMock.NewMockEmailChangeService(ctrl),
+				roleService.NewRoleService(roleRepo, userRepo, validator.Validator, cache.NewMemoryCache(10)),
+				validator.Validator,
+				uuidPkg.UUID,
+				bcryptMock.NewMockBcryptInterface(ctrl),
+				storageMock.NewMockStorageInterface(ctrl),
+				imageProcessorMock.NewMockImageProcessorInterface(ctrl),
+				timeMock.NewMockTimeInterface(ctrl),
+			)
+
+			_, err := userService.CreateUser(context.Background(), entity.RoleAdmin, dto.CreateUserRequest{
+				Name:     "Jane Doe",
+				Email:    "jane@example.com",
+				Password: "correct horse battery",
+				RoleID:   tt.roleID,
+			})
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+// accounts are only erased once the grace period is over, and not at all
+// while it is unset
+func TestUserService_PurgeDeletedUsers(t *testing.T) {
+	gracePeriod := env.AppEnv.AccountDeletionGracePeriod
+	t.Cleanup(func() {
+		env.AppEnv.AccountDeletionGracePeriod = gracePeriod
+	})
+
+	now := time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC)
+	restoredID := uuid.MustParse("0192b7a4-7e30-7f5c-9b43-8d0a6e4f3c29")
+
+	tests := []struct {
+		name        string
+		gracePeriod time.Duration
+	}{
+		{
+			name:        "grace period",
+			gracePeriod: 30 * 24 * time.Hour,
+		},
+		{
+			name:        "no grace period keeps every account",
+			gracePeriod: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env.AppEnv.AccountDeletionGracePeriod = tt.gracePeriod
+
+			ctrl := gomock.NewController(t)
+			userRepo := userMock.NewMockUserRepository(ctrl)
+			storage := storageMock.NewMockStorageInterface(ctrl)
+			clock := timeMock.NewMockTimeInterface(ctrl)
+			clock.EXPECT().Now().Return(now).AnyTimes()
+
+			if tt.gracePeriod > 0 {
+				cutoff := now.Add(-tt.gracePeriod)
+				deleted := managedUser(entity.RoleMember)
+				deleted.AvatarKey = sql.NullString{String: "avatars/" + userID.String(), Valid: true}
+
+				userRepo.EXPECT().
+					FindDeletedBefore(gomock.Any(), cutoff, gomock.Any()).
+					Return([]entity.User{deleted, {ID: restoredID}}, nil)
+
+				// a thumbnail that is already gone doesn't stop the purge
+				storage.EXPECT().Delete(gomock.Any(), deleted.AvatarKey.String+"/64.jpg").Return(nil)
+				storage.EXPECT().Delete(gomock.Any(), deleted.AvatarKey.String+"/128.jpg").Return(storagePkg.ErrObjectNotFound)
+				storage.EXPECT().Delete(gomock.Any(), deleted.AvatarKey.String+"/256.jpg").Return(nil)
+
+				userRepo.EXPECT().Purge(gomock.Any(), userID, cutoff).Return(nil)
+				// restored after it was listed, the repository skips it
+				userRepo.EXPECT().Purge(gomock.Any(), restoredID, cutoff).Return(domain.ErrUserNotFound)
+			}
+
+			roleRepo := roleMock.NewMockRoleRepository(ctrl)
+			userService := service.NewUserService(
+				userRepo,
+				userMock.NewMockUserExportRepository(ctrl),
+				roleRepo,
+				sessionMock.NewMockSessionService(ctrl),
+				mfaMock.NewMockMFAService(ctrl),
+				loginThrottleMock.NewMockLoginThrottleService(ctrl),
+				emailVerificationMock.NewMockEmailVerificationService(ctrl),
+				emailChangeMock.NewMockEmailChangeService(ctrl),
+				roleService.NewRoleService(roleRepo, userRepo, validator.Validator, cache.NewMemoryCache(10)),
+				validator.Validator,
+				uuidPkg.UUID,
+				bcryptMock.NewMockBcryptInterface(ctrl),
+				storage,
+				imageProcessorMock.NewMockImageProcessorInterface(ctrl),
+				clock,
+			)
+
+			err := userService.PurgeDeletedUsers(context.Background())
+			assert.NoError(t, err)
+		})
+	}
+}
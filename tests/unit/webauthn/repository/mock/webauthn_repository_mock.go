@@ -0,0 +1,258 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/contracts/webauthn_contracts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=domain/contracts/webauthn_contracts.go -destination=tests/unit/webauthn/repository/mock/webauthn_repository_mock.go -package=repository_mock
+//
+
+// Package repository_mock is a generated GoMock package.
+package repository_mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	dto "github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	entity "github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWebauthnRepository is a mock of WebauthnRepository interface.
+type MockWebauthnRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebauthnRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockWebauthnRepositoryMockRecorder is the mock recorder for MockWebauthnRepository.
+type MockWebauthnRepositoryMockRecorder struct {
+	mock *MockWebauthnRepository
+}
+
+// NewMockWebauthnRepository creates a new mock instance.
+func NewMockWebauthnRepository(ctrl *gomock.Controller) *MockWebauthnRepository {
+	mock := &MockWebauthnRepository{ctrl: ctrl}
+	mock.recorder = &MockWebauthnRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebauthnRepository) EXPECT() *MockWebauthnRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ConsumeSession mocks base method.
+func (m *MockWebauthnRepository) ConsumeSession(ctx context.Context, id uuid.UUID, ceremony string, now time.Time) (entity.WebauthnSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeSession", ctx, id, ceremony, now)
+	ret0, _ := ret[0].(entity.WebauthnSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeSession indicates an expected call of ConsumeSession.
+func (mr *MockWebauthnRepositoryMockRecorder) ConsumeSession(ctx, id, ceremony, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeSession", reflect.TypeOf((*MockWebauthnRepository)(nil).ConsumeSession), ctx, id, ceremony, now)
+}
+
+// CreateCredential mocks base method.
+func (m *MockWebauthnRepository) CreateCredential(ctx context.Context, credential *entity.WebauthnCredential) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCredential", ctx, credential)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCredential indicates an expected call of CreateCredential.
+func (mr *MockWebauthnRepositoryMockRecorder) CreateCredential(ctx, credential any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCredential", reflect.TypeOf((*MockWebauthnRepository)(nil).CreateCredential), ctx, credential)
+}
+
+// CreateSession mocks base method.
+func (m *MockWebauthnRepository) CreateSession(ctx context.Context, session *entity.WebauthnSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", ctx, session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockWebauthnRepositoryMockRecorder) CreateSession(ctx, session any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockWebauthnRepository)(nil).CreateSession), ctx, session)
+}
+
+// DeleteCredential mocks base method.
+func (m *MockWebauthnRepository) DeleteCredential(ctx context.Context, userID, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCredential", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCredential indicates an expected call of DeleteCredential.
+func (mr *MockWebauthnRepositoryMockRecorder) DeleteCredential(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCredential", reflect.TypeOf((*MockWebauthnRepository)(nil).DeleteCredential), ctx, userID, id)
+}
+
+// DeleteExpiredSessions mocks base method.
+func (m *MockWebauthnRepository) DeleteExpiredSessions(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredSessions", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredSessions indicates an expected call of DeleteExpiredSessions.
+func (mr *MockWebauthnRepositoryMockRecorder) DeleteExpiredSessions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredSessions", reflect.TypeOf((*MockWebauthnRepository)(nil).DeleteExpiredSessions), ctx)
+}
+
+// FindCredentialsByUserID mocks base method.
+func (m *MockWebauthnRepository) FindCredentialsByUserID(ctx context.Context, userID uuid.UUID) ([]entity.WebauthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCredentialsByUserID", ctx, userID)
+	ret0, _ := ret[0].([]entity.WebauthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCredentialsByUserID indicates an expected call of FindCredentialsByUserID.
+func (mr *MockWebauthnRepositoryMockRecorder) FindCredentialsByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCredentialsByUserID", reflect.TypeOf((*MockWebauthnRepository)(nil).FindCredentialsByUserID), ctx, userID)
+}
+
+// UpdateSignCount mocks base method.
+func (m *MockWebauthnRepository) UpdateSignCount(ctx context.Context, id uuid.UUID, signCount int64, backupState bool, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSignCount", ctx, id, signCount, backupState, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSignCount indicates an expected call of UpdateSignCount.
+func (mr *MockWebauthnRepositoryMockRecorder) UpdateSignCount(ctx, id, signCount, backupState, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSignCount", reflect.TypeOf((*MockWebauthnRepository)(nil).UpdateSignCount), ctx, id, signCount, backupState, now)
+}
+
+// MockWebauthnService is a mock of WebauthnService interface.
+type MockWebauthnService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebauthnServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockWebauthnServiceMockRecorder is the mock recorder for MockWebauthnService.
+type MockWebauthnServiceMockRecorder struct {
+	mock *MockWebauthnService
+}
+
+// NewMockWebauthnService creates a new mock instance.
+func NewMockWebauthnService(ctrl *gomock.Controller) *MockWebauthnService {
+	mock := &MockWebauthnService{ctrl: ctrl}
+	mock.recorder = &MockWebauthnServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebauthnService) EXPECT() *MockWebauthnServiceMockRecorder {
+	return m.recorder
+}
+
+// BeginLogin mocks base method.
+func (m *MockWebauthnService) BeginLogin(ctx context.Context) (dto.PasskeyOptionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginLogin", ctx)
+	ret0, _ := ret[0].(dto.PasskeyOptionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginLogin indicates an expected call of BeginLogin.
+func (mr *MockWebauthnServiceMockRecorder) BeginLogin(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginLogin", reflect.TypeOf((*MockWebauthnService)(nil).BeginLogin), ctx)
+}
+
+// BeginRegistration mocks base method.
+func (m *MockWebauthnService) BeginRegistration(ctx context.Context, userID uuid.UUID, req dto.ReauthRequest) (dto.PasskeyOptionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginRegistration", ctx, userID, req)
+	ret0, _ := ret[0].(dto.PasskeyOptionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginRegistration indicates an expected call of BeginRegistration.
+func (mr *MockWebauthnServiceMockRecorder) BeginRegistration(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginRegistration", reflect.TypeOf((*MockWebauthnService)(nil).BeginRegistration), ctx, userID, req)
+}
+
+// Delete mocks base method.
+func (m *MockWebauthnService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebauthnServiceMockRecorder) Delete(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebauthnService)(nil).Delete), ctx, userID, id)
+}
+
+// FinishLogin mocks base method.
+func (m *MockWebauthnService) FinishLogin(ctx context.Context, req dto.PasskeyLoginRequest) (dto.LoginResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishLogin", ctx, req)
+	ret0, _ := ret[0].(dto.LoginResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishLogin indicates an expected call of FinishLogin.
+func (mr *MockWebauthnServiceMockRecorder) FinishLogin(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishLogin", reflect.TypeOf((*MockWebauthnService)(nil).FinishLogin), ctx, req)
+}
+
+// FinishRegistration mocks base method.
+func (m *MockWebauthnService) FinishRegistration(ctx context.Context, userID uuid.UUID, req dto.PasskeyRegisterRequest) (dto.PasskeyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishRegistration", ctx, userID, req)
+	ret0, _ := ret[0].(dto.PasskeyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishRegistration indicates an expected call of FinishRegistration.
+func (mr *MockWebauthnServiceMockRecorder) FinishRegistration(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishRegistration", reflect.TypeOf((*MockWebauthnService)(nil).FinishRegistration), ctx, userID, req)
+}
+
+// List mocks base method.
+func (m *MockWebauthnService) List(ctx context.Context, userID uuid.UUID) ([]dto.PasskeyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].([]dto.PasskeyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWebauthnServiceMockRecorder) List(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWebauthnService)(nil).List), ctx, userID)
+}
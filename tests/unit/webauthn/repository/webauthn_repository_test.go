@@ -0,0 +1,89 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/contracts"
+	"github.com/kelompok1-swe-academya/caper-be/internal/app/webauthn/repository"
+)
+
+var errConnectionReset = errors.New("connection reset")
+
+const updateSignCountQuery = `UPDATE webauthn_credentials SET sign_count = $2, backup_state = $3, last_used_at = $4 WHERE id = $1 AND (sign_count < $2 OR $2 = 0)`
+
+func newWebauthnRepository(t *testing.T) (contracts.WebauthnRepository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+		db.Close()
+	})
+
+	return repository.NewWebauthnRepository(sqlx.NewDb(db, "pgx")), mock
+}
+
+func TestWebauthnRepository_UpdateSignCount(t *testing.T) {
+	id := uuid.New()
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		signCount int64
+		rows      int64
+		execErr   error
+		wantErr   error
+	}{
+		{
+			name:      "counter moves forward",
+			signCount: 6,
+			rows:      1,
+		},
+		{
+			name:      "authenticator without a counter",
+			signCount: 0,
+			rows:      1,
+		},
+		{
+			// a concurrent login already stored this counter or a higher one
+			name:      "counter already used",
+			signCount: 6,
+			rows:      0,
+			wantErr:   domain.ErrPasskeyVerificationFailed,
+		},
+		{
+			name:      "database error",
+			signCount: 6,
+			execErr:   errConnectionReset,
+			wantErr:   errConnectionReset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mock := newWebauthnRepository(t)
+
+			exec := mock.ExpectExec(updateSignCountQuery).WithArgs(id, tt.signCount, true, now)
+			if tt.execErr != nil {
+				exec.WillReturnError(tt.execErr)
+			} else {
+				exec.WillReturnResult(sqlmock.NewResult(0, tt.rows))
+			}
+
+			err := repo.UpdateSignCount(context.Background(), id, tt.signCount, true, now)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package service_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	rpID     = "localhost"
+	rpOrigin = "http://localhost:3000"
+
+	flagUserPresent  byte = 0x01
+	flagUserVerified byte = 0x04
+	flagAttestedData byte = 0x40
+)
+
+// authenticator is a software passkey holding a single P-256 key, it answers
+// ceremonies the way a browser would hand them back to the server. Without
+// counting it reports a sign count of zero like many platform authenticators.
+type authenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	signCount    uint32
+	withoutCount bool
+}
+
+func newAuthenticator(t *testing.T) *authenticator {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	credentialID := make([]byte, 16)
+	_, err = rand.Read(credentialID)
+	require.NoError(t, err)
+
+	return &authenticator{
+		key:          key,
+		credentialID: credentialID,
+	}
+}
+
+// publicKey is the COSE encoded key the server stores for the credential
+func (a *authenticator) publicKey(t *testing.T) []byte {
+	t.Helper()
+
+	ecdhKey, err := a.key.PublicKey.ECDH()
+	require.NoError(t, err)
+
+	point := ecdhKey.Bytes()
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  1,
+		XCoord: point[1:33],
+		YCoord: point[33:],
+	})
+	require.NoError(t, err)
+
+	return publicKey
+}
+
+// register answers navigator.credentials.create with a none attestation
+func (a *authenticator) register(t *testing.T, options json.RawMessage) json.RawMessage {
+	t.Helper()
+
+	var creation protocol.CredentialCreation
+	require.NoError(t, json.Unmarshal(options, &creation))
+
+	clientData := a.clientData(t, protocol.CreateCeremony, creation.Response.Challenge)
+
+	attested := make([]byte, 16)
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(a.credentialID)))
+	attested = append(attested, a.credentialID...)
+	attested = append(attested, a.publicKey(t)...)
+
+	attestationObject, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authData(flagUserPresent|flagUserVerified|flagAttestedData, attested),
+	})
+	require.NoError(t, err)
+
+	return a.credential(t, map[string]any{
+		"clientDataJSON":    encode(clientData),
+		"attestationObject": encode(attestationObject),
+	})
+}
+
+// login answers navigator.credentials.get, a counting authenticator moves its
+// sign count up first
+func (a *authenticator) login(t *testing.T, options json.RawMessage, userHandle []byte) json.RawMessage {
+	t.Helper()
+
+	var assertion protocol.CredentialAssertion
+	require.NoError(t, json.Unmarshal(options, &assertion))
+
+	if !a.withoutCount {
+		a.signCount++
+	}
+
+	clientData := a.clientData(t, protocol.AssertCeremony, assertion.Response.Challenge)
+	authData := a.authData(flagUserPresent|flagUserVerified, nil)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(t, err)
+
+	return a.credential(t, map[string]any{
+		"clientDataJSON":    encode(clientData),
+		"authenticatorData": encode(authData),
+		"signature":         encode(signature),
+		"userHandle":        encode(userHandle),
+	})
+}
+
+func (a *authenticator) clientData(t *testing.T, ceremony protocol.CeremonyType, challenge []byte) []byte {
+	t.Helper()
+
+	clientData, err := json.Marshal(map[string]any{
+		"type":      ceremony,
+		"challenge": encode(challenge),
+		"origin":    rpOrigin,
+	})
+	require.NoError(t, err)
+
+	return clientData
+}
+
+func (a *authenticator) authData(flags byte, attested []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+
+	authData := append([]byte{}, rpIDHash[:]...)
+	authData = append(authData, flags)
+	authData = binary.BigEndian.AppendUint32(authData, a.signCount)
+
+	return append(authData, attested...)
+}
+
+func (a *authenticator) credential(t *testing.T, response map[string]any) json.RawMessage {
+	t.Helper()
+
+	credential, err := json.Marshal(map[string]any{
+		"id":       encode(a.credentialID),
+		"rawId":    encode(a.credentialID),
+		"type":     "public-key",
+		"response": response,
+	})
+	require.NoError(t, err)
+
+	return credential
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,447 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kelompok1-swe-academya/caper-be/domain"
+	"github.com/kelompok1-swe-academya/caper-be/domain/dto"
+	"github.com/kelompok1-swe-academya/caper-be/domain/entity"
+	"github.com/kelompok1-swe-academya/caper-be/internal/app/webauthn/service"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/jwt"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/mail"
+	timePkg "github.com/kelompok1-swe-academya/caper-be/pkg/time"
+	uuidPkg "github.com/kelompok1-swe-academya/caper-be/pkg/uuid"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/validator"
+	"github.com/kelompok1-swe-academya/caper-be/pkg/webauthn"
+	mailMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/mail/repository/mock"
+	mfaMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/mfa/repository/mock"
+	sessionMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/session/repository/mock"
+	userMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/user/repository/mock"
+	webauthnMock "github.com/kelompok1-swe-academya/caper-be/tests/unit/webauthn/repository/mock"
+)
+
+var user = entity.User{
+	ID:     uuid.MustParse("0192b7a4-5c1e-7d3a-9f21-6b8e4c2d1a07"),
+	Name:   "Jane Doe",
+	Email:  "jane@example.com",
+	Locale: "id",
+}
+
+var reauth = dto.ReauthRequest{
+	CurrentPassword: "password",
+	IPAddress:       "203.0.113.7",
+}
+
+// storeCeremonies keeps ceremonies in memory so a Begin call can be followed
+// by its Finish, a ceremony is gone once it has been consumed
+func storeCeremonies(webauthnRepo *webauthnMock.MockWebauthnRepository) {
+	sessions := make(map[uuid.UUID]entity.WebauthnSession)
+
+	webauthnRepo.EXPECT().
+		CreateSession(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, session *entity.WebauthnSession) error {
+			sessions[session.ID] = *session
+			return nil
+		}).
+		AnyTimes()
+
+	webauthnRepo.EXPECT().
+		ConsumeSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, id uuid.UUID, ceremony string, _ time.Time) (entity.WebauthnSession, error) {
+			session, ok := sessions[id]
+			if !ok || session.Ceremony != ceremony {
+				return entity.WebauthnSession{}, domain.ErrInvalidPasskeyCeremony
+			}
+
+			delete(sessions, id)
+			return session, nil
+		}).
+		AnyTimes()
+}
+
+func newRelyingParty(t *testing.T) webauthn.WebauthnInterface {
+	t.Helper()
+
+	rp, err := webauthn.NewWebauthn(rpID, "Caper", []string{rpOrigin})
+	require.NoError(t, err)
+
+	return rp
+}
+
+func storedPasskey(t *testing.T, auth *authenticator) entity.WebauthnCredential {
+	t.Helper()
+
+	return entity.WebauthnCredential{
+		ID:              uuid.New(),
+		UserID:          user.ID,
+		Name:            "Laptop",
+		CredentialID:    auth.credentialID,
+		PublicKey:       auth.publicKey(t),
+		AttestationType: "none",
+		AAGUID:          make([]byte, 16),
+		SignCount:       int64(auth.signCount),
+	}
+}
+
+func TestWebauthnService_BeginRegistration(t *testing.T) {
+	tests := []struct {
+		name      string
+		reauthErr error
+		wantErr   error
+	}{
+		{
+			name: "reauthenticated",
+		},
+		{
+			name:      "wrong password",
+			reauthErr: domain.ErrCredentialsNotMatch,
+			wantErr:   domain.ErrCredentialsNotMatch,
+		},
+		{
+			name:      "locked out",
+			reauthErr: domain.ErrTooManyLoginAttempts,
+			wantErr:   domain.ErrTooManyLoginAttempts,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			webauthnRepo := webauthnMock.NewMockWebauthnRepository(ctrl)
+			userRepo := userMock.NewMockUserRepository(ctrl)
+			mfaService := mfaMock.NewMockMFAService(ctrl)
+
+			webauthnService := service.NewWebauthnService(
+				webauthnRepo,
+				userRepo,
+				sessionMock.NewMockSessionService(ctrl),
+				mfaService,
+				mailMock.NewMockMailService(ctrl),
+				validator.Validator,
+				uuidPkg.UUID,
+				newRelyingParty(t),
+				timePkg.Time,
+			)
+
+			mfaService.EXPECT().Reauthenticate(gomock.Any(), user.ID, reauth).Return(tt.reauthErr)
+			if tt.reauthErr == nil {
+				userRepo.EXPECT().FindByID(gomock.Any(), user.ID).Return(user, nil)
+				webauthnRepo.EXPECT().FindCredentialsByUserID(gomock.Any(), user.ID).Return(nil, nil)
+				webauthnRepo.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			res, err := webauthnService.BeginRegistration(context.Background(), user.ID, reauth)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+
+			var creation struct {
+				PublicKey struct {
+					User struct {
+						ID   string `json:"id"`
+						Name string `json:"name"`
+					} `json:"user"`
+				} `json:"publicKey"`
+			}
+			require.NoError(t, json.Unmarshal(res.Options, &creation))
+			assert.Equal(t, encode(user.ID[:]), creation.PublicKey.User.ID)
+			assert.Equal(t, user.Email, creation.PublicKey.User.Name)
+		})
+	}
+}
+
+func TestWebauthnService_FinishRegistration(t *testing.T) {
+	tests := []struct {
+		name string
+		// finishAs is the user finishing the ceremony, user started it
+		finishAs uuid.UUID
+		replay   bool
+		mailErr  error
+		wantErr  error
+	}{
+		{
+			name:     "registered",
+			finishAs: user.ID,
+		},
+		{
+			// the passkey is stored already, a failing mail can't undo that
+			name:     "mail failure",
+			finishAs: user.ID,
+			mailErr:  errors.New("outbox unavailable"),
+		},
+		{
+			name:     "other user's ceremony",
+			finishAs: uuid.New(),
+			wantErr:  domain.ErrInvalidPasskeyCeremony,
+		},
+		{
+			name:     "replayed ceremony",
+			finishAs: user.ID,
+			replay:   true,
+			wantErr:  domain.ErrInvalidPasskeyCeremony,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			webauthnRepo := webauthnMock.NewMockWebauthnRepository(ctrl)
+			userRepo := userMock.NewMockUserRepository(ctrl)
+			mfaService := mfaMock.NewMockMFAService(ctrl)
+			mailService := mailMock.NewMockMailService(ctrl)
+
+			webauthnService := service.NewWebauthnService(
+				webauthnRepo,
+				userRepo,
+				sessionMock.NewMockSessionService(ctrl),
+				mfaService,
+				mailService,
+				validator.Validator,
+				uuidPkg.UUID,
+				newRelyingParty(t),
+				timePkg.Time,
+			)
+
+			storeCeremonies(webauthnRepo)
+			mfaService.EXPECT().Reauthenticate(gomock.Any(), user.ID, reauth).Return(nil)
+			userRepo.EXPECT().FindByID(gomock.Any(), user.ID).Return(user, nil).AnyTimes()
+			webauthnRepo.EXPECT().FindCredentialsByUserID(gomock.Any(), user.ID).Return(nil, nil).AnyTimes()
+
+			var stored entity.WebauthnCredential
+			if tt.finishAs == user.ID {
+				webauthnRepo.EXPECT().
+					CreateCredential(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, credential *entity.WebauthnCredential) error {
+						stored = *credential
+						return nil
+					})
+				mailService.EXPECT().
+					Queue(gomock.Any(), user.Email, user.Locale, mail.TemplatePasskeyAdded, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, _ string, _ string, data any) error {
+						added, ok := data.(mail.PasskeyAddedData)
+						assert.True(t, ok)
+						assert.Equal(t, user.Name, added.Name)
+						assert.Equal(t, "Laptop", added.PasskeyName)
+						assert.Contains(t, added.Link, "/settings/security")
+						return tt.mailErr
+					})
+			}
+
+			options, err := webauthnService.BeginRegistration(context.Background(), user.ID, reauth)
+			require.NoError(t, err)
+
+			auth := newAuthenticator(t)
+			req := dto.PasskeyRegisterRequest{
+				CeremonyID: options.CeremonyID,
+				Name:       "  Laptop  ",
+				Credential: auth.register(t, options.Options),
+			}
+
+			res, err := webauthnService.FinishRegistration(context.Background(), tt.finishAs, req)
+			if tt.replay {
+				require.NoError(t, err)
+				_, err = webauthnService.FinishRegistration(context.Background(), tt.finishAs, req)
+			}
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+
+			assert.Equal(t, stored.ID, res.ID)
+			assert.Equal(t, "Laptop", res.Name)
+			assert.Equal(t, user.ID, stored.UserID)
+			assert.Equal(t, auth.credentialID, stored.CredentialID)
+			assert.NotEmpty(t, stored.PublicKey)
+			assert.Equal(t, int64(0), stored.SignCount)
+		})
+	}
+}
+
+func TestWebauthnService_FinishLogin(t *testing.T) {
+	tests := []struct {
+		name string
+		// stored is the sign count kept for the passkey, signCount the one
+		// the authenticator answers with before it counts this login
+		stored       uint32
+		signCount    uint32
+		withoutCount bool
+		otherKey     bool
+		otherPasskey bool
+		userErr      error
+		updateErr    error
+		replay       bool
+		wantCount    int64
+		wantErr      error
+	}{
+		{
+			name:      "counter moves forward",
+			stored:    4,
+			signCount: 4,
+			wantCount: 5,
+		},
+		{
+			// authenticators that don't count always report zero, they
+			// can't be told apart from a clone and are let through
+			name:         "authenticator without a counter",
+			withoutCount: true,
+			wantCount:    0,
+		},
+		{
+			// the clone answers with a counter behind the one stored
+			name:      "sign count regression",
+			stored:    10,
+			signCount: 6,
+			wantErr:   domain.ErrPasskeyVerificationFailed,
+		},
+		{
+			// two logins racing with the same counter pass verification,
+			// the repository only lets the first one move the counter
+			name:      "counter already used",
+			wantCount: 1,
+			updateErr: domain.ErrPasskeyVerificationFailed,
+			wantErr:   domain.ErrPasskeyVerificationFailed,
+		},
+		{
+			name:         "unknown credential",
+			otherPasskey: true,
+			wantErr:      domain.ErrPasskeyVerificationFailed,
+		},
+		{
+			name:    "unknown user",
+			userErr: domain.ErrUserNotFound,
+			wantErr: domain.ErrPasskeyVerificationFailed,
+		},
+		{
+			name:     "wrong key",
+			otherKey: true,
+			wantErr:  domain.ErrPasskeyVerificationFailed,
+		},
+		{
+			// the ceremony is consumed by the first attempt, so a captured
+			// response can't be replayed
+			name:      "replayed response",
+			replay:    true,
+			wantCount: 1,
+			wantErr:   domain.ErrInvalidPasskeyCeremony,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			webauthnRepo := webauthnMock.NewMockWebauthnRepository(ctrl)
+			userRepo := userMock.NewMockUserRepository(ctrl)
+			sessionService := sessionMock.NewMockSessionService(ctrl)
+
+			webauthnService := service.NewWebauthnService(
+				webauthnRepo,
+				userRepo,
+				sessionService,
+				mfaMock.NewMockMFAService(ctrl),
+				mailMock.NewMockMailService(ctrl),
+				validator.Validator,
+				uuidPkg.UUID,
+				newRelyingParty(t),
+				timePkg.Time,
+			)
+
+			auth := newAuthenticator(t)
+			auth.signCount = tt.stored
+			auth.withoutCount = tt.withoutCount
+
+			passkey := storedPasskey(t, auth)
+			switch {
+			case tt.otherPasskey:
+				passkey = storedPasskey(t, newAuthenticator(t))
+			case tt.otherKey:
+				passkey.PublicKey = newAuthenticator(t).publicKey(t)
+			}
+
+			auth.signCount = tt.signCount
+
+			storeCeremonies(webauthnRepo)
+			if tt.userErr != nil {
+				userRepo.EXPECT().FindByID(gomock.Any(), user.ID).Return(entity.User{}, tt.userErr)
+			} else {
+				userRepo.EXPECT().FindByID(gomock.Any(), user.ID).Return(user, nil).AnyTimes()
+				webauthnRepo.EXPECT().
+					FindCredentialsByUserID(gomock.Any(), user.ID).
+					Return([]entity.WebauthnCredential{passkey}, nil).
+					AnyTimes()
+			}
+
+			token := dto.TokenResponse{AccessToken: "access", RefreshToken: "refresh"}
+			if tt.wantErr == nil || tt.updateErr != nil || tt.replay {
+				webauthnRepo.EXPECT().
+					UpdateSignCount(gomock.Any(), passkey.ID, tt.wantCount, false, gomock.Any()).
+					Return(tt.updateErr)
+			}
+			if tt.wantErr == nil || tt.replay {
+				sessionService.EXPECT().
+					Issue(gomock.Any(), user, []string{jwt.AMRHardwareKey, jwt.AMRMultiFactor}).
+					Return(token, nil)
+			}
+
+			options, err := webauthnService.BeginLogin(context.Background())
+			require.NoError(t, err)
+
+			req := dto.PasskeyLoginRequest{
+				CeremonyID: options.CeremonyID,
+				Credential: auth.login(t, options.Options, user.ID[:]),
+			}
+
+			res, err := webauthnService.FinishLogin(context.Background(), req)
+			if tt.replay {
+				require.NoError(t, err)
+				res, err = webauthnService.FinishLogin(context.Background(), req)
+			}
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+
+			require.NotNil(t, res.TokenResponse)
+			assert.Equal(t, token, *res.TokenResponse)
+			assert.False(t, res.MFARequired)
+		})
+	}
+}
+
+func TestWebauthnFinishLogin_SignCountRegression(t *testing.T) {
+	rp := newRelyingParty(t)
+
+	auth := newAuthenticator(t)
+	auth.signCount = 10
+	webauthnUser := webauthn.User{
+		ID:   []byte("user handle"),
+		Name: "jane@example.com",
+		Credentials: []webauthn.Credential{{
+			ID:              auth.credentialID,
+			PublicKey:       auth.publicKey(t),
+			AttestationType: "none",
+			SignCount:       auth.signCount,
+		}},
+	}
+
+	auth.signCount = 6
+
+	options, session, err := rp.BeginLogin()
+	require.NoError(t, err)
+
+	_, err = rp.FinishLogin(session, auth.login(t, options, webauthnUser.ID), func([]byte) (webauthn.User, error) {
+		return webauthnUser, nil
+	})
+	assert.ErrorIs(t, err, webauthn.ErrCredentialCloned)
+}